@@ -0,0 +1,74 @@
+// Command orders-admin runs operational tasks against the orders service's
+// infrastructure.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/events"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+const usage = `Usage: orders-admin <command> [flags]
+
+Commands:
+  replay-dlq    Republish dead-lettered payment events to the payments topic
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "replay-dlq":
+		err = replayDLQ(ctx, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "orders-admin:", err)
+		os.Exit(1)
+	}
+}
+
+func replayDLQ(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("replay-dlq", flag.ExitOnError)
+	limit := flags.Int("limit", 0, "maximum number of messages to replay (0 for all)")
+	idle := flags.Duration("idle", 10*time.Second, "stop once no message has arrived for this long")
+	dryRun := flags.Bool("dry-run", false, "log the messages that would be replayed without publishing them")
+	flags.Parse(args)
+
+	cfg := config.Load()
+	logger := logging.NewLoggerV2("orders-admin")
+
+	replayer := events.NewDeadLetterReplayer(cfg.Kafka, logger)
+	defer replayer.Close()
+
+	logger.Info("Replaying dead-lettered payment events", logging.Fields{
+		"dlq_topic": cfg.Kafka.PaymentsDLQTopic,
+		"limit":     *limit,
+		"dry_run":   *dryRun,
+	})
+
+	replayed, err := replayer.Replay(ctx, events.ReplayOptions{
+		Limit:  *limit,
+		Idle:   *idle,
+		DryRun: *dryRun,
+	})
+	logger.Info("Replay finished", logging.Fields{"replayed": replayed})
+	return err
+}
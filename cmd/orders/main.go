@@ -3,19 +3,24 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/auth"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/clients"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/events"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/handlers"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/health"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/ratelimit"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/server"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/tracing"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 
 	_ "github.com/lib/pq"
@@ -29,6 +34,11 @@ func main() {
 	// TODO(TEAM-PLATFORM): Migrate all legacy logging to structured logging
 	logging.Infof("Starting orders-service on port %d", cfg.Server.Port)
 
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatal("Failed to initialise tracing", logging.Fields{"error": err.Error()})
+	}
+
 	db, err := initDatabase(cfg)
 	if err != nil {
 		logger.Fatal("Failed to connect to database", logging.Fields{"error": err.Error()})
@@ -48,29 +58,114 @@ func main() {
 	userClient := clients.NewHTTPUserClient(cfg.UserService, logger)
 	notificationClient := clients.NewHTTPNotificationClient(cfg.NotificationService, logger)
 
-	eventPublisher := events.NewKafkaPublisher(cfg.Kafka, logger)
-	defer eventPublisher.Close()
+	eventEncoder, err := events.NewEventEncoder(cfg.Kafka.EventEncoding)
+	if err != nil {
+		logger.Fatal("Invalid event encoding", logging.Fields{"error": err.Error()})
+	}
+	kafkaPublisher := events.NewKafkaPublisher(cfg.Kafka, eventEncoder, logger)
+	defer kafkaPublisher.Close()
 
-	orderService := service.NewOrderService(
-		orderRepo,
-		orderCache,
-		legacyRepo,
-		paymentClient,
-		legacyPaymentClient,
-		userClient,
-		notificationClient,
-		eventPublisher,
-		cfg,
-	)
+	// Order events are written to the outbox with the order change and
+	// relayed to Kafka in the background.
+	txManager := repository.NewTxManager(db, logger)
+	outboxRepo := repository.NewPostgresOutboxRepository(db, logger)
+	eventPublisher := events.NewOutboxPublisher(outboxRepo, logger)
+	outboxRelay := events.NewOutboxRelay(outboxRepo, txManager, kafkaPublisher, cfg.Outbox, logger)
+	outboxPruner := events.NewOutboxPruner(outboxRepo, cfg.Outbox, logger)
+	processedEvents := repository.NewPostgresProcessedEventStore(db, logger)
+	inboxPruner := events.NewInboxPruner(processedEvents, cfg.Inbox, logger)
+	taxLines := repository.NewPostgresTaxLineRepository(db, logger)
+	statusHistory := repository.NewPostgresStatusHistoryRepository(db, logger)
+	shipments := repository.NewPostgresShipmentRepository(db, logger)
+	refunds := repository.NewPostgresRefundRepository(db, logger)
+	returns := repository.NewPostgresReturnRepository(db, logger)
+
+	taxRules, err := service.LoadTaxRules(cfg.Tax.RulesFile)
+	if err != nil {
+		logger.Fatal("Failed to load tax rules", logging.Fields{"error": err.Error()})
+	}
+	taxRounding, err := service.ParseRoundingMode(cfg.Pricing.TaxRounding)
+	if err != nil {
+		logger.Fatal("Invalid tax rounding mode", logging.Fields{"error": err.Error()})
+	}
+	taxCalculator := service.NewRulesTaxCalculator(taxRules, taxRounding)
+
+	lifecycle := service.DefaultOrderLifecycle()
+	if cfg.Lifecycle.File != "" {
+		lifecycle, err = service.LoadOrderLifecycle(cfg.Lifecycle.File)
+		if err != nil {
+			logger.Fatal("Failed to load order lifecycle", logging.Fields{"error": err.Error()})
+		}
+	}
+	logger.Info("Loaded tax rules", logging.Fields{
+		"version":       taxRules.Version,
+		"jurisdictions": len(taxRules.Jurisdictions),
+	})
+
+	orderService := service.NewOrderService(service.OrderServiceDeps{
+		OrderRepo:           orderRepo,
+		OrderCache:          orderCache,
+		LegacyRepo:          legacyRepo,
+		PaymentClient:       paymentClient,
+		LegacyPaymentClient: legacyPaymentClient,
+		UserClient:          userClient,
+		NotificationClient:  notificationClient,
+		Events:              eventPublisher,
+		ProcessedEvents:     processedEvents,
+		TaxLines:            taxLines,
+		StatusHistory:       statusHistory,
+		Shipments:           shipments,
+		Refunds:             refunds,
+		TaxCalculator:       taxCalculator,
+		Lifecycle:           lifecycle,
+		Tx:                  txManager,
+		Config:              cfg,
+	})
 
 	paymentService := service.NewPaymentService(
 		paymentClient,
 		legacyPaymentClient,
 		orderRepo,
+		orderService,
 		cfg,
 	)
 
-	h := handlers.NewHandlers(orderService, paymentService, cfg)
+	returnService := service.NewReturnService(
+		orderService,
+		paymentService,
+		returns,
+		notificationClient,
+		cfg,
+	)
+
+	idempotencyStore := repository.NewPostgresIdempotencyStore(db, logger)
+	idempotencyPruner := events.NewIdempotencyPruner(idempotencyStore, cfg.Idempotency, logger)
+
+	readiness := newReadiness(cfg, db, orderCache, kafkaPublisher, paymentClient, userClient, notificationClient)
+
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		authenticator, err = auth.NewAuthenticator(cfg.Auth)
+		if err != nil {
+			logger.Fatal("Failed to configure authentication", logging.Fields{
+				"error": err.Error(),
+				"fix":   "set AUTH_JWT_SECRETS, AUTH_JWKS_FILE, AUTH_JWKS_URL or AUTH_API_KEYS, or AUTH_ENABLED=false for local development",
+			})
+		}
+	} else {
+		// Only reachable with an explicit AUTH_ENABLED=false, which must
+		// never be set outside local development.
+		logger.Error("AUTHENTICATION IS DISABLED: the v1 and v2 APIs and /debug are open to all callers", logging.Fields{
+			"fix": "unset AUTH_ENABLED and set AUTH_JWT_SECRETS, AUTH_JWKS_FILE, AUTH_JWKS_URL or AUTH_API_KEYS",
+		})
+	}
+
+	limiter, err := newRateLimiter(cfg)
+	if err != nil {
+		logger.Fatal("Failed to configure rate limiting", logging.Fields{"error": err.Error()})
+	}
+
+	h := handlers.NewHandlers(orderService, paymentService, returnService, idempotencyStore, readiness, authenticator, limiter, cfg)
 
 	srv := server.New(h, cfg)
 
@@ -85,10 +180,39 @@ func main() {
 		}
 	}()
 
+	// Background workers run until they are stopped during shutdown;
+	// cancelling runCtx is the last resort for anything still running.
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+
+	go func() {
+		if err := outboxRelay.Start(runCtx); err != nil {
+			logger.Error("Outbox relay failed", logging.Fields{"error": err.Error()})
+		}
+	}()
+
+	go func() {
+		if err := outboxPruner.Start(runCtx); err != nil {
+			logger.Error("Outbox pruner failed", logging.Fields{"error": err.Error()})
+		}
+	}()
+
+	go func() {
+		if err := inboxPruner.Start(runCtx); err != nil {
+			logger.Error("Inbox pruner failed", logging.Fields{"error": err.Error()})
+		}
+	}()
+
+	go func() {
+		if err := idempotencyPruner.Start(runCtx); err != nil {
+			logger.Error("Idempotency pruner failed", logging.Fields{"error": err.Error()})
+		}
+	}()
+
 	// Start event consumer
 	eventConsumer := events.NewKafkaConsumer(cfg.Kafka, orderService, logger)
 	go func() {
-		if err := eventConsumer.Start(context.Background()); err != nil {
+		if err := eventConsumer.Start(runCtx); err != nil {
 			logger.Error("Event consumer failed", logging.Fields{"error": err.Error()})
 		}
 	}()
@@ -102,15 +226,85 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	eventConsumer.Stop()
-
+	// Stop taking requests first, then let the consumer finish the payment
+	// events it has fetched, and relay whatever they wrote to the outbox.
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", logging.Fields{"error": err.Error()})
 	}
 
+	drainCtx, cancelDrain := context.WithTimeout(ctx, cfg.Kafka.ConsumerDrainTimeout)
+	if err := eventConsumer.Stop(drainCtx); err != nil {
+		logger.Error("Event consumer did not drain", logging.Fields{"error": err.Error()})
+	}
+	cancelDrain()
+
+	outboxRelay.Stop()
+	outboxPruner.Stop()
+	inboxPruner.Stop()
+	idempotencyPruner.Stop()
+
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("Failed to flush traces", logging.Fields{"error": err.Error()})
+	}
+
 	logger.Info("Server exited")
 }
 
+// newReadiness registers a check for each dependency. Those listed in
+// READINESS_CRITICAL take the service out of rotation when they fail.
+func newReadiness(
+	cfg *config.Config,
+	db *sql.DB,
+	orderCache *repository.RedisOrderCache,
+	kafkaPublisher *events.KafkaPublisher,
+	paymentClient *clients.HTTPPaymentClient,
+	userClient *clients.HTTPUserClient,
+	notificationClient *clients.HTTPNotificationClient,
+) *health.Registry {
+	critical := make(map[string]bool, len(cfg.Readiness.Critical))
+	for _, name := range cfg.Readiness.Critical {
+		critical[name] = true
+	}
+
+	checks := []struct {
+		name  string
+		check health.CheckFunc
+	}{
+		{"postgres", db.PingContext},
+		{"redis", orderCache.Ping},
+		{"kafka", kafkaPublisher.Ping},
+		{"payment-service", paymentClient.Health},
+		{"user-service", userClient.Health},
+		{"notification-service", notificationClient.Health},
+	}
+
+	registry := health.NewRegistry(cfg.Readiness.Timeout)
+	for _, c := range checks {
+		registry.Register(health.Checker{
+			Name:     c.name,
+			Critical: critical[c.name],
+			Check:    c.check,
+		})
+	}
+	return registry
+}
+
+// newRateLimiter returns the configured rate limit backend, or nil when rate
+// limiting is disabled.
+func newRateLimiter(cfg *config.Config) (ratelimit.Limiter, error) {
+	if !cfg.RateLimit.Enabled {
+		return nil, nil
+	}
+	switch cfg.RateLimit.Backend {
+	case "memory":
+		return ratelimit.NewMemoryLimiter(), nil
+	case "redis":
+		return ratelimit.NewRedisLimiter(cfg.Redis), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", cfg.RateLimit.Backend)
+	}
+}
+
 func initDatabase(cfg *config.Config) (*sql.DB, error) {
 	db, err := sql.Open("postgres", cfg.Database.ConnectionString())
 	if err != nil {
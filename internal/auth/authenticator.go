@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+// HeaderAPIKey carries the key of a service calling with an API key.
+const HeaderAPIKey = "X-API-Key"
+
+var (
+	// ErrNoCredentials is returned for a request with neither a bearer
+	// token nor an API key.
+	ErrNoCredentials = errors.New("missing credentials")
+	// ErrInvalidCredentials is returned for a token or API key that does not
+	// verify.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// signingMethods are the JWT algorithms accepted. Anything else, notably
+// "none", is rejected before a key is looked up.
+var signingMethods = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512"}
+
+type apiKey struct {
+	service string
+	hash    [sha256.Size]byte
+}
+
+// Authenticator verifies bearer tokens and API keys.
+type Authenticator struct {
+	secrets    []jwt.VerificationKey
+	keySet     *KeySet
+	apiKeys    []apiKey
+	rolesClaim string
+	parser     *jwt.Parser
+}
+
+// NewAuthenticator builds an authenticator from cfg. It fails if cfg
+// provides no way to verify any caller.
+func NewAuthenticator(cfg config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{rolesClaim: cfg.RolesClaim}
+	if a.rolesClaim == "" {
+		a.rolesClaim = "roles"
+	}
+
+	for _, secret := range cfg.JWTSecrets {
+		a.secrets = append(a.secrets, []byte(secret))
+	}
+
+	switch {
+	case cfg.JWKSFile != "":
+		keySet, err := LoadKeySetFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.keySet = keySet
+	case cfg.JWKSURL != "":
+		a.keySet = NewRemoteKeySet(cfg.JWKSURL, cfg.JWKSRefresh)
+	}
+
+	for service, key := range cfg.APIKeys {
+		if key == "" {
+			return nil, fmt.Errorf("empty API key for service %q", service)
+		}
+		a.apiKeys = append(a.apiKeys, apiKey{service: service, hash: sha256.Sum256([]byte(key))})
+	}
+
+	if len(a.secrets) == 0 && a.keySet == nil && len(a.apiKeys) == 0 {
+		return nil, errors.New("authentication is enabled but no JWT secret, JWKS or API key is configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.ClockSkew),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+// Authenticate identifies the caller of r from its API key or bearer token.
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return a.AuthenticateAPIKey(key)
+	}
+
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrNoCredentials
+	}
+	return a.AuthenticateToken(r.Context(), strings.TrimSpace(token))
+}
+
+// AuthenticateAPIKey returns the service principal owning key.
+func (a *Authenticator) AuthenticateAPIKey(key string) (*Principal, error) {
+	hash := sha256.Sum256([]byte(key))
+	for _, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			return &Principal{
+				Subject: "service:" + k.service,
+				Roles:   []Role{RoleService},
+				Method:  MethodAPIKey,
+			}, nil
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// AuthenticateToken verifies a signed JWT and returns its subject. Roles are
+// read from the configured claim; a token without recognised roles is
+// treated as a customer's.
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return a.verificationKey(ctx, t)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+
+	roles := parseRoles(claims[a.rolesClaim])
+	if len(roles) == 0 {
+		roles = []Role{RoleCustomer}
+	}
+	return &Principal{Subject: subject, Roles: roles, Method: MethodJWT}, nil
+}
+
+func (a *Authenticator) verificationKey(ctx context.Context, t *jwt.Token) (interface{}, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(a.secrets) == 0 {
+			return nil, errors.New("HMAC-signed tokens are not accepted")
+		}
+		return jwt.VerificationKeySet{Keys: a.secrets}, nil
+	case *jwt.SigningMethodRSA:
+		if a.keySet == nil {
+			return nil, errors.New("RSA-signed tokens are not accepted")
+		}
+		kid, _ := t.Header["kid"].(string)
+		return a.keySet.Key(ctx, kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+}
+
+// parseRoles accepts a roles claim given either as a list of strings or as
+// a single space-separated string. Unknown roles are ignored.
+func parseRoles(claim interface{}) []Role {
+	var names []string
+	switch v := claim.(type) {
+	case string:
+		names = strings.Fields(v)
+	case []interface{}:
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var roles []Role
+	for _, name := range names {
+		switch role := Role(strings.ToLower(name)); role {
+		case RoleCustomer, RoleSupport, RoleAdmin, RoleService:
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
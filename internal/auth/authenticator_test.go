@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func writeJWKS(t *testing.T, kid string, key *rsa.PublicKey) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("marshal JWKS: %v", err)
+	}
+	return data
+}
+
+func TestAuthenticateToken_HMAC(t *testing.T) {
+	a, err := NewAuthenticator(config.AuthConfig{
+		JWTSecrets: []string{"new-secret", "old-secret"},
+		Issuer:     "https://auth.acme.test",
+		RolesClaim: "roles",
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "user_1",
+			"iss": "https://auth.acme.test",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		token     func() string
+		wantRoles []Role
+		wantErr   bool
+	}{
+		{
+			name:      "customer by default",
+			token:     func() string { return signHS256(t, "new-secret", valid()) },
+			wantRoles: []Role{RoleCustomer},
+		},
+		{
+			name: "roles claim",
+			token: func() string {
+				claims := valid()
+				claims["roles"] = []string{"support", "unknown"}
+				return signHS256(t, "new-secret", claims)
+			},
+			wantRoles: []Role{RoleSupport},
+		},
+		{
+			name:      "rotated secret",
+			token:     func() string { return signHS256(t, "old-secret", valid()) },
+			wantRoles: []Role{RoleCustomer},
+		},
+		{
+			name:    "wrong secret",
+			token:   func() string { return signHS256(t, "other", valid()) },
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := valid()
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return signHS256(t, "new-secret", claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := valid()
+				claims["iss"] = "https://evil.test"
+				return signHS256(t, "new-secret", claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsigned",
+			token: func() string {
+				token, _ := jwt.NewWithClaims(jwt.SigningMethodNone, valid()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				return token
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.AuthenticateToken(context.Background(), tt.token())
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCredentials) {
+					t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Subject != "user_1" || p.Method != MethodJWT {
+				t.Errorf("unexpected principal %+v", p)
+			}
+			if len(p.Roles) != len(tt.wantRoles) || p.Roles[0] != tt.wantRoles[0] {
+				t.Errorf("expected roles %v, got %v", tt.wantRoles, p.Roles)
+			}
+		})
+	}
+}
+
+func TestAuthenticateToken_JWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	jwks := writeJWKS(t, "key-1", &key.PublicKey)
+
+	sign := func(kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub":   "user_2",
+			"roles": "admin",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return signed
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+
+	var fetches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	sources := map[string]config.AuthConfig{
+		"file": {JWKSFile: path},
+		"url":  {JWKSURL: server.URL, JWKSRefresh: time.Hour},
+	}
+	for name, cfg := range sources {
+		t.Run(name, func(t *testing.T) {
+			a, err := NewAuthenticator(cfg)
+			if err != nil {
+				t.Fatalf("NewAuthenticator: %v", err)
+			}
+
+			p, err := a.AuthenticateToken(context.Background(), sign("key-1"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Subject != "user_2" || !p.HasRole(RoleAdmin) {
+				t.Errorf("unexpected principal %+v", p)
+			}
+
+			if _, err := a.AuthenticateToken(context.Background(), sign("key-2")); !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("expected an unknown key to be rejected, got %v", err)
+			}
+		})
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the remote key set to be fetched once, got %d", fetches)
+	}
+}
+
+func TestAuthenticateToken_RejectsHMACWithoutSecret(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, writeJWKS(t, "key-1", &key.PublicKey), 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+
+	a, err := NewAuthenticator(config.AuthConfig{JWKSFile: path})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	token := signHS256(t, "guessed", jwt.MapClaims{"sub": "user_1", "exp": time.Now().Add(time.Hour).Unix()})
+	if _, err := a.AuthenticateToken(context.Background(), token); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	a, err := NewAuthenticator(config.AuthConfig{
+		JWTSecrets: []string{"secret"},
+		APIKeys:    map[string]string{"warehouse": "wh-key"},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/orders", nil)
+	if _, err := a.Authenticate(req); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials, got %v", err)
+	}
+
+	req.Header.Set(HeaderAPIKey, "wh-key")
+	p, err := a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Subject != "service:warehouse" || !p.HasRole(RoleService) || !p.CanAccessAllOrders() {
+		t.Errorf("unexpected principal %+v", p)
+	}
+
+	req.Header.Set(HeaderAPIKey, "wrong")
+	if _, err := a.Authenticate(req); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+
+	req.Header.Del(HeaderAPIKey)
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, "secret", jwt.MapClaims{
+		"sub": "user_1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}))
+	p, err = a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !p.CanAccessUser("user_1") || p.CanAccessUser("user_2") {
+		t.Errorf("expected a customer to access only their own orders")
+	}
+}
+
+func TestNewAuthenticator_RequiresCredentials(t *testing.T) {
+	if _, err := NewAuthenticator(config.AuthConfig{}); err == nil {
+		t.Error("expected an error without any way to verify callers")
+	}
+}
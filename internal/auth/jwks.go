@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minRefetchInterval limits how often an unknown key ID triggers a fetch of
+// a remote key set, so forged key IDs cannot be used to hammer the issuer.
+const minRefetchInterval = 30 * time.Second
+
+// ErrUnknownKey is returned for a token signed with a key not in the set.
+var ErrUnknownKey = errors.New("signing key not found in key set")
+
+// KeySet holds the RSA public keys of a JSON Web Key Set, read from a file
+// once or fetched from a URL and refreshed periodically.
+type KeySet struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+	now     func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// LoadKeySetFile reads a JSON Web Key Set from path.
+func LoadKeySetFile(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWKS file: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: keys, now: time.Now}, nil
+}
+
+// NewRemoteKeySet returns a key set fetched from url on first use and again
+// once it is older than refresh or a token names a key it does not hold.
+func NewRemoteKeySet(url string, refresh time.Duration) *KeySet {
+	return &KeySet{
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		now:     time.Now,
+	}
+}
+
+// Key returns the key with ID kid. An empty kid matches the only key of a
+// single-key set.
+func (k *KeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.url != "" && k.stale(kid) {
+		keys, err := k.fetch(ctx)
+		// Keep serving the previous keys if the issuer is briefly unreachable.
+		if err != nil && k.keys == nil {
+			return nil, err
+		}
+		if err == nil {
+			k.keys = keys
+		}
+		k.fetchedAt = k.now()
+	}
+
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, nil
+		}
+	}
+	if key, ok := k.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+func (k *KeySet) stale(kid string) bool {
+	age := k.now().Sub(k.fetchedAt)
+	if k.keys == nil || (k.refresh > 0 && age >= k.refresh) {
+		return true
+	}
+	_, known := k.keys[kid]
+	return kid != "" && !known && age >= minRefetchInterval
+}
+
+func (k *KeySet) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	return parseJWKS(data)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS returns the RSA signing keys of a JSON Web Key Set by key ID.
+// Keys of other types or meant for encryption are skipped.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("parse JWKS key %q: invalid modulus", jwk.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("parse JWKS key %q: invalid exponent", jwk.Kid)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("parse JWKS: no RSA signing keys")
+	}
+	return keys, nil
+}
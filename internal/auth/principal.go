@@ -0,0 +1,72 @@
+// Package auth authenticates API callers and decides what they may access.
+package auth
+
+import "context"
+
+// Role is a coarse permission granted to a caller.
+type Role string
+
+const (
+	// RoleCustomer may only see and act on their own orders.
+	RoleCustomer Role = "customer"
+	// RoleSupport may read every order and handle cancellations, refunds
+	// and returns on a customer's behalf.
+	RoleSupport Role = "support"
+	// RoleAdmin may do anything, including overriding order status.
+	RoleAdmin Role = "admin"
+	// RoleService is granted to other AcmeShop services calling with an
+	// API key.
+	RoleService Role = "service"
+)
+
+// Method is how a caller proved its identity.
+type Method string
+
+const (
+	MethodJWT    Method = "jwt"
+	MethodAPIKey Method = "api_key"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject is the user ID for token holders and "service:<name>" for
+	// API key holders.
+	Subject string
+	Roles   []Role
+	Method  Method
+}
+
+// HasRole reports whether p holds any of roles.
+func (p *Principal) HasRole(roles ...Role) bool {
+	for _, held := range p.Roles {
+		for _, role := range roles {
+			if held == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanAccessAllOrders reports whether p may see orders belonging to any user.
+func (p *Principal) CanAccessAllOrders() bool {
+	return p.HasRole(RoleSupport, RoleAdmin, RoleService)
+}
+
+// CanAccessUser reports whether p may see the orders of userID.
+func (p *Principal) CanAccessUser(userID string) bool {
+	return p.CanAccessAllOrders() || (userID != "" && p.Subject == userID)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,52 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// newHealthClient returns the http.Client used for /health probes. It
+// bypasses the resilient transport: a probe must report the dependency as
+// it is right now, so it is not retried, does not take a bulkhead slot and
+// neither trips nor resets the circuit breaker. The readiness check's
+// context bounds it.
+func newHealthClient() *http.Client {
+	return &http.Client{Transport: http.DefaultTransport}
+}
+
+// checkHealth calls the /health endpoint of a downstream service.
+func checkHealth(ctx context.Context, httpClient *http.Client, baseURL, service string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s service health returned status %d", service, resp.StatusCode)
+	}
+	return nil
+}
+
+// Health checks that the payment service is up.
+func (c *HTTPPaymentClient) Health(ctx context.Context) error {
+	return checkHealth(ctx, c.healthClient, c.baseURL, "payment")
+}
+
+// Health checks that the user service is up.
+func (c *HTTPUserClient) Health(ctx context.Context) error {
+	return checkHealth(ctx, c.healthClient, c.baseURL, "user")
+}
+
+// Health checks that the notification service is up.
+func (c *HTTPNotificationClient) Health(ctx context.Context) error {
+	return checkHealth(ctx, c.healthClient, c.baseURL, "notification")
+}
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+var (
+	clientRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "orders_client_request_duration_seconds",
+		Help:    "Duration of calls to downstream services by service, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "method", "code"})
+	clientErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_client_errors_total",
+		Help: "Failed calls to downstream services by service and reason (transport or 5xx).",
+	}, []string{"service", "reason"})
+	clientRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_client_retries_total",
+		Help: "Retried calls to downstream services.",
+	}, []string{"service"})
+	clientRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_client_rejected_total",
+		Help: "Calls to downstream services rejected without being sent, by reason (circuit_open or bulkhead_full).",
+	}, []string{"service", "reason"})
+	clientCircuitState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "orders_client_circuit_state",
+		Help: "Circuit breaker state per downstream service: 0 closed, 1 half-open, 2 open.",
+	}, []string{"service"})
+)
+
+// instrumentedTransport records the duration and outcome of every request
+// sent to a downstream service.
+type instrumentedTransport struct {
+	service string
+	next    http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Seconds()
+
+	if err != nil {
+		clientRequestDuration.WithLabelValues(t.service, req.Method, "error").Observe(elapsed)
+		clientErrors.WithLabelValues(t.service, "transport").Inc()
+		return nil, err
+	}
+
+	clientRequestDuration.WithLabelValues(t.service, req.Method, strconv.Itoa(resp.StatusCode)).Observe(elapsed)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		clientErrors.WithLabelValues(t.service, "5xx").Inc()
+	}
+	return resp, nil
+}
+
+// newHTTPClient returns an http.Client for the named downstream service.
+// Each attempt is traced, propagating the W3C traceparent header, and
+// recorded in the client metrics. Retries, the circuit breaker and the
+// concurrency cap are configured by cfg.Resilience; cfg.Timeout bounds a
+// call including its retries.
+func newHTTPClient(service string, cfg config.ServiceConfig) *http.Client {
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: newResilientTransport(service, cfg.Resilience, &instrumentedTransport{
+			service: service,
+			next: otelhttp.NewTransport(http.DefaultTransport,
+				otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+					return service + " " + req.Method
+				}),
+			),
+		}),
+	}
+}
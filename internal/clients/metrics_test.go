@@ -0,0 +1,45 @@
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+func TestInstrumentedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newHTTPClient("test", config.ServiceConfig{Timeout: time.Second})
+	errors5xx := clientErrors.WithLabelValues("test", "5xx")
+	transport := clientErrors.WithLabelValues("test", "transport")
+
+	for _, path := range []string{"/ok", "/fail"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if got := testutil.ToFloat64(errors5xx); got != 1 {
+		t.Errorf("expected one 5xx error, got %v", got)
+	}
+
+	server.Close()
+	if _, err := client.Get(server.URL + "/ok"); err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	if got := testutil.ToFloat64(transport); got != 1 {
+		t.Errorf("expected one transport error, got %v", got)
+	}
+}
@@ -19,21 +19,21 @@ var _ interfaces.NotificationSender = (*HTTPNotificationClient)(nil)
 
 // HTTPNotificationClient implements interfaces.NotificationSender using HTTP.
 type HTTPNotificationClient struct {
-	baseURL    string
-	httpClient *http.Client
-	apiKey     string
-	logger     *logging.LoggerV2
+	baseURL      string
+	httpClient   *http.Client
+	healthClient *http.Client
+	apiKey       string
+	logger       *logging.LoggerV2
 }
 
 // NewHTTPNotificationClient creates a new HTTP-based notification client.
 func NewHTTPNotificationClient(cfg config.ServiceConfig, logger *logging.LoggerV2) *HTTPNotificationClient {
 	return &HTTPNotificationClient{
-		baseURL: cfg.BaseURL,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
-		apiKey: cfg.APIKey,
-		logger: logger,
+		baseURL:      cfg.BaseURL,
+		httpClient:   newHTTPClient("notification", cfg),
+		healthClient: newHealthClient(),
+		apiKey:       cfg.APIKey,
+		logger:       logger,
 	}
 }
 
@@ -20,21 +20,25 @@ var _ interfaces.PaymentClient = (*HTTPPaymentClient)(nil)
 
 // HTTPPaymentClient implements interfaces.PaymentClient using HTTP.
 type HTTPPaymentClient struct {
-	baseURL    string
-	httpClient *http.Client
-	apiKey     string
-	logger     *logging.LoggerV2
+	baseURL          string
+	httpClient       *http.Client
+	healthClient     *http.Client
+	apiKey           string
+	webhookSecrets   []string
+	webhookTolerance time.Duration
+	logger           *logging.LoggerV2
 }
 
 // NewHTTPPaymentClient creates a new HTTP-based payment client.
 func NewHTTPPaymentClient(cfg config.ServiceConfig, logger *logging.LoggerV2) *HTTPPaymentClient {
 	return &HTTPPaymentClient{
-		baseURL: cfg.BaseURL,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
-		apiKey: cfg.APIKey,
-		logger: logger,
+		baseURL:          cfg.BaseURL,
+		httpClient:       newHTTPClient("payment", cfg),
+		healthClient:     newHealthClient(),
+		apiKey:           cfg.APIKey,
+		webhookSecrets:   cfg.WebhookSecrets,
+		webhookTolerance: cfg.WebhookTolerance,
+		logger:           logger,
 	}
 }
 
@@ -158,7 +162,7 @@ func (c *HTTPPaymentClient) Refund(ctx context.Context, req *models.RefundReques
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("refund service returned status %d", resp.StatusCode)
+		return nil, &StatusError{Service: "refund", StatusCode: resp.StatusCode}
 	}
 
 	var result models.RefundResponse
@@ -201,16 +205,18 @@ func (c *HTTPPaymentClient) CancelPayment(ctx context.Context, paymentID string)
 	return nil
 }
 
-// ValidateWebhook validates an incoming webhook from the payment provider.
+// ValidateWebhook verifies the HMAC-SHA256 signature of an incoming webhook
+// against every configured secret. Rejections return a *WebhookSignatureError
+// describing the reason.
 func (c *HTTPPaymentClient) ValidateWebhook(ctx context.Context, payload []byte, signature string) (bool, error) {
 	c.logger.Debug("Validating webhook", logging.Fields{
-		"payload_size": len(payload),
+		"payload_size":  len(payload),
 		"has_signature": signature != "",
 	})
 
-	// TODO(TEAM-PAYMENTS): Implement proper webhook signature validation
-	if signature == "" {
-		return false, nil
+	if err := VerifyWebhookSignature(payload, signature, c.webhookSecrets, c.webhookTolerance, time.Now()); err != nil {
+		c.logger.Info("Rejected webhook signature", logging.Fields{"error": err.Error()})
+		return false, err
 	}
 
 	return true, nil
@@ -0,0 +1,289 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+var (
+	// ErrCircuitOpen is returned without calling a service whose circuit
+	// breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	// ErrBulkheadFull is returned without calling a service that already
+	// has its maximum number of calls in flight.
+	ErrBulkheadFull = errors.New("too many concurrent requests")
+)
+
+// IsUnavailable reports whether err means a downstream service was not
+// called because it is failing or saturated.
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrBulkheadFull)
+}
+
+// StatusError is returned when a downstream service answers with an
+// unexpected HTTP status.
+type StatusError struct {
+	Service    string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s service returned status %d", e.Service, e.StatusCode)
+}
+
+// IsRejected reports whether err means a downstream service definitely
+// refused the request: it answered with a client error other than a
+// timeout, conflict or rate limit, so it did not act on it.
+func IsRejected(err error) bool {
+	var status *StatusError
+	if !errors.As(err, &status) {
+		return false
+	}
+	switch status.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return status.StatusCode >= 400 && status.StatusCode < 500
+}
+
+// breakerState is the state of a circuit breaker. The values are exported
+// as the orders_client_circuit_state gauge.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+// circuitBreaker opens after a run of consecutive failures, rejects calls
+// while open, and after a cool-down lets a limited number of probe calls
+// through. A successful probe closes it again; a failed one reopens it.
+type circuitBreaker struct {
+	service     string
+	failures    int
+	openTimeout time.Duration
+	probes      int
+	now         func() time.Time
+
+	mu          sync.Mutex
+	state       breakerState
+	consecutive int
+	openedAt    time.Time
+	inProbe     int
+}
+
+func newCircuitBreaker(service string, cfg config.ResilienceConfig) *circuitBreaker {
+	probes := cfg.BreakerHalfOpenProbes
+	if probes <= 0 {
+		probes = 1
+	}
+	b := &circuitBreaker{
+		service:     service,
+		failures:    cfg.BreakerFailures,
+		openTimeout: cfg.BreakerOpenTimeout,
+		probes:      probes,
+		now:         time.Now,
+	}
+	clientCircuitState.WithLabelValues(service).Set(float64(breakerClosed))
+	return b
+}
+
+// allow reports whether a call may proceed. Every allowed call must be
+// followed by exactly one call to done.
+func (b *circuitBreaker) allow() bool {
+	if b.failures <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.setState(breakerHalfOpen)
+	}
+	if b.state == breakerHalfOpen {
+		if b.inProbe >= b.probes {
+			return false
+		}
+		b.inProbe++
+	}
+	return true
+}
+
+// done records the outcome of an allowed call.
+func (b *circuitBreaker) done(success bool) {
+	if b.failures <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen && b.inProbe > 0 {
+		b.inProbe--
+	}
+
+	if success {
+		b.consecutive = 0
+		if b.state != breakerClosed {
+			b.setState(breakerClosed)
+		}
+		return
+	}
+
+	b.consecutive++
+	if b.state == breakerHalfOpen || b.consecutive >= b.failures {
+		b.openedAt = b.now()
+		b.setState(breakerOpen)
+	}
+}
+
+func (b *circuitBreaker) setState(state breakerState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	b.inProbe = 0
+	clientCircuitState.WithLabelValues(b.service).Set(float64(state))
+}
+
+// resilientTransport wraps a downstream service's transport with a
+// concurrency cap, a circuit breaker and retries.
+type resilientTransport struct {
+	service  string
+	cfg      config.ResilienceConfig
+	breaker  *circuitBreaker
+	bulkhead chan struct{}
+	next     http.RoundTripper
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+func newResilientTransport(service string, cfg config.ResilienceConfig, next http.RoundTripper) *resilientTransport {
+	t := &resilientTransport{
+		service: service,
+		cfg:     cfg,
+		breaker: newCircuitBreaker(service, cfg),
+		next:    next,
+		sleep:   sleepContext,
+	}
+	if cfg.MaxConcurrent > 0 {
+		t.bulkhead = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	return t
+}
+
+func (t *resilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.bulkhead != nil {
+		select {
+		case t.bulkhead <- struct{}{}:
+			defer func() { <-t.bulkhead }()
+		default:
+			clientRejected.WithLabelValues(t.service, "bulkhead_full").Inc()
+			return nil, fmt.Errorf("%s service: %w", t.service, ErrBulkheadFull)
+		}
+	}
+
+	retryable := isIdempotent(req)
+	for attempt := 0; ; attempt++ {
+		if !t.breaker.allow() {
+			clientRejected.WithLabelValues(t.service, "circuit_open").Inc()
+			return nil, fmt.Errorf("%s service: %w", t.service, ErrCircuitOpen)
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		failed := isFailure(resp, err)
+		// A call abandoned by its caller says nothing about the service.
+		t.breaker.done(!failed || req.Context().Err() == context.Canceled)
+
+		if !failed || !retryable || attempt >= t.cfg.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		next, rewindErr := rewind(req)
+		if rewindErr != nil {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req.Context(), backoff(t.cfg, attempt)); err != nil {
+			return nil, err
+		}
+		clientRetries.WithLabelValues(t.service).Inc()
+		req = next
+	}
+}
+
+// isIdempotent reports whether req may safely be sent more than once:
+// idempotent methods, and writes that carry an Idempotency-Key.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// isFailure reports whether a call counts against the service: transport
+// errors, timeouts, throttling and server errors. Other client errors do not.
+func isFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// rewind returns a copy of req whose body can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be replayed")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next := req.Clone(req.Context())
+	next.Body = body
+	return next, nil
+}
+
+// backoff returns the delay before retry attempt+1: exponential in
+// attempt, capped at RetryMaxDelay, with full jitter.
+func backoff(cfg config.ResilienceConfig, attempt int) time.Duration {
+	delay := cfg.RetryBaseDelay << uint(attempt)
+	if delay <= 0 || (cfg.RetryMaxDelay > 0 && delay > cfg.RetryMaxDelay) {
+		delay = cfg.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
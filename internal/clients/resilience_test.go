@@ -0,0 +1,205 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func statusResponse(code int) *http.Response {
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(""))}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	b := newCircuitBreaker("test", config.ResilienceConfig{
+		BreakerFailures:    2,
+		BreakerOpenTimeout: 10 * time.Second,
+	})
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if !b.allow() {
+			t.Fatalf("expected call %d to be allowed while closed", i)
+		}
+		b.done(false)
+	}
+	if b.allow() {
+		t.Fatal("expected the circuit to open after two failures")
+	}
+
+	now = now.Add(10 * time.Second)
+	if !b.allow() {
+		t.Fatal("expected a probe after the open timeout")
+	}
+	if b.allow() {
+		t.Error("expected only one probe while half-open")
+	}
+	b.done(false)
+	if b.allow() {
+		t.Fatal("expected a failed probe to reopen the circuit")
+	}
+
+	now = now.Add(10 * time.Second)
+	if !b.allow() {
+		t.Fatal("expected another probe after the open timeout")
+	}
+	b.done(true)
+	if b.state != breakerClosed {
+		t.Errorf("expected a successful probe to close the circuit, got %v", b.state)
+	}
+}
+
+func TestResilientTransportRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		key      string
+		attempts int32
+	}{
+		{name: "GET is retried", method: http.MethodGet, attempts: 3},
+		{name: "POST is not retried", method: http.MethodPost, attempts: 1},
+		{name: "POST with idempotency key is retried", method: http.MethodPost, key: "key_1", attempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&attempts, 1)
+				if req.Body != nil {
+					body, _ := io.ReadAll(req.Body)
+					if string(body) != `{"amount":100}` {
+						t.Errorf("expected the body on every attempt, got %q", body)
+					}
+				}
+				return statusResponse(http.StatusServiceUnavailable), nil
+			})
+
+			transport := newResilientTransport("test", config.ResilienceConfig{MaxRetries: 2}, next)
+			transport.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+			req := httptest.NewRequest(tt.method, "http://payments/api", strings.NewReader(`{"amount":100}`))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(`{"amount":100}`)), nil
+			}
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("expected the last response, got %d", resp.StatusCode)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("expected %d attempts, got %d", tt.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestResilientTransportRejects(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/slow" {
+			close(started)
+			<-release
+		}
+		return statusResponse(http.StatusInternalServerError), nil
+	})
+
+	transport := newResilientTransport("test", config.ResilienceConfig{
+		BreakerFailures:    1,
+		BreakerOpenTimeout: time.Minute,
+		MaxConcurrent:      1,
+	}, next)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://users/slow", nil))
+	}()
+	<-started
+
+	if _, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://users/fast", nil)); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("expected ErrBulkheadFull, got %v", err)
+	}
+
+	close(release)
+	<-done
+
+	_, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://users/fast", nil))
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen after a server error, got %v", err)
+	}
+	if !IsUnavailable(err) {
+		t.Error("expected the error to be reported as unavailable")
+	}
+}
+
+func TestHealthBypassesResilience(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPUserClient(config.ServiceConfig{
+		BaseURL: server.URL,
+		Timeout: time.Second,
+		Resilience: config.ResilienceConfig{
+			MaxRetries:         3,
+			RetryBaseDelay:     time.Millisecond,
+			RetryMaxDelay:      time.Millisecond,
+			BreakerFailures:    1,
+			BreakerOpenTimeout: time.Minute,
+		},
+	}, nil)
+
+	for i := 0; i < 3; i++ {
+		err := client.Health(context.Background())
+		if err == nil || strings.Contains(err.Error(), "circuit") {
+			t.Fatalf("expected the probe to report the service status, got %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected one request per probe, got %d", got)
+	}
+}
+
+func TestIsRejected(t *testing.T) {
+	tests := []struct {
+		err      error
+		rejected bool
+	}{
+		{&StatusError{Service: "refund", StatusCode: http.StatusBadRequest}, true},
+		{&StatusError{Service: "refund", StatusCode: http.StatusUnprocessableEntity}, true},
+		{&StatusError{Service: "refund", StatusCode: http.StatusConflict}, false},
+		{&StatusError{Service: "refund", StatusCode: http.StatusTooManyRequests}, false},
+		{&StatusError{Service: "refund", StatusCode: http.StatusBadGateway}, false},
+		{context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRejected(tt.err); got != tt.rejected {
+			t.Errorf("IsRejected(%v) = %v, want %v", tt.err, got, tt.rejected)
+		}
+	}
+}
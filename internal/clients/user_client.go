@@ -20,21 +20,21 @@ type UserClient interface {
 
 // HTTPUserClient implements UserClient using HTTP.
 type HTTPUserClient struct {
-	baseURL    string
-	httpClient *http.Client
-	apiKey     string
-	logger     *logging.LoggerV2
+	baseURL      string
+	httpClient   *http.Client
+	healthClient *http.Client
+	apiKey       string
+	logger       *logging.LoggerV2
 }
 
 // NewHTTPUserClient creates a new HTTP-based user client.
 func NewHTTPUserClient(cfg config.ServiceConfig, logger *logging.LoggerV2) *HTTPUserClient {
 	return &HTTPUserClient{
-		baseURL: cfg.BaseURL,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
-		apiKey: cfg.APIKey,
-		logger: logger,
+		baseURL:      cfg.BaseURL,
+		httpClient:   newHTTPClient("user", cfg),
+		healthClient: newHealthClient(),
+		apiKey:       cfg.APIKey,
+		logger:       logger,
 	}
 }
 
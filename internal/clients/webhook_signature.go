@@ -0,0 +1,135 @@
+package clients
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderPaymentSignature carries the webhook signature, formatted as
+// "t=<unix timestamp>,v1=<hex HMAC-SHA256>". Several v1 entries may be
+// present while the payment service is rotating keys.
+const HeaderPaymentSignature = "X-Payment-Signature"
+
+// WebhookRejectReason identifies why a webhook signature was rejected.
+type WebhookRejectReason string
+
+const (
+	WebhookRejectMissingSignature   WebhookRejectReason = "missing_signature"
+	WebhookRejectMalformedSignature WebhookRejectReason = "malformed_signature"
+	WebhookRejectTimestampExpired   WebhookRejectReason = "timestamp_outside_tolerance"
+	WebhookRejectSignatureMismatch  WebhookRejectReason = "signature_mismatch"
+	WebhookRejectNoSecrets          WebhookRejectReason = "no_secrets_configured"
+)
+
+// WebhookSignatureError is returned when a webhook fails verification.
+type WebhookSignatureError struct {
+	Reason  WebhookRejectReason
+	Message string
+}
+
+func (e *WebhookSignatureError) Error() string {
+	return fmt.Sprintf("webhook signature rejected (%s): %s", e.Reason, e.Message)
+}
+
+func newWebhookSignatureError(reason WebhookRejectReason, format string, args ...interface{}) *WebhookSignatureError {
+	return &WebhookSignatureError{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// VerifyWebhookSignature checks header against HMAC-SHA256("<timestamp>.<payload>")
+// for each of secrets and rejects timestamps further than tolerance from now.
+// A zero tolerance disables the timestamp check.
+func VerifyWebhookSignature(payload []byte, header string, secrets []string, tolerance time.Duration, now time.Time) error {
+	if len(secrets) == 0 {
+		return newWebhookSignatureError(WebhookRejectNoSecrets, "no webhook secrets configured")
+	}
+	if header == "" {
+		return newWebhookSignatureError(WebhookRejectMissingSignature, "%s header is required", HeaderPaymentSignature)
+	}
+
+	timestamp, signatures, err := parseSignatureHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if tolerance > 0 {
+		skew := now.Sub(time.Unix(timestamp, 0))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > tolerance {
+			return newWebhookSignatureError(WebhookRejectTimestampExpired,
+				"timestamp is %s away from server time, tolerance is %s", skew.Round(time.Second), tolerance)
+		}
+	}
+
+	for _, secret := range secrets {
+		expected := computeSignature(payload, secret, timestamp)
+		for _, sig := range signatures {
+			if hmac.Equal(expected, sig) {
+				return nil
+			}
+		}
+	}
+
+	return newWebhookSignatureError(WebhookRejectSignatureMismatch, "no signature matches an active secret")
+}
+
+// SignWebhook builds a signature header for payload. It is used by tests and
+// tooling that replay webhooks against the service.
+func SignWebhook(payload []byte, secret string, timestamp time.Time) string {
+	ts := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", ts, hex.EncodeToString(computeSignature(payload, secret, ts)))
+}
+
+func computeSignature(payload []byte, secret string, timestamp int64) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func parseSignatureHeader(header string) (int64, [][]byte, error) {
+	var timestamp int64
+	var hasTimestamp bool
+	var signatures [][]byte
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return 0, nil, newWebhookSignatureError(WebhookRejectMalformedSignature, "invalid element %q", part)
+		}
+
+		switch key {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, newWebhookSignatureError(WebhookRejectMalformedSignature, "invalid timestamp %q", value)
+			}
+			timestamp = ts
+			hasTimestamp = true
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				return 0, nil, newWebhookSignatureError(WebhookRejectMalformedSignature, "signature is not hex encoded")
+			}
+			signatures = append(signatures, sig)
+		default:
+			// Unknown schemes are ignored so the provider can add new ones.
+		}
+	}
+
+	if !hasTimestamp {
+		return 0, nil, newWebhookSignatureError(WebhookRejectMalformedSignature, "timestamp is missing")
+	}
+	if len(signatures) == 0 {
+		return 0, nil, newWebhookSignatureError(WebhookRejectMalformedSignature, "no v1 signature present")
+	}
+
+	return timestamp, signatures, nil
+}
@@ -0,0 +1,92 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"payment.completed"}`)
+	now := time.Unix(1700000000, 0)
+	secrets := []string{"whsec_new", "whsec_old"}
+
+	tests := []struct {
+		name    string
+		header  string
+		secrets []string
+		reason  WebhookRejectReason
+	}{
+		{
+			name:    "current secret",
+			header:  SignWebhook(payload, "whsec_new", now),
+			secrets: secrets,
+		},
+		{
+			name:    "rotated secret",
+			header:  SignWebhook(payload, "whsec_old", now.Add(-time.Minute)),
+			secrets: secrets,
+		},
+		{
+			name:    "no secrets configured",
+			header:  SignWebhook(payload, "whsec_new", now),
+			secrets: nil,
+			reason:  WebhookRejectNoSecrets,
+		},
+		{
+			name:    "missing signature",
+			header:  "",
+			secrets: secrets,
+			reason:  WebhookRejectMissingSignature,
+		},
+		{
+			name:    "malformed signature",
+			header:  "sha256=abcdef",
+			secrets: secrets,
+			reason:  WebhookRejectMalformedSignature,
+		},
+		{
+			name:    "replayed timestamp",
+			header:  SignWebhook(payload, "whsec_new", now.Add(-10*time.Minute)),
+			secrets: secrets,
+			reason:  WebhookRejectTimestampExpired,
+		},
+		{
+			name:    "unknown secret",
+			header:  SignWebhook(payload, "whsec_attacker", now),
+			secrets: secrets,
+			reason:  WebhookRejectSignatureMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookSignature(payload, tt.header, tt.secrets, 5*time.Minute, now)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("Expected signature to verify, got %v", err)
+				}
+				return
+			}
+
+			var sigErr *WebhookSignatureError
+			if !errors.As(err, &sigErr) {
+				t.Fatalf("Expected WebhookSignatureError, got %v", err)
+			}
+			if sigErr.Reason != tt.reason {
+				t.Errorf("Expected reason %s, got %s", tt.reason, sigErr.Reason)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhookSignature_TamperedPayload(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	header := SignWebhook([]byte(`{"amount":100}`), "whsec_new", now)
+
+	err := VerifyWebhookSignature([]byte(`{"amount":1}`), header, []string{"whsec_new"}, time.Minute, now)
+	var sigErr *WebhookSignatureError
+	if !errors.As(err, &sigErr) || sigErr.Reason != WebhookRejectSignatureMismatch {
+		t.Errorf("Expected signature mismatch, got %v", err)
+	}
+}
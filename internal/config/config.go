@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,7 +16,17 @@ type Config struct {
 	UserService         ServiceConfig
 	NotificationService ServiceConfig
 	Features            FeatureFlags
-	TaxRate             float64
+	Idempotency         IdempotencyConfig
+	Outbox              OutboxConfig
+	Inbox               InboxConfig
+	Pricing             PricingConfig
+	Tax                 TaxConfig
+	Lifecycle           LifecycleConfig
+	Returns             ReturnsConfig
+	Readiness           ReadinessConfig
+	Tracing             TracingConfig
+	Auth                AuthConfig
+	RateLimit           RateLimitConfig
 }
 
 type ServerConfig struct {
@@ -59,12 +70,50 @@ type KafkaConfig struct {
 	ConsumerGroup string
 	OrdersTopic   string
 	PaymentsTopic string
+	// PaymentsRetryDelays holds one delay per payments retry topic. A
+	// payment event that fails its last retry, or fails permanently, goes
+	// to PaymentsDLQTopic.
+	PaymentsRetryDelays []time.Duration
+	PaymentsDLQTopic    string
+	// ConsumerWorkers is the number of payment events handled concurrently
+	// per topic. Events for the same order are always handled in order.
+	ConsumerWorkers int
+	// ConsumerDrainTimeout bounds how long shutdown waits for events being
+	// handled to finish.
+	ConsumerDrainTimeout time.Duration
+	// EventEncoding is how published event data is encoded: json or
+	// protobuf. Consumed events are decoded by their content-type header.
+	EventEncoding string
 }
 
 type ServiceConfig struct {
 	BaseURL string
 	Timeout time.Duration
 	APIKey  string
+	// WebhookSecrets are the active signing secrets for inbound webhooks.
+	// Listing more than one allows rotation without downtime.
+	WebhookSecrets   []string
+	WebhookTolerance time.Duration
+	Resilience       ResilienceConfig
+}
+
+// ResilienceConfig controls retries, circuit breaking and the concurrency
+// cap for calls to a downstream service.
+type ResilienceConfig struct {
+	// MaxRetries is how many times an idempotent call is retried after a
+	// transport error or a retryable status; 0 disables retries.
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// BreakerFailures consecutive failures open the circuit; calls then
+	// fail fast for BreakerOpenTimeout before up to BreakerHalfOpenProbes
+	// trial calls are let through.
+	BreakerFailures       int
+	BreakerOpenTimeout    time.Duration
+	BreakerHalfOpenProbes int
+	// MaxConcurrent caps in-flight calls; calls beyond it are rejected.
+	// 0 means unlimited.
+	MaxConcurrent int
 }
 
 type FeatureFlags struct {
@@ -74,6 +123,138 @@ type FeatureFlags struct {
 	EnableOrderCaching   bool
 }
 
+// IdempotencyConfig controls how long Idempotency-Key values are remembered
+// and how often expired ones are pruned.
+type IdempotencyConfig struct {
+	TTL            time.Duration
+	PruneInterval  time.Duration
+	PruneBatchSize int
+}
+
+// OutboxConfig controls the outbox relay and pruning of delivered messages.
+// BatchTimeout bounds how long a relay batch spends publishing while its
+// claimed rows stay locked; messages not published in time are left for the
+// next batch.
+type OutboxConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	BatchTimeout   time.Duration
+	MaxAttempts    int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	Retention      time.Duration
+	PruneInterval  time.Duration
+	PruneBatchSize int
+}
+
+// InboxConfig controls pruning of the processed events inbox. Retention
+// must outlast the longest time an event can be redelivered, including
+// retries and dead-letter replays, or a late redelivery is applied twice.
+type InboxConfig struct {
+	Retention      time.Duration
+	PruneInterval  time.Duration
+	PruneBatchSize int
+}
+
+// PricingConfig holds server-side pricing settings. Amounts are in minor
+// currency units (cents).
+type PricingConfig struct {
+	// ShippingFlatRate and FreeShippingThreshold are keyed by currency code
+	// and given in that currency's minor units. Orders in a currency with
+	// no flat rate ship free; a missing or zero threshold disables free
+	// shipping.
+	ShippingFlatRate      map[string]int64
+	FreeShippingThreshold map[string]int64
+	// MismatchTolerance is how far client-submitted totals may differ from
+	// the server-computed ones before an order is rejected.
+	MismatchTolerance int64
+	// TaxRounding is the rounding mode for tax: half_up, half_even, down or up.
+	TaxRounding string
+}
+
+// TaxConfig points at the jurisdiction tax rules file.
+type TaxConfig struct {
+	RulesFile string
+}
+
+// LifecycleConfig selects the order state machine definition. An empty File
+// uses the built-in lifecycle.
+type LifecycleConfig struct {
+	File string
+}
+
+// ReturnsConfig holds the returns policy.
+type ReturnsConfig struct {
+	// Window is how long after delivery a return may be requested.
+	Window time.Duration
+}
+
+// ReadinessConfig controls the dependency checks behind /ready.
+type ReadinessConfig struct {
+	// Timeout bounds each dependency check.
+	Timeout time.Duration
+	// Critical names the dependencies whose failure makes the service not
+	// ready; any other failing dependency only degrades it.
+	Critical []string
+}
+
+// TracingConfig controls OpenTelemetry trace export.
+type TracingConfig struct {
+	// Exporter is "otlp", "stdout" or "none". Trace context is propagated
+	// even when spans are not exported.
+	Exporter string
+	// OTLPEndpoint is the host:port of an OTLP/HTTP collector.
+	OTLPEndpoint string
+	OTLPInsecure bool
+	// SampleRatio is the fraction of new traces recorded; incoming sampled
+	// traces are always recorded.
+	SampleRatio float64
+}
+
+// AuthConfig controls authentication of API callers.
+type AuthConfig struct {
+	// Enabled requires callers of the v1 and v2 APIs and debug endpoints to
+	// present a bearer token or an API key. It is on by default; local
+	// development opts out with AUTH_ENABLED=false.
+	Enabled bool
+	// JWTSecrets verify HS256/384/512 tokens. Listing more than one allows
+	// rotation without downtime.
+	JWTSecrets []string
+	// JWKSFile or JWKSURL supply the keys that verify RS256/384/512 tokens.
+	// A URL is refetched every JWKSRefresh.
+	JWKSFile    string
+	JWKSURL     string
+	JWKSRefresh time.Duration
+	// Issuer and Audience, when set, must match the token's iss and aud.
+	Issuer   string
+	Audience string
+	// RolesClaim names the token claim listing the caller's roles.
+	RolesClaim string
+	ClockSkew  time.Duration
+	// APIKeys maps the name of each calling service to its key.
+	APIKeys map[string]string
+}
+
+// RateLimitConfig controls per-caller request quotas.
+type RateLimitConfig struct {
+	Enabled bool
+	// Backend is "memory" for limits per replica or "redis" for limits
+	// shared by all replicas.
+	Backend string
+	// Read applies to safe requests, Write to the others. Order creation
+	// is additionally charged to CreateOrder.
+	Read        RateLimitPolicy
+	Write       RateLimitPolicy
+	CreateOrder RateLimitPolicy
+}
+
+// RateLimitPolicy is a token bucket refilled at PerMinute tokens a minute
+// and holding at most Burst.
+type RateLimitPolicy struct {
+	PerMinute int
+	Burst     int
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -105,21 +286,37 @@ func Load() *Config {
 			ConsumerGroup: getEnvString("KAFKA_CONSUMER_GROUP", "orders-service"),
 			OrdersTopic:   getEnvString("KAFKA_ORDERS_TOPIC", "orders"),
 			PaymentsTopic: getEnvString("KAFKA_PAYMENTS_TOPIC", "payments"),
+
+			PaymentsRetryDelays: getEnvSeconds("KAFKA_PAYMENTS_RETRY_DELAYS", []int{10, 60, 600}),
+			PaymentsDLQTopic:    getEnvString("KAFKA_PAYMENTS_DLQ_TOPIC", getEnvString("KAFKA_PAYMENTS_TOPIC", "payments")+".dlq"),
+
+			ConsumerWorkers:      getEnvInt("KAFKA_CONSUMER_WORKERS", 8),
+			ConsumerDrainTimeout: time.Duration(getEnvInt("KAFKA_CONSUMER_DRAIN_TIMEOUT", 20)) * time.Second,
+
+			EventEncoding: getEnvString("KAFKA_EVENT_ENCODING", "json"),
 		},
 		PaymentService: ServiceConfig{
 			BaseURL: getEnvString("PAYMENT_SERVICE_URL", "http://localhost:8083"),
 			Timeout: time.Duration(getEnvInt("PAYMENT_SERVICE_TIMEOUT", 30)) * time.Second,
 			APIKey:  getEnvString("PAYMENT_SERVICE_API_KEY", ""),
+
+			WebhookSecrets:   getEnvList("PAYMENT_WEBHOOK_SECRETS"),
+			WebhookTolerance: time.Duration(getEnvInt("PAYMENT_WEBHOOK_TOLERANCE", 300)) * time.Second,
+			Resilience:       loadResilienceConfig("PAYMENT_SERVICE"),
 		},
 		UserService: ServiceConfig{
 			BaseURL: getEnvString("USER_SERVICE_URL", "http://localhost:8081"),
 			Timeout: time.Duration(getEnvInt("USER_SERVICE_TIMEOUT", 10)) * time.Second,
 			APIKey:  getEnvString("USER_SERVICE_API_KEY", ""),
+
+			Resilience: loadResilienceConfig("USER_SERVICE"),
 		},
 		NotificationService: ServiceConfig{
 			BaseURL: getEnvString("NOTIFICATION_SERVICE_URL", "http://localhost:8084"),
 			Timeout: time.Duration(getEnvInt("NOTIFICATION_SERVICE_TIMEOUT", 10)) * time.Second,
 			APIKey:  getEnvString("NOTIFICATION_SERVICE_API_KEY", ""),
+
+			Resilience: loadResilienceConfig("NOTIFICATION_SERVICE"),
 		},
 		Features: FeatureFlags{
 			EnableV1API:          getEnvBool("ENABLE_V1_API", true),
@@ -127,8 +324,90 @@ func Load() *Config {
 			EnableOrderEvents:    getEnvBool("ENABLE_ORDER_EVENTS", true),
 			EnableOrderCaching:   getEnvBool("ENABLE_ORDER_CACHING", true),
 		},
-		// Updated by platform team in Q4 2023
-		TaxRate: getEnvFloat("TAX_RATE", 0.088),
+		Idempotency: IdempotencyConfig{
+			TTL:            time.Duration(getEnvInt("IDEMPOTENCY_KEY_TTL", 24)) * time.Hour,
+			PruneInterval:  time.Duration(getEnvInt("IDEMPOTENCY_PRUNE_INTERVAL_MINUTES", 60)) * time.Minute,
+			PruneBatchSize: getEnvInt("IDEMPOTENCY_PRUNE_BATCH_SIZE", 1000),
+		},
+		Outbox: OutboxConfig{
+			PollInterval:   time.Duration(getEnvInt("OUTBOX_POLL_INTERVAL_MS", 500)) * time.Millisecond,
+			BatchSize:      getEnvInt("OUTBOX_BATCH_SIZE", 100),
+			BatchTimeout:   time.Duration(getEnvInt("OUTBOX_BATCH_TIMEOUT_MS", 10000)) * time.Millisecond,
+			MaxAttempts:    getEnvInt("OUTBOX_MAX_ATTEMPTS", 20),
+			BaseBackoff:    time.Duration(getEnvInt("OUTBOX_BASE_BACKOFF_MS", 500)) * time.Millisecond,
+			MaxBackoff:     time.Duration(getEnvInt("OUTBOX_MAX_BACKOFF", 300)) * time.Second,
+			Retention:      time.Duration(getEnvInt("OUTBOX_RETENTION_DAYS", 7)) * 24 * time.Hour,
+			PruneInterval:  time.Duration(getEnvInt("OUTBOX_PRUNE_INTERVAL_MINUTES", 60)) * time.Minute,
+			PruneBatchSize: getEnvInt("OUTBOX_PRUNE_BATCH_SIZE", 1000),
+		},
+		Inbox: InboxConfig{
+			Retention:      time.Duration(getEnvInt("INBOX_RETENTION_DAYS", 30)) * 24 * time.Hour,
+			PruneInterval:  time.Duration(getEnvInt("INBOX_PRUNE_INTERVAL_MINUTES", 60)) * time.Minute,
+			PruneBatchSize: getEnvInt("INBOX_PRUNE_BATCH_SIZE", 1000),
+		},
+		Pricing: PricingConfig{
+			ShippingFlatRate:      getEnvAmounts("SHIPPING_FLAT_RATE"),
+			FreeShippingThreshold: getEnvAmounts("FREE_SHIPPING_THRESHOLD"),
+			MismatchTolerance:     int64(getEnvInt("PRICE_MISMATCH_TOLERANCE", 1)),
+			TaxRounding:           getEnvString("TAX_ROUNDING_MODE", "half_up"),
+		},
+		Tax: TaxConfig{
+			RulesFile: getEnvString("TAX_RULES_FILE", "configs/tax_rules.json"),
+		},
+		Lifecycle: LifecycleConfig{
+			File: getEnvString("ORDER_LIFECYCLE_FILE", ""),
+		},
+		Returns: ReturnsConfig{
+			Window: time.Duration(getEnvInt("RETURN_WINDOW_DAYS", 30)) * 24 * time.Hour,
+		},
+		Readiness: ReadinessConfig{
+			Timeout:  time.Duration(getEnvInt("READINESS_TIMEOUT_MS", 2000)) * time.Millisecond,
+			Critical: getEnvListDefault("READINESS_CRITICAL", []string{"postgres"}),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnvString("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnvString("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getEnvBool("TRACING_OTLP_INSECURE", true),
+			SampleRatio:  getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		Auth: AuthConfig{
+			Enabled:     getEnvBool("AUTH_ENABLED", true),
+			JWTSecrets:  getEnvList("AUTH_JWT_SECRETS"),
+			JWKSFile:    getEnvString("AUTH_JWKS_FILE", ""),
+			JWKSURL:     getEnvString("AUTH_JWKS_URL", ""),
+			JWKSRefresh: time.Duration(getEnvInt("AUTH_JWKS_REFRESH", 300)) * time.Second,
+			Issuer:      getEnvString("AUTH_JWT_ISSUER", ""),
+			Audience:    getEnvString("AUTH_JWT_AUDIENCE", ""),
+			RolesClaim:  getEnvString("AUTH_ROLES_CLAIM", "roles"),
+			ClockSkew:   time.Duration(getEnvInt("AUTH_CLOCK_SKEW", 30)) * time.Second,
+			APIKeys:     getEnvMap("AUTH_API_KEYS"),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     getEnvBool("RATE_LIMIT_ENABLED", true),
+			Backend:     getEnvString("RATE_LIMIT_BACKEND", "memory"),
+			Read:        loadRateLimitPolicy("RATE_LIMIT_READ", 600, 100),
+			Write:       loadRateLimitPolicy("RATE_LIMIT_WRITE", 120, 30),
+			CreateOrder: loadRateLimitPolicy("RATE_LIMIT_CREATE_ORDER", 10, 5),
+		},
+	}
+}
+
+func loadResilienceConfig(prefix string) ResilienceConfig {
+	return ResilienceConfig{
+		MaxRetries:            getEnvInt(prefix+"_MAX_RETRIES", 2),
+		RetryBaseDelay:        time.Duration(getEnvInt(prefix+"_RETRY_BASE_DELAY_MS", 100)) * time.Millisecond,
+		RetryMaxDelay:         time.Duration(getEnvInt(prefix+"_RETRY_MAX_DELAY_MS", 2000)) * time.Millisecond,
+		BreakerFailures:       getEnvInt(prefix+"_BREAKER_FAILURES", 5),
+		BreakerOpenTimeout:    time.Duration(getEnvInt(prefix+"_BREAKER_OPEN_TIMEOUT", 30)) * time.Second,
+		BreakerHalfOpenProbes: getEnvInt(prefix+"_BREAKER_HALF_OPEN_PROBES", 1),
+		MaxConcurrent:         getEnvInt(prefix+"_MAX_CONCURRENT", 50),
+	}
+}
+
+func loadRateLimitPolicy(prefix string, perMinute, burst int) RateLimitPolicy {
+	return RateLimitPolicy{
+		PerMinute: getEnvInt(prefix+"_PER_MINUTE", perMinute),
+		Burst:     getEnvInt(prefix+"_BURST", burst),
 	}
 }
 
@@ -148,6 +427,65 @@ func getEnvString(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvMap parses a comma-separated list of name=value pairs.
+func getEnvMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range getEnvList(key) {
+		if name, value, found := strings.Cut(pair, "="); found {
+			values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
+
+// getEnvAmounts parses a comma-separated list of CURRENCY=amount pairs,
+// amounts in minor units.
+func getEnvAmounts(key string) map[string]int64 {
+	amounts := make(map[string]int64)
+	for currency, value := range getEnvMap(key) {
+		if amount, err := strconv.ParseInt(value, 10, 64); err == nil {
+			amounts[strings.ToUpper(currency)] = amount
+		}
+	}
+	return amounts
+}
+
+// getEnvSeconds parses a comma-separated list of durations in seconds.
+func getEnvSeconds(key string, defaultValue []int) []time.Duration {
+	seconds := defaultValue
+	if values := getEnvList(key); len(values) > 0 {
+		seconds = nil
+		for _, value := range values {
+			if intValue, err := strconv.Atoi(value); err == nil {
+				seconds = append(seconds, intValue)
+			}
+		}
+	}
+
+	durations := make([]time.Duration, len(seconds))
+	for i, s := range seconds {
+		durations[i] = time.Duration(s) * time.Second
+	}
+	return durations
+}
+
+func getEnvListDefault(key string, defaultValue []string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return defaultValue
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
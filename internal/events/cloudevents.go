@@ -0,0 +1,188 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/schemas"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// Events are written as CloudEvents 1.0 in the binary content mode of the
+// Kafka protocol binding: the context attributes travel as ce_ headers and
+// the message value is the event data alone.
+const (
+	CloudEventsSpecVersion = "1.0"
+
+	HeaderCESpecVersion = "ce_specversion"
+	HeaderCEID          = "ce_id"
+	HeaderCESource      = "ce_source"
+	HeaderCEType        = "ce_type"
+	HeaderCETime        = "ce_time"
+	HeaderCESubject     = "ce_subject"
+	HeaderCEDataSchema  = "ce_dataschema"
+	HeaderContentType   = "content-type"
+
+	// Extension attributes.
+	HeaderCEUserID        = "ce_userid"
+	HeaderCECorrelationID = "ce_correlationid"
+)
+
+// eventSource is the CloudEvents source of every event we publish.
+const eventSource = "/acme-shop/orders-service"
+
+// dataVersions pins the schema version each event type's data is built
+// with. Bump an entry together with its builder when a new schema version
+// is added under internal/schemas/events.
+var dataVersions = map[EventType]int{
+	EventTypeOrderCreated:          1,
+	EventTypeOrderStatusChanged:    1,
+	EventTypeOrderCancelled:        1,
+	EventTypeOrderRefunded:         1,
+	EventTypeShipmentCreated:       1,
+	EventTypeShipmentStatusChanged: 1,
+}
+
+// dataSchemaFor returns the schema ID new events of eventType carry.
+func dataSchemaFor(eventType EventType) string {
+	version, ok := dataVersions[eventType]
+	if !ok {
+		return ""
+	}
+	return schemas.SchemaID(string(eventType), version)
+}
+
+// cloudEventMessage returns the Kafka message carrying event in binary
+// mode, with its data written by encoder.
+func cloudEventMessage(event *OrderEvent, encoder EventEncoder) (kafka.Message, error) {
+	value, err := encoder.EncodeOrderEvent(event.Type, event.Data)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	msg := kafka.Message{
+		Key:   []byte(event.OrderID),
+		Value: value,
+	}
+
+	headers := kafkaHeaders{msg: &msg}
+	headers.Set(HeaderCESpecVersion, CloudEventsSpecVersion)
+	headers.Set(HeaderCEID, event.ID)
+	headers.Set(HeaderCESource, eventSource)
+	headers.Set(HeaderCEType, string(event.Type))
+	headers.Set(HeaderCETime, event.Timestamp.UTC().Format(time.RFC3339Nano))
+	headers.Set(HeaderCESubject, event.OrderID)
+	headers.Set(HeaderCEDataSchema, event.DataSchema)
+	headers.Set(HeaderContentType, encoder.ContentType())
+	if event.UserID != "" {
+		headers.Set(HeaderCEUserID, event.UserID)
+	}
+	if event.CorrelationID != "" {
+		headers.Set(HeaderCECorrelationID, event.CorrelationID)
+	}
+	return msg, nil
+}
+
+// upgradeLegacyEvent brings an event stored in the outbox before events
+// carried a schema up to the first schema version. Only order.created
+// changed shape: its data was the bare order rather than wrapping it.
+func upgradeLegacyEvent(event *OrderEvent) error {
+	if event.DataSchema != "" {
+		return nil
+	}
+
+	if event.Type == EventTypeOrderCreated {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(event.Data, &fields); err != nil {
+			return err
+		}
+		if _, wrapped := fields["order"]; !wrapped {
+			data, err := json.Marshal(map[string]json.RawMessage{"order": event.Data})
+			if err != nil {
+				return err
+			}
+			event.Data = data
+		}
+	}
+
+	event.DataSchema = schemas.SchemaID(string(event.Type), 1)
+	return nil
+}
+
+// paymentEventData is the data of a payment event.
+type paymentEventData struct {
+	PaymentID     string        `json:"payment_id"`
+	OrderID       string        `json:"order_id"`
+	Status        string        `json:"status"`
+	Amount        *models.Money `json:"amount,omitempty"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+	RefundID      string        `json:"refund_id,omitempty"`
+}
+
+// decodePaymentEvent reads a payment event in CloudEvents binary mode,
+// decoding its data as its content-type says and validating it against the
+// schema it names, or as the legacy JSON envelope still sent by producers
+// that have not migrated.
+func decodePaymentEvent(msg kafka.Message) (*PaymentEvent, error) {
+	headers := kafkaHeaders{msg: &msg}
+
+	specVersion := headers.Get(HeaderCESpecVersion)
+	if specVersion == "" {
+		var event PaymentEvent
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+	if specVersion != CloudEventsSpecVersion {
+		return nil, fmt.Errorf("unsupported CloudEvents spec version %q", specVersion)
+	}
+	encoder, err := encoderForContentType(headers.Get(HeaderContentType))
+	if err != nil {
+		return nil, err
+	}
+
+	event := &PaymentEvent{
+		ID:   headers.Get(HeaderCEID),
+		Type: PaymentEventType(headers.Get(HeaderCEType)),
+	}
+	if at := headers.Get(HeaderCETime); at != "" {
+		timestamp, err := time.Parse(time.RFC3339Nano, at)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", HeaderCETime, err)
+		}
+		event.Timestamp = timestamp
+	}
+
+	// Types with no schema are not handled and are left for the caller to
+	// ignore, without decoding data no schema describes.
+	registry := schemas.Default()
+	dataSchema := headers.Get(HeaderCEDataSchema)
+	if dataSchema == "" {
+		latest, err := registry.Latest(string(event.Type))
+		if err != nil {
+			return event, nil
+		}
+		dataSchema = latest.ID
+	}
+
+	data, err := encoder.DecodePaymentEvent(event.Type, msg.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.Validate(dataSchema, data); err != nil {
+		return nil, err
+	}
+
+	var fields paymentEventData
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	event.PaymentID = fields.PaymentID
+	event.OrderID = fields.OrderID
+	event.Status = fields.Status
+	event.RefundID = fields.RefundID
+	event.Data = data
+	return event, nil
+}
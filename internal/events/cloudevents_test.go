@@ -0,0 +1,184 @@
+package events
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/schemas"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func testOrder() *models.Order {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usd := func(amount int64) models.Money { return models.Money{Amount: amount, Currency: "USD"} }
+	return &models.Order{
+		ID:     "ord_1",
+		UserID: "usr_1",
+		Status: models.OrderStatusConfirmed,
+		Items: []models.OrderItem{
+			{ID: "itm_1", ProductID: "prd_1", ProductName: "Widget", Quantity: 2, UnitPrice: usd(1250), Total: usd(2500)},
+		},
+		Subtotal:  usd(2500),
+		Total:     usd(2500),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// testEvents builds one event of each type from fixed inputs.
+func testEvents(t *testing.T) []*OrderEvent {
+	t.Helper()
+	ctx := context.Background()
+	order := testOrder()
+	now := order.CreatedAt
+	shipment := &repository.Shipment{
+		ID:        "shp_1",
+		OrderID:   order.ID,
+		Status:    repository.ShipmentStatusShipped,
+		Items:     []repository.ShipmentItem{{ItemID: "itm_1", ProductID: "prd_1", Quantity: 2}},
+		CreatedAt: now,
+		UpdatedAt: now,
+		ShippedAt: &now,
+	}
+	refund := &repository.OrderRefund{
+		ID:        "rfd_1",
+		OrderID:   order.ID,
+		PaymentID: "pay_1",
+		Status:    models.PaymentStatusCompleted,
+		Amount:    models.Money{Amount: 1250, Currency: "USD"},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	build := []func() (*OrderEvent, error){
+		func() (*OrderEvent, error) { return NewOrderCreatedEvent(ctx, order) },
+		func() (*OrderEvent, error) { return NewOrderStatusChangedEvent(ctx, order, models.OrderStatusPending) },
+		func() (*OrderEvent, error) { return NewOrderCancelledEvent(ctx, order, "Payment failed") },
+		func() (*OrderEvent, error) {
+			return NewOrderRefundedEvent(ctx, order, refund, refund.Amount)
+		},
+		func() (*OrderEvent, error) { return NewShipmentCreatedEvent(ctx, order, shipment) },
+		func() (*OrderEvent, error) {
+			return NewShipmentStatusChangedEvent(ctx, order, shipment, repository.ShipmentStatusPending)
+		},
+	}
+
+	events := make([]*OrderEvent, 0, len(build))
+	for _, b := range build {
+		event, err := b()
+		if err != nil {
+			t.Fatalf("building event: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// TestEventBuildersMatchSchemas checks that every event we publish is valid
+// against the schema version it claims.
+func TestEventBuildersMatchSchemas(t *testing.T) {
+	built := make(map[EventType]bool)
+	for _, event := range testEvents(t) {
+		built[event.Type] = true
+		if err := schemas.Default().Validate(event.DataSchema, event.Data); err != nil {
+			t.Errorf("%s: %v", event.Type, err)
+		}
+	}
+	for eventType := range dataVersions {
+		if !built[eventType] {
+			t.Errorf("no builder checked for %s", eventType)
+		}
+	}
+}
+
+func TestCloudEventMessage(t *testing.T) {
+	event, err := NewOrderCreatedEvent(context.Background(), testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.Timestamp = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	msg, err := cloudEventMessage(event, JSONEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := kafkaHeaders{msg: &msg}
+
+	want := map[string]string{
+		HeaderCESpecVersion: "1.0",
+		HeaderCEID:          event.ID,
+		HeaderCESource:      eventSource,
+		HeaderCEType:        "order.created",
+		HeaderCETime:        "2024-05-01T12:00:00Z",
+		HeaderCESubject:     "ord_1",
+		HeaderCEDataSchema:  "urn:acme-shop:schema:order.created:v1",
+		HeaderContentType:   "application/json",
+		HeaderCEUserID:      "usr_1",
+	}
+	for key, value := range want {
+		if got := headers.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if string(msg.Key) != "ord_1" || string(msg.Value) != string(event.Data) {
+		t.Errorf("expected the order ID as key and the data alone as value, got %s / %s", msg.Key, msg.Value)
+	}
+}
+
+func TestDecodePaymentEvent(t *testing.T) {
+	binary := func(data string, headers ...kafka.Header) kafka.Message {
+		return kafka.Message{
+			Value: []byte(data),
+			Headers: append([]kafka.Header{
+				{Key: HeaderCESpecVersion, Value: []byte("1.0")},
+				{Key: HeaderCEID, Value: []byte("evt_1")},
+				{Key: HeaderCEType, Value: []byte("payment.completed")},
+				{Key: HeaderCETime, Value: []byte("2024-05-01T12:00:00Z")},
+			}, headers...),
+		}
+	}
+
+	event, err := decodePaymentEvent(binary(`{"payment_id":"pay_1","order_id":"ord_1","status":"completed"}`,
+		kafka.Header{Key: HeaderContentType, Value: []byte("application/json; charset=utf-8")}))
+	if err != nil {
+		t.Fatalf("decoding a binary-mode event: %v", err)
+	}
+	if event.ID != "evt_1" || event.Type != PaymentEventCompleted || event.OrderID != "ord_1" || event.PaymentID != "pay_1" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// The data must match the schema, here the one named by dataschema.
+	_, err = decodePaymentEvent(binary(`{"payment_id":"pay_1"}`,
+		kafka.Header{Key: HeaderCEDataSchema, Value: []byte("urn:acme-shop:schema:payment.completed:v1")}))
+	var invalid *schemas.ValidationError
+	if !stderrors.As(err, &invalid) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+
+	if _, err := decodePaymentEvent(binary(`{}`, kafka.Header{Key: HeaderContentType, Value: []byte("application/avro")})); err == nil {
+		t.Error("expected an unsupported content type to be rejected")
+	}
+
+	// Producers that have not migrated still send the JSON envelope.
+	legacy, err := decodePaymentEvent(kafka.Message{Value: []byte(`{"id":"evt_2","type":"payment.failed","payment_id":"pay_1","order_id":"ord_1"}`)})
+	if err != nil || legacy.ID != "evt_2" || legacy.OrderID != "ord_1" {
+		t.Errorf("unexpected legacy event %+v, %v", legacy, err)
+	}
+}
+
+func TestUpgradeLegacyEvent(t *testing.T) {
+	event := &OrderEvent{
+		Type: EventTypeOrderCreated,
+		Data: []byte(`{"id":"ord_1","user_id":"usr_1","status":"pending","items":null,"total":{"amount":0,"currency":"USD"},"created_at":"2024-05-01T12:00:00Z","updated_at":"2024-05-01T12:00:00Z"}`),
+	}
+	if err := upgradeLegacyEvent(event); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.Default().Validate(event.DataSchema, event.Data); err != nil {
+		t.Errorf("expected the upgraded event to be valid: %v", err)
+	}
+}
@@ -3,14 +3,25 @@ package events
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/tracing"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PaymentEventType represents the type of payment event.
@@ -29,150 +40,517 @@ type PaymentEvent struct {
 	PaymentID string           `json:"payment_id"`
 	OrderID   string           `json:"order_id"`
 	Status    string           `json:"status"`
+	RefundID  string           `json:"refund_id,omitempty"`
 	Data      json.RawMessage  `json:"data"`
 	Timestamp time.Time        `json:"timestamp"`
 }
 
-// KafkaConsumer consumes events from Kafka.
+// paymentEventsConsumer names the payment events consumer in the processed
+// events inbox.
+const paymentEventsConsumer = "payment-events"
+
+// errConsumerStopped is returned by waits interrupted by Stop.
+var errConsumerStopped = stderrors.New("consumer stopped")
+
+// workerQueueSize is how many fetched messages may wait for each worker.
+const workerQueueSize = 16
+
+// Bounds of the delay before fetching again after a failed fetch, so an
+// unreachable broker is not retried in a tight loop.
+const (
+	fetchBackoffMin = 500 * time.Millisecond
+	fetchBackoffMax = 30 * time.Second
+)
+
+// messageReader is the part of kafka.Reader used to consume a topic.
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// messageWriter is the part of kafka.Writer used to forward failed messages.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// consumerStage is one topic payment events are read from: the payments
+// topic itself, or a retry topic whose messages are held back until due.
+type consumerStage struct {
+	reader  messageReader
+	attempt int
+	offsets *offsetTracker
+	// commitMu keeps commits of the stage in offset order.
+	commitMu sync.Mutex
+}
+
+// KafkaConsumer consumes payment events from Kafka. Messages are handled
+// by a pool of workers, in order per order ID. Offsets are committed only
+// once a message, and every message before it in its partition, has been
+// handled or forwarded to a retry topic or the dead-letter topic.
 type KafkaConsumer struct {
-	reader       *kafka.Reader
+	stages       []*consumerStage
+	writer       messageWriter
+	retryTopics  []string
+	retryDelays  []time.Duration
+	dlqTopic     string
+	workers      int
 	orderService *service.OrderService
 	logger       *logging.LoggerV2
-	stopCh       chan struct{}
+	now          func() time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	doneCh   chan struct{}
+
+	mu             sync.Mutex
+	started        bool
+	cancelHandlers context.CancelFunc
 }
 
 // NewKafkaConsumer creates a new Kafka-based event consumer.
 func NewKafkaConsumer(cfg config.KafkaConfig, orderService *service.OrderService, logger *logging.LoggerV2) *KafkaConsumer {
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  cfg.Brokers,
-		Topic:    cfg.PaymentsTopic,
-		GroupID:  cfg.ConsumerGroup,
-		MinBytes: 1,
-		MaxBytes: 10e6,
-		MaxWait:  time.Second,
-	})
+	workers := cfg.ConsumerWorkers
+	if workers <= 0 {
+		workers = 1
+	}
 
-	return &KafkaConsumer{
-		reader:       reader,
+	c := &KafkaConsumer{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			WriteTimeout: 10 * time.Second,
+			RequiredAcks: kafka.RequireAll,
+		},
+		retryDelays:  cfg.PaymentsRetryDelays,
+		dlqTopic:     cfg.PaymentsDLQTopic,
+		workers:      workers,
 		orderService: orderService,
 		logger:       logger,
+		now:          time.Now,
 		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
 	}
+
+	c.addStage(newPaymentsReader(cfg, cfg.PaymentsTopic, cfg.ConsumerGroup), 0)
+	for i := range cfg.PaymentsRetryDelays {
+		topic := retryTopic(cfg.PaymentsTopic, i+1)
+		c.retryTopics = append(c.retryTopics, topic)
+		// Each retry topic has its own group so a rebalance of one does
+		// not pause the others.
+		groupID := cfg.ConsumerGroup + "." + strings.TrimPrefix(topic, cfg.PaymentsTopic+".")
+		c.addStage(newPaymentsReader(cfg, topic, groupID), i+1)
+	}
+
+	return c
+}
+
+func (c *KafkaConsumer) addStage(reader messageReader, attempt int) {
+	c.stages = append(c.stages, &consumerStage{
+		reader:  reader,
+		attempt: attempt,
+		offsets: newOffsetTracker(),
+	})
+}
+
+func newPaymentsReader(cfg config.KafkaConfig, topic, groupID string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  cfg.Brokers,
+		Topic:    topic,
+		GroupID:  groupID,
+		MinBytes: 1,
+		MaxBytes: 10e6,
+		MaxWait:  time.Second,
+	})
 }
 
-// Start begins consuming events.
+// Start begins consuming the payments topic and its retry topics, and
+// returns once Stop has drained them or ctx is cancelled.
 func (c *KafkaConsumer) Start(ctx context.Context) error {
-	c.logger.Info("Starting Kafka consumer")
+	defer close(c.doneCh)
 
-	for {
+	handlerCtx, cancelHandlers := context.WithCancel(ctx)
+	defer cancelHandlers()
+	c.mu.Lock()
+	c.started = true
+	c.cancelHandlers = cancelHandlers
+	c.mu.Unlock()
+
+	// Fetching stops as soon as Stop is called; handlers keep handlerCtx
+	// until the drain deadline.
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	defer cancelFetch()
+	go func() {
 		select {
-		case <-ctx.Done():
-			return ctx.Err()
 		case <-c.stopCh:
-			c.logger.Info("Kafka consumer stopped")
-			return nil
-		default:
-			msg, err := c.reader.ReadMessage(ctx)
-			if err != nil {
-				if ctx.Err() != nil {
-					return ctx.Err()
+			cancelFetch()
+		case <-fetchCtx.Done():
+		}
+	}()
+
+	c.logger.Info("Starting Kafka consumer", logging.Fields{
+		"workers":      c.workers,
+		"retry_topics": c.retryTopics,
+		"dlq_topic":    c.dlqTopic,
+	})
+
+	errs := make(chan error, len(c.stages))
+	for _, stage := range c.stages {
+		go func(stage *consumerStage) {
+			errs <- c.consume(fetchCtx, handlerCtx, stage)
+		}(stage)
+	}
+
+	var firstErr error
+	for range c.stages {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		c.logger.Info("Kafka consumer stopped")
+	}
+	return firstErr
+}
+
+// consume fetches a stage's messages and hands them to its workers until
+// fetchCtx ends, then waits for the workers to finish what they were given.
+func (c *KafkaConsumer) consume(fetchCtx, handlerCtx context.Context, stage *consumerStage) error {
+	queues := make([]chan kafka.Message, c.workers)
+	var workers sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan kafka.Message, workerQueueSize)
+		workers.Add(1)
+		go func(queue <-chan kafka.Message) {
+			defer workers.Done()
+			for msg := range queue {
+				c.process(handlerCtx, stage, msg)
+			}
+		}(queues[i])
+	}
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		workers.Wait()
+	}()
+
+	failures := 0
+	for {
+		msg, err := stage.reader.FetchMessage(fetchCtx)
+		if err != nil {
+			if fetchCtx.Err() == nil {
+				failures++
+				delay := fetchBackoff(failures)
+				c.logger.Error("Failed to read message", logging.Fields{
+					"error":    err.Error(),
+					"failures": failures,
+					"retry_in": delay.String(),
+				})
+				if waitFor(fetchCtx, c.stopCh, delay) == nil {
+					continue
 				}
-				c.logger.Error("Failed to read message", logging.Fields{"error": err.Error()})
-				continue
 			}
+			select {
+			case <-c.stopCh:
+				return nil
+			default:
+				return fetchCtx.Err()
+			}
+		}
+		failures = 0
+
+		stage.offsets.fetched(msg)
+		queues[workerFor(msg, c.workers)] <- msg
+	}
+}
+
+// fetchBackoff returns the delay after the given number of consecutive
+// failed fetches: exponential from fetchBackoffMin, capped at
+// fetchBackoffMax, with jitter so the stages do not retry in lockstep.
+func fetchBackoff(failures int) time.Duration {
+	delay := fetchBackoffMax
+	if failures < 16 {
+		delay = fetchBackoffMin << uint(failures-1)
+	}
+	if delay > fetchBackoffMax {
+		delay = fetchBackoffMax
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
 
-			c.handleMessage(ctx, msg)
+// process handles msg, forwards it on failure, and commits it. A message
+// abandoned because the consumer is stopping is left uncommitted and will
+// be redelivered.
+func (c *KafkaConsumer) process(ctx context.Context, stage *consumerStage, msg kafka.Message) {
+	kafkaInFlight.WithLabelValues(msg.Topic).Inc()
+	defer kafkaInFlight.WithLabelValues(msg.Topic).Dec()
+
+	if stage.attempt > 0 {
+		if err := waitFor(ctx, c.stopCh, retryDue(msg).Sub(c.now())); err != nil {
+			return
 		}
 	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err := c.handleMessage(ctx, msg); err != nil {
+		// A handler cut short by shutdown has not really failed.
+		if ctx.Err() != nil {
+			return
+		}
+		if err := c.forward(ctx, stage, msg, err); err != nil {
+			return
+		}
+	}
+
+	stage.commitMu.Lock()
+	defer stage.commitMu.Unlock()
+
+	commit, ok := stage.offsets.settle(msg)
+	if !ok {
+		return
+	}
+	if err := stage.reader.CommitMessages(ctx, commit); err != nil {
+		c.logger.Error("Failed to commit message", logging.Fields{
+			"topic":     commit.Topic,
+			"partition": commit.Partition,
+			"offset":    commit.Offset,
+			"error":     err.Error(),
+		})
+	}
 }
 
-// Stop stops the consumer.
-func (c *KafkaConsumer) Stop() {
-	close(c.stopCh)
-	c.reader.Close()
+// forward moves a failed message to the next retry topic or, once retries
+// are exhausted or the failure is permanent, to the dead-letter topic. It
+// keeps trying until the write succeeds, since the message must not be
+// committed before it is safely stored elsewhere.
+func (c *KafkaConsumer) forward(ctx context.Context, stage *consumerStage, msg kafka.Message, cause error) error {
+	attempt := stage.attempt + 1
+	now := c.now()
+
+	topic, notBefore, destination := c.dlqTopic, time.Time{}, "dead_letter"
+	if !isPermanent(cause) && attempt <= len(c.retryTopics) {
+		topic, notBefore, destination = c.retryTopics[attempt-1], now.Add(c.retryDelays[attempt-1]), "retry"
+	}
+	out := failedMessage(msg, topic, attempt, notBefore, now, cause)
+
+	delay := time.Second
+	for {
+		err := c.writer.WriteMessages(ctx, out)
+		if err == nil {
+			break
+		}
+		c.logger.Error("Failed to forward payment event", logging.Fields{
+			"topic": topic,
+			"error": err.Error(),
+		})
+		if err := waitFor(ctx, c.stopCh, delay); err != nil {
+			return err
+		}
+		if delay < 30*time.Second {
+			delay *= 2
+		}
+	}
+
+	fields := logging.Fields{
+		"from_topic": msg.Topic,
+		"to_topic":   topic,
+		"attempt":    attempt,
+		"offset":     msg.Offset,
+		"error":      cause.Error(),
+	}
+	if destination == "dead_letter" {
+		c.logger.Error("Payment event dead-lettered", fields)
+	} else {
+		c.logger.Info("Payment event scheduled for retry", fields)
+	}
+	recordForwarded(msg.Topic, destination)
+	return nil
 }
 
-func (c *KafkaConsumer) handleMessage(ctx context.Context, msg kafka.Message) {
+// Stop stops fetching and waits for the messages already fetched to be
+// handled and committed. If ctx ends first, handlers still running are
+// cancelled and their messages left to be redelivered. The readers and
+// writer are closed once the consumer has stopped.
+func (c *KafkaConsumer) Stop(ctx context.Context) error {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+
+	c.mu.Lock()
+	started, cancelHandlers := c.started, c.cancelHandlers
+	c.mu.Unlock()
+
+	var err error
+	if started {
+		select {
+		case <-c.doneCh:
+		case <-ctx.Done():
+			err = ctx.Err()
+			cancelHandlers()
+			<-c.doneCh
+		}
+	}
+
+	for _, stage := range c.stages {
+		stage.reader.Close()
+	}
+	c.writer.Close()
+	return err
+}
+
+// handleMessage applies a payment event. Each event ID is applied at most
+// once; redeliveries and unknown event types are ignored.
+func (c *KafkaConsumer) handleMessage(ctx context.Context, msg kafka.Message) error {
 	c.logger.Debug("Received message", logging.Fields{
 		"topic":     msg.Topic,
 		"partition": msg.Partition,
 		"offset":    msg.Offset,
 	})
 
-	var event PaymentEvent
-	if err := json.Unmarshal(msg.Value, &event); err != nil {
-		c.logger.Error("Failed to unmarshal event", logging.Fields{"error": err.Error()})
-		return
+	recordConsumerLag(msg)
+
+	ctx = extractTrace(ctx, kafkaHeaders{msg: &msg})
+	ctx, span := tracing.Tracer().Start(ctx, msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(msg.Partition)),
+			semconv.MessagingKafkaMessageOffset(int(msg.Offset)),
+			attribute.Int("orders.retry_attempt", retryAttempt(msg)),
+		),
+	)
+	defer span.End()
+
+	event, err := decodePaymentEvent(msg)
+	if err != nil {
+		c.logger.Error("Failed to decode event", logging.Fields{"error": err.Error()})
+		span.SetStatus(codes.Error, "undecodable message")
+		recordConsumed(msg.Topic, "unknown", "invalid")
+		return &permanentError{err: err}
 	}
+	// Without an ID a redelivery cannot be told apart from a new event.
+	if event.ID == "" {
+		c.logger.Error("Rejecting payment event without an ID", logging.Fields{
+			"type":     event.Type,
+			"order_id": event.OrderID,
+		})
+		span.SetStatus(codes.Error, "missing event ID")
+		recordConsumed(msg.Topic, string(event.Type), "invalid")
+		return &permanentError{err: stderrors.New("payment event has no ID")}
+	}
+	span.SetAttributes(
+		semconv.MessagingMessageID(event.ID),
+		attribute.String("orders.event_type", string(event.Type)),
+		attribute.String("orders.order_id", event.OrderID),
+	)
+
+	ctx = service.WithAudit(ctx, service.AuditInfo{
+		Actor:     "kafka:" + msg.Topic,
+		Source:    service.ChangeSourceKafka,
+		RequestID: event.ID,
+	})
 
+	var handle func(ctx context.Context, event *PaymentEvent) error
 	switch event.Type {
 	case PaymentEventCompleted:
-		c.handlePaymentCompleted(ctx, &event)
+		handle = c.handlePaymentCompleted
 	case PaymentEventFailed:
-		c.handlePaymentFailed(ctx, &event)
+		handle = c.handlePaymentFailed
 	case PaymentEventRefunded:
-		c.handlePaymentRefunded(ctx, &event)
+		handle = c.handlePaymentRefunded
 	default:
 		c.logger.Debug("Ignoring unknown event type", logging.Fields{"type": event.Type})
+		recordConsumed(msg.Topic, "unknown", "ignored")
+		return nil
 	}
+
+	applied, err := c.orderService.ApplyOnce(ctx, paymentEventsConsumer, event.ID, func(ctx context.Context) error {
+		return handle(ctx, event)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		recordConsumed(msg.Topic, string(event.Type), "failed")
+		return err
+	}
+	if !applied {
+		span.SetAttributes(attribute.Bool("orders.duplicate", true))
+		recordConsumed(msg.Topic, string(event.Type), "duplicate")
+		return nil
+	}
+	recordConsumed(msg.Topic, string(event.Type), "handled")
+	return nil
 }
 
-func (c *KafkaConsumer) handlePaymentCompleted(ctx context.Context, event *PaymentEvent) {
+func (c *KafkaConsumer) handlePaymentCompleted(ctx context.Context, event *PaymentEvent) error {
 	c.logger.Info("Handling payment completed event", logging.Fields{
 		"payment_id": event.PaymentID,
 		"order_id":   event.OrderID,
 	})
 
-	// Update order status to confirmed
-	req := &models.UpdateOrderStatusRequest{
-		Status: models.OrderStatusConfirmed,
-		Notes:  "Payment completed via event",
-	}
-
-	_, err := c.orderService.UpdateOrderStatus(ctx, event.OrderID, req)
+	err := c.orderService.ApplyPaymentCompleted(ctx, event.OrderID, event.PaymentID, "Payment completed via event")
 	if err != nil {
 		c.logger.Error("Failed to update order status", logging.Fields{
 			"order_id": event.OrderID,
 			"error":    err.Error(),
 		})
 	}
+	return err
 }
 
-func (c *KafkaConsumer) handlePaymentFailed(ctx context.Context, event *PaymentEvent) {
+func (c *KafkaConsumer) handlePaymentFailed(ctx context.Context, event *PaymentEvent) error {
 	c.logger.Info("Handling payment failed event", logging.Fields{
 		"payment_id": event.PaymentID,
 		"order_id":   event.OrderID,
 	})
 
 	// Cancel the order due to payment failure
-	_, err := c.orderService.CancelOrder(ctx, event.OrderID, "Payment failed")
+	err := c.orderService.ApplyPaymentFailed(ctx, event.OrderID, "Payment failed")
 	if err != nil {
 		c.logger.Error("Failed to cancel order", logging.Fields{
 			"order_id": event.OrderID,
 			"error":    err.Error(),
 		})
 	}
+	return err
 }
 
-func (c *KafkaConsumer) handlePaymentRefunded(ctx context.Context, event *PaymentEvent) {
+func (c *KafkaConsumer) handlePaymentRefunded(ctx context.Context, event *PaymentEvent) error {
 	c.logger.Info("Handling payment refunded event", logging.Fields{
 		"payment_id": event.PaymentID,
 		"order_id":   event.OrderID,
 	})
 
-	// Update order status to refunded
-	req := &models.UpdateOrderStatusRequest{
-		Status: models.OrderStatusRefunded,
-		Notes:  "Payment refunded via event",
+	if event.RefundID != "" {
+		reconciled, err := c.orderService.ReconcileRefund(ctx, event.OrderID, event.RefundID, models.PaymentStatus(event.Status))
+		if err != nil {
+			c.logger.Error("Failed to reconcile refund", logging.Fields{
+				"order_id":  event.OrderID,
+				"refund_id": event.RefundID,
+				"error":     err.Error(),
+			})
+			return err
+		}
+		if reconciled {
+			return nil
+		}
 	}
 
-	_, err := c.orderService.UpdateOrderStatus(ctx, event.OrderID, req)
+	err := c.orderService.ApplyPaymentRefunded(ctx, event.OrderID, "Payment refunded via event")
 	if err != nil {
 		c.logger.Error("Failed to update order status", logging.Fields{
 			"order_id": event.OrderID,
 			"error":    err.Error(),
 		})
 	}
+	return err
 }
 
 // LegacyEventConsumer is the deprecated event consumer.
@@ -225,6 +603,10 @@ func (c *LegacyEventConsumer) HandlePaymentEventLegacy(orderID string, status st
 		Notes:  "Updated via legacy event",
 	}
 
-	_, err := c.orderService.UpdateOrderStatus(context.Background(), orderID, req)
+	ctx := service.WithAudit(context.Background(), service.AuditInfo{
+		Actor:  "legacy-consumer",
+		Source: service.ChangeSourceKafka,
+	})
+	_, err := c.orderService.UpdateOrderStatus(ctx, orderID, repository.AnyVersion, req)
 	return err
 }
@@ -0,0 +1,142 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// HeaderReplayedAt is set on a dead-lettered message when it is replayed.
+const HeaderReplayedAt = "x-replayed-at"
+
+// DeadLetterReplayer republishes dead-lettered payment events to the topic
+// they were first read from, typically once the cause has been fixed.
+type DeadLetterReplayer struct {
+	reader        *kafka.Reader
+	writer        *kafka.Writer
+	paymentsTopic string
+	logger        *logging.LoggerV2
+}
+
+// ReplayOptions bound a replay run.
+type ReplayOptions struct {
+	// Limit is the maximum number of messages replayed; 0 means all.
+	Limit int
+	// Idle ends the run once no message has arrived for this long.
+	Idle time.Duration
+	// DryRun logs the messages that would be replayed without publishing
+	// or committing them.
+	DryRun bool
+}
+
+// NewDeadLetterReplayer creates a replayer reading the payments
+// dead-letter topic with its own consumer group.
+func NewDeadLetterReplayer(cfg config.KafkaConfig, logger *logging.LoggerV2) *DeadLetterReplayer {
+	return &DeadLetterReplayer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     cfg.Brokers,
+			Topic:       cfg.PaymentsDLQTopic,
+			GroupID:     cfg.ConsumerGroup + ".dlq-replay",
+			StartOffset: kafka.FirstOffset,
+			MinBytes:    1,
+			MaxBytes:    10e6,
+			MaxWait:     time.Second,
+		}),
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			WriteTimeout: 10 * time.Second,
+			RequiredAcks: kafka.RequireAll,
+		},
+		paymentsTopic: cfg.PaymentsTopic,
+		logger:        logger,
+	}
+}
+
+// Replay republishes dead-lettered messages and returns how many it
+// replayed. Each message is committed on the dead-letter topic only after
+// it has been written back, so an interrupted run can simply be repeated.
+func (r *DeadLetterReplayer) Replay(ctx context.Context, opts ReplayOptions) (int, error) {
+	replayed := 0
+	for opts.Limit <= 0 || replayed < opts.Limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, opts.Idle)
+		msg, err := r.reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return replayed, ctx.Err()
+			}
+			if fetchCtx.Err() == context.DeadlineExceeded {
+				return replayed, nil
+			}
+			return replayed, err
+		}
+
+		out := replayMessage(msg, r.paymentsTopic, time.Now())
+		headers := kafkaHeaders{msg: &msg}
+		fields := logging.Fields{
+			"dlq_offset":      msg.Offset,
+			"topic":           out.Topic,
+			"original_offset": headers.Get(HeaderOriginalOffset),
+			"attempts":        headers.Get(HeaderRetryAttempt),
+			"error":           headers.Get(HeaderError),
+		}
+
+		if opts.DryRun {
+			r.logger.Info("Would replay dead-lettered message", fields)
+			replayed++
+			continue
+		}
+
+		if err := r.writer.WriteMessages(ctx, out); err != nil {
+			return replayed, err
+		}
+		if err := r.reader.CommitMessages(ctx, msg); err != nil {
+			return replayed, err
+		}
+		recordReplayed(out.Topic)
+		r.logger.Info("Replayed dead-lettered message", fields)
+		replayed++
+	}
+	return replayed, nil
+}
+
+// Close closes the replayer's reader and writer.
+func (r *DeadLetterReplayer) Close() error {
+	readerErr := r.reader.Close()
+	if err := r.writer.Close(); err != nil {
+		return err
+	}
+	return readerErr
+}
+
+// replayMessage returns the copy of a dead-lettered msg to publish again:
+// sent to its original topic, with the failure headers removed so it
+// starts a fresh round of retries.
+func replayMessage(msg kafka.Message, defaultTopic string, now time.Time) kafka.Message {
+	topic := kafkaHeaders{msg: &msg}.Get(HeaderOriginalTopic)
+	if topic == "" {
+		topic = defaultTopic
+	}
+
+	out := kafka.Message{Topic: topic, Key: msg.Key, Value: msg.Value}
+	for _, header := range msg.Headers {
+		if !isFailureHeader(header.Key) && header.Key != HeaderReplayedAt {
+			out.Headers = append(out.Headers, header)
+		}
+	}
+	kafkaHeaders{msg: &out}.Set(HeaderReplayedAt, now.UTC().Format(time.RFC3339Nano))
+	return out
+}
+
+func isFailureHeader(key string) bool {
+	for _, failure := range failureHeaders {
+		if key == failure {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package events
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/tm-acme-shop/acme-shop-orders-service acmeshop/orders/v1/events.proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/events/eventspb"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Content types of event data, sent in the content-type header.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/protobuf"
+)
+
+// Event encodings selectable with KAFKA_EVENT_ENCODING.
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+// EventEncoder converts event data between its canonical JSON form, the
+// one the schemas describe and the outbox stores, and a Kafka message
+// value.
+type EventEncoder interface {
+	// ContentType is the content-type of the values the encoder writes.
+	ContentType() string
+	// EncodeOrderEvent returns the message value for an order event's data.
+	EncodeOrderEvent(eventType EventType, data json.RawMessage) ([]byte, error)
+	// DecodePaymentEvent returns the JSON data of a payment event's value.
+	DecodePaymentEvent(eventType PaymentEventType, value []byte) (json.RawMessage, error)
+}
+
+// NewEventEncoder returns the encoder for a configured encoding.
+func NewEventEncoder(encoding string) (EventEncoder, error) {
+	switch encoding {
+	case EncodingJSON, "":
+		return JSONEncoder{}, nil
+	case EncodingProtobuf:
+		return ProtobufEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown event encoding %q", encoding)
+	}
+}
+
+// encoderForContentType picks the encoder that reads a message, so
+// producers can move between encodings without coordinating with us.
+// Messages without a content-type are JSON.
+func encoderForContentType(contentType string) (EventEncoder, error) {
+	if contentType == "" {
+		return JSONEncoder{}, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+	switch mediaType {
+	case ContentTypeJSON:
+		return JSONEncoder{}, nil
+	case ContentTypeProtobuf, "application/x-protobuf":
+		return ProtobufEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+}
+
+// JSONEncoder writes event data as the JSON its schema describes.
+type JSONEncoder struct{}
+
+// ContentType implements EventEncoder.
+func (JSONEncoder) ContentType() string {
+	return ContentTypeJSON
+}
+
+// EncodeOrderEvent implements EventEncoder.
+func (JSONEncoder) EncodeOrderEvent(eventType EventType, data json.RawMessage) ([]byte, error) {
+	return data, nil
+}
+
+// DecodePaymentEvent implements EventEncoder.
+func (JSONEncoder) DecodePaymentEvent(eventType PaymentEventType, value []byte) (json.RawMessage, error) {
+	return value, nil
+}
+
+// ProtobufEncoder writes event data as the messages defined in
+// proto/acmeshop/orders/v1/events.proto.
+type ProtobufEncoder struct{}
+
+// protoJSON reads canonical JSON data into protobuf messages. Fields the
+// .proto does not define yet are dropped rather than failing the event.
+var protoJSON = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// ContentType implements EventEncoder.
+func (ProtobufEncoder) ContentType() string {
+	return ContentTypeProtobuf
+}
+
+// EncodeOrderEvent implements EventEncoder. The data is wrapped in an
+// eventspb.OrderEvent whose case matches eventType.
+func (ProtobufEncoder) EncodeOrderEvent(eventType EventType, data json.RawMessage) ([]byte, error) {
+	event := &eventspb.OrderEvent{}
+	var payload proto.Message
+	switch eventType {
+	case EventTypeOrderCreated:
+		m := &eventspb.OrderCreated{}
+		event.Data, payload = &eventspb.OrderEvent_OrderCreated{OrderCreated: m}, m
+	case EventTypeOrderStatusChanged:
+		m := &eventspb.OrderStatusChanged{}
+		event.Data, payload = &eventspb.OrderEvent_OrderStatusChanged{OrderStatusChanged: m}, m
+	case EventTypeOrderCancelled:
+		m := &eventspb.OrderCancelled{}
+		event.Data, payload = &eventspb.OrderEvent_OrderCancelled{OrderCancelled: m}, m
+	case EventTypeOrderRefunded:
+		m := &eventspb.OrderRefunded{}
+		event.Data, payload = &eventspb.OrderEvent_OrderRefunded{OrderRefunded: m}, m
+	case EventTypeShipmentCreated:
+		m := &eventspb.ShipmentCreated{}
+		event.Data, payload = &eventspb.OrderEvent_ShipmentCreated{ShipmentCreated: m}, m
+	case EventTypeShipmentStatusChanged:
+		m := &eventspb.ShipmentStatusChanged{}
+		event.Data, payload = &eventspb.OrderEvent_ShipmentStatusChanged{ShipmentStatusChanged: m}, m
+	default:
+		return nil, fmt.Errorf("no protobuf message for event type %q", eventType)
+	}
+
+	if err := protoJSON.Unmarshal(data, payload); err != nil {
+		return nil, fmt.Errorf("converting %s data to protobuf: %w", eventType, err)
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(event)
+}
+
+// DecodePaymentEvent implements EventEncoder.
+func (ProtobufEncoder) DecodePaymentEvent(eventType PaymentEventType, value []byte) (json.RawMessage, error) {
+	var event eventspb.PaymentEvent
+	if err := proto.Unmarshal(value, &event); err != nil {
+		return nil, err
+	}
+
+	data := paymentEventData{
+		PaymentID:     event.GetPaymentId(),
+		OrderID:       event.GetOrderId(),
+		Status:        event.GetStatus(),
+		FailureReason: event.GetFailureReason(),
+		RefundID:      event.GetRefundId(),
+	}
+	if amount := event.GetAmount(); amount != nil {
+		data.Amount = &models.Money{Amount: amount.GetAmount(), Currency: amount.GetCurrency()}
+	}
+	return json.Marshal(data)
+}
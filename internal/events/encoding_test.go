@@ -0,0 +1,129 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/events/eventspb"
+	"google.golang.org/protobuf/proto"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// checkGolden compares got with testdata/golden/name, rewriting the file
+// instead when -update is set.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match the golden file; if the change is intended, run go test -update", name)
+	}
+}
+
+func TestOrderEventEncodingsMatchGoldenFiles(t *testing.T) {
+	for _, event := range testEvents(t) {
+		value, err := JSONEncoder{}.EncodeOrderEvent(event.Type, event.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", event.Type, err)
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, value, "", "  "); err != nil {
+			t.Fatalf("%s: %v", event.Type, err)
+		}
+		checkGolden(t, string(event.Type)+".json", append(indented.Bytes(), '\n'))
+
+		value, err = ProtobufEncoder{}.EncodeOrderEvent(event.Type, event.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", event.Type, err)
+		}
+		checkGolden(t, string(event.Type)+".pb", value)
+	}
+}
+
+func TestProtobufOrderEventCarriesTypedData(t *testing.T) {
+	event := testEvents(t)[1]
+	value, err := ProtobufEncoder{}.EncodeOrderEvent(event.Type, event.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded eventspb.OrderEvent
+	if err := proto.Unmarshal(value, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	changed := decoded.GetOrderStatusChanged()
+	if changed == nil {
+		t.Fatalf("expected the order_status_changed case, got %v", decoded.GetData())
+	}
+	order := changed.GetOrder()
+	if order.GetId() != "ord_1" || order.GetTotal().GetAmount() != 2500 || changed.GetPreviousStatus() != "pending" {
+		t.Errorf("unexpected data %v", changed)
+	}
+	if got := order.GetCreatedAt().AsTime(); !got.Equal(testOrder().CreatedAt) {
+		t.Errorf("expected created_at %v, got %v", testOrder().CreatedAt, got)
+	}
+}
+
+func TestPaymentEventDecodingsMatchGoldenFiles(t *testing.T) {
+	event := &eventspb.PaymentEvent{
+		PaymentId:     "pay_1",
+		OrderId:       "ord_1",
+		Status:        "failed",
+		Amount:        &eventspb.Money{Amount: 3200, Currency: "USD"},
+		FailureReason: "card_declined",
+	}
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "payment.failed.pb", value)
+
+	// Both encodings decode to the same JSON data.
+	fromProtobuf, err := ProtobufEncoder{}.DecodePaymentEvent(PaymentEventFailed, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "payment.failed.json", append(fromProtobuf, '\n'))
+
+	fromJSON, err := JSONEncoder{}.DecodePaymentEvent(PaymentEventFailed, fromProtobuf)
+	if err != nil || !bytes.Equal(fromJSON, fromProtobuf) {
+		t.Errorf("expected JSON data to be read as is, got %s, %v", fromJSON, err)
+	}
+}
+
+func TestEncoderForContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"", ContentTypeJSON},
+		{"application/json; charset=utf-8", ContentTypeJSON},
+		{"application/protobuf", ContentTypeProtobuf},
+		{"application/x-protobuf", ContentTypeProtobuf},
+	}
+	for _, tt := range tests {
+		encoder, err := encoderForContentType(tt.contentType)
+		if err != nil || encoder.ContentType() != tt.want {
+			t.Errorf("encoderForContentType(%q) = %v, %v; want %s", tt.contentType, encoder, err, tt.want)
+		}
+	}
+
+	if _, err := encoderForContentType("application/avro"); err == nil {
+		t.Error("expected an unsupported content type to be rejected")
+	}
+}
@@ -0,0 +1,20 @@
+package events
+
+import (
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// NewInboxPruner creates a pruner that deletes processed events older than
+// the configured retention, keeping the inbox table from growing without
+// bound.
+func NewInboxPruner(store repository.ProcessedEventStore, cfg config.InboxConfig, logger *logging.LoggerV2) *Pruner {
+	p := NewPruner("processed events", store.DeleteProcessedBefore, PruneConfig{
+		Retention: cfg.Retention,
+		Interval:  cfg.PruneInterval,
+		BatchSize: cfg.PruneBatchSize,
+	}, logger)
+	p.recordPruned = recordInboxPruned
+	return p
+}
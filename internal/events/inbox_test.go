@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// agedInbox holds processed events by processing time.
+type agedInbox struct {
+	processedAt []time.Time
+	batches     int
+}
+
+func (a *agedInbox) MarkProcessed(ctx context.Context, consumer, eventID string) (bool, error) {
+	return true, nil
+}
+
+func (a *agedInbox) DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	a.batches++
+	var kept []time.Time
+	var deleted int64
+	for _, at := range a.processedAt {
+		if at.Before(before) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	a.processedAt = kept
+	return deleted, nil
+}
+
+func TestInboxPrunerDeletesExpiredEntriesInBatches(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	inbox := &agedInbox{}
+	for i := 0; i < 5; i++ {
+		inbox.processedAt = append(inbox.processedAt, now.Add(-40*24*time.Hour))
+	}
+	inbox.processedAt = append(inbox.processedAt, now.Add(-time.Hour))
+
+	p := NewInboxPruner(inbox, config.InboxConfig{
+		Retention:      30 * 24 * time.Hour,
+		PruneInterval:  time.Hour,
+		PruneBatchSize: 2,
+	}, logging.NewLoggerV2("test"))
+	p.now = func() time.Time { return now }
+
+	deleted, err := p.prune(context.Background())
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if deleted != 5 || len(inbox.processedAt) != 1 {
+		t.Errorf("expected the 5 expired entries to be deleted, deleted %d and kept %d", deleted, len(inbox.processedAt))
+	}
+	if inbox.batches != 3 {
+		t.Errorf("expected 3 batches of at most 2, got %d", inbox.batches)
+	}
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	stderrors "errors"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/segmentio/kafka-go"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/schemas"
+)
+
+var (
+	kafkaPublishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_kafka_messages_published_total",
+		Help: "Messages written to Kafka by topic, event type and result (ok, error or invalid).",
+	}, []string{"topic", "event_type", "result"})
+	kafkaConsumedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_kafka_messages_consumed_total",
+		Help: "Messages read from Kafka by topic, event type and result (handled, duplicate, failed, ignored or invalid).",
+	}, []string{"topic", "event_type", "result"})
+	kafkaForwardedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_kafka_messages_forwarded_total",
+		Help: "Failed messages moved to a retry or dead-letter topic by source topic and destination.",
+	}, []string{"topic", "destination"})
+	kafkaReplayedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_kafka_dlq_messages_replayed_total",
+		Help: "Dead-lettered messages republished by target topic.",
+	}, []string{"topic"})
+	kafkaInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "orders_kafka_messages_in_flight",
+		Help: "Messages fetched from Kafka and being handled by topic.",
+	}, []string{"topic"})
+	inboxPrunedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orders_inbox_pruned_events_total",
+		Help: "Processed events deleted from the inbox after their retention.",
+	})
+	idempotencyPrunedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orders_idempotency_pruned_keys_total",
+		Help: "Expired idempotency keys deleted.",
+	})
+	kafkaConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "orders_kafka_consumer_lag_messages",
+		Help: "Messages between the last one read and the partition's high water mark.",
+	}, []string{"topic", "partition"})
+)
+
+func recordPublished(topic, eventType string, err error) {
+	result := "ok"
+	var invalid *schemas.ValidationError
+	switch {
+	case stderrors.As(err, &invalid) || stderrors.Is(err, schemas.ErrUnknownSchema):
+		result = "invalid"
+	case err != nil:
+		result = "error"
+	}
+	kafkaPublishedTotal.WithLabelValues(topic, eventType, result).Inc()
+}
+
+func recordConsumed(topic, eventType, result string) {
+	kafkaConsumedTotal.WithLabelValues(topic, eventType, result).Inc()
+}
+
+func recordForwarded(topic, destination string) {
+	kafkaForwardedTotal.WithLabelValues(topic, destination).Inc()
+}
+
+func recordReplayed(topic string) {
+	kafkaReplayedTotal.WithLabelValues(topic).Inc()
+}
+
+func recordInboxPruned(deleted int64) {
+	inboxPrunedTotal.Add(float64(deleted))
+}
+
+func recordIdempotencyPruned(deleted int64) {
+	idempotencyPrunedTotal.Add(float64(deleted))
+}
+
+// recordConsumerLag derives the partition lag from the high water mark the
+// broker returned alongside msg.
+func recordConsumerLag(msg kafka.Message) {
+	lag := msg.HighWaterMark - msg.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+	kafkaConsumerLag.WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).Set(float64(lag))
+}
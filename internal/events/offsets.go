@@ -0,0 +1,80 @@
+package events
+
+import (
+	"hash/fnv"
+	"strconv"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsetTracker decides which offsets may be committed when messages of a
+// partition are handled concurrently. A partition is only committed up to
+// the last message below which every fetched message has been settled, so
+// a restart never skips a message that was still being handled.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	// pending holds the fetched, not yet committable offsets in fetch order.
+	pending []int64
+	settled map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[int]*partitionOffsets)}
+}
+
+// fetched records a message handed to a worker. Offsets fetched out of
+// sequence mean the reader rewound after a rebalance; what was pending is
+// then forgotten, since it will be fetched again.
+func (t *offsetTracker) fetched(msg kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[msg.Partition]
+	if !ok || (len(p.pending) > 0 && msg.Offset <= p.pending[len(p.pending)-1]) {
+		p = &partitionOffsets{settled: make(map[int64]bool)}
+		t.partitions[msg.Partition] = p
+	}
+	p.pending = append(p.pending, msg.Offset)
+}
+
+// settle marks msg as handled and returns the message to commit if the
+// partition's committable offset advanced.
+func (t *offsetTracker) settle(msg kafka.Message) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[msg.Partition]
+	if !ok {
+		return kafka.Message{}, false
+	}
+	p.settled[msg.Offset] = true
+
+	committable := int64(-1)
+	for len(p.pending) > 0 && p.settled[p.pending[0]] {
+		committable = p.pending[0]
+		delete(p.settled, committable)
+		p.pending = p.pending[1:]
+	}
+	if committable < 0 {
+		return kafka.Message{}, false
+	}
+	return kafka.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: committable}, true
+}
+
+// workerFor picks the worker handling msg. Messages with the same key, the
+// order ID, always go to the same worker and so are handled in order;
+// unkeyed messages are kept in order per partition.
+func workerFor(msg kafka.Message, workers int) int {
+	hash := fnv.New32a()
+	if len(msg.Key) > 0 {
+		hash.Write(msg.Key)
+	} else {
+		hash.Write([]byte(strconv.Itoa(msg.Partition)))
+	}
+	return int(hash.Sum32() % uint32(workers))
+}
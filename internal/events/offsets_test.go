@@ -0,0 +1,214 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+func TestOffsetTracker(t *testing.T) {
+	tracker := newOffsetTracker()
+	msg := func(offset int64) kafka.Message {
+		return kafka.Message{Topic: "payments", Partition: 0, Offset: offset}
+	}
+
+	for offset := int64(10); offset <= 12; offset++ {
+		tracker.fetched(msg(offset))
+	}
+
+	if _, ok := tracker.settle(msg(11)); ok {
+		t.Fatal("expected no commit while offset 10 is still being handled")
+	}
+	commit, ok := tracker.settle(msg(10))
+	if !ok || commit.Offset != 11 {
+		t.Fatalf("expected a commit up to 11, got %v %v", commit.Offset, ok)
+	}
+
+	// A rebalance rewinds the reader; pending offsets are forgotten.
+	tracker.fetched(msg(11))
+	if _, ok := tracker.settle(msg(12)); ok {
+		t.Error("expected offset 12 to be forgotten after the rewind")
+	}
+	if commit, ok := tracker.settle(msg(11)); !ok || commit.Offset != 11 {
+		t.Errorf("expected a commit up to 11, got %v %v", commit.Offset, ok)
+	}
+}
+
+func TestWorkerForKeepsKeysTogether(t *testing.T) {
+	a := kafka.Message{Partition: 1, Key: []byte("ord_1")}
+	b := kafka.Message{Partition: 2, Key: []byte("ord_1")}
+	if workerFor(a, 8) != workerFor(b, 8) {
+		t.Error("expected messages for the same order to go to the same worker")
+	}
+}
+
+// fakeReader serves a fixed set of messages, then blocks until cancelled.
+type fakeReader struct {
+	mu        sync.Mutex
+	messages  []kafka.Message
+	committed map[int]int64
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.mu.Lock()
+	if len(r.messages) > 0 {
+		msg := r.messages[0]
+		r.messages = r.messages[1:]
+		r.mu.Unlock()
+		return msg, nil
+	}
+	r.mu.Unlock()
+	<-ctx.Done()
+	return kafka.Message{}, ctx.Err()
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgs {
+		r.committed[msg.Partition] = msg.Offset
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+func (r *fakeReader) remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.messages)
+}
+
+func TestKafkaConsumerDrainsOnStop(t *testing.T) {
+	reader := &fakeReader{committed: make(map[int]int64)}
+	for partition := 0; partition < 2; partition++ {
+		for offset := int64(0); offset < 20; offset++ {
+			reader.messages = append(reader.messages, kafka.Message{
+				Topic:     "payments",
+				Partition: partition,
+				Offset:    offset,
+				Key:       []byte(fmt.Sprintf("ord_%d", offset%5)),
+				// Undecodable, so each is dead-lettered and committed.
+				Value: []byte("not json"),
+			})
+		}
+	}
+
+	writer := &recordingWriter{}
+	c := &KafkaConsumer{
+		writer:   &lockedWriter{w: writer},
+		dlqTopic: "payments.dlq",
+		workers:  4,
+		logger:   logging.NewLoggerV2("test"),
+		now:      time.Now,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	c.addStage(reader, 0)
+
+	started := make(chan error, 1)
+	go func() { started <- c.Start(context.Background()) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for reader.remaining() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := <-started; err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if len(writer.messages) != 40 {
+		t.Errorf("expected all 40 messages to be handled, got %d", len(writer.messages))
+	}
+	for partition := 0; partition < 2; partition++ {
+		if offset := reader.committed[partition]; offset != 19 {
+			t.Errorf("expected partition %d to be committed up to 19, got %d", partition, offset)
+		}
+	}
+}
+
+type lockedWriter struct {
+	mu sync.Mutex
+	w  *recordingWriter
+}
+
+func (l *lockedWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.WriteMessages(ctx, msgs...)
+}
+
+func (l *lockedWriter) Close() error { return nil }
+
+// failingReader fails every fetch until cancelled.
+type failingReader struct {
+	fetches atomic.Int32
+}
+
+func (r *failingReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.fetches.Add(1)
+	if err := ctx.Err(); err != nil {
+		return kafka.Message{}, err
+	}
+	return kafka.Message{}, errors.New("broker unreachable")
+}
+
+func (r *failingReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error { return nil }
+func (r *failingReader) Close() error                                                    { return nil }
+
+func TestKafkaConsumerBacksOffFailedFetches(t *testing.T) {
+	reader := &failingReader{}
+	c := &KafkaConsumer{
+		writer:  &lockedWriter{w: &recordingWriter{}},
+		workers: 1,
+		logger:  logging.NewLoggerV2("test"),
+		now:     time.Now,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	c.addStage(reader, 0)
+
+	started := make(chan error, 1)
+	go func() { started <- c.Start(context.Background()) }()
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := <-started; err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// The first failure waits at least fetchBackoffMin/2 before the next
+	// fetch, and Stop interrupts that wait.
+	if fetches := reader.fetches.Load(); fetches != 1 {
+		t.Errorf("expected a single fetch before backing off, got %d", fetches)
+	}
+}
+
+func TestFetchBackoff(t *testing.T) {
+	for failures := 1; failures <= 40; failures++ {
+		delay := fetchBackoff(failures)
+		if delay < fetchBackoffMin/2 || delay > fetchBackoffMax {
+			t.Errorf("fetchBackoff(%d) = %s, outside [%s, %s]", failures, delay, fetchBackoffMin/2, fetchBackoffMax)
+		}
+	}
+	if delay := fetchBackoff(20); delay < fetchBackoffMax/2 {
+		t.Errorf("expected the delay to be capped near %s, got %s", fetchBackoffMax, delay)
+	}
+}
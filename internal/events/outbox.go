@@ -0,0 +1,305 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/schemas"
+	"github.com/tm-acme-shop/acme-shop-shared-go/interfaces"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// Ensure OutboxPublisher implements interfaces.OrderEventPublisher
+var _ interfaces.OrderEventPublisher = (*OutboxPublisher)(nil)
+
+var (
+	outboxPendingMessages = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "orders_outbox_pending_messages",
+		Help: "Number of order events waiting in the outbox.",
+	})
+	outboxFailedMessages = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "orders_outbox_failed_messages",
+		Help: "Number of order events that exhausted their delivery attempts.",
+	})
+	outboxLagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "orders_outbox_lag_seconds",
+		Help: "Age of the oldest undelivered order event in the outbox.",
+	})
+	outboxRelayedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_outbox_relayed_total",
+		Help: "Outbox relay delivery attempts by result.",
+	}, []string{"result"})
+	outboxPrunedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orders_outbox_pruned_messages_total",
+		Help: "Delivered order events deleted from the outbox after their retention.",
+	})
+)
+
+// OutboxPublisher implements interfaces.OrderEventPublisher by writing events
+// to the order_outbox table. Callers pass the transactional context of the
+// order change so the event is committed atomically with it.
+type OutboxPublisher struct {
+	outbox repository.OutboxRepository
+	logger *logging.LoggerV2
+}
+
+// NewOutboxPublisher creates a new outbox-backed event publisher.
+func NewOutboxPublisher(outbox repository.OutboxRepository, logger *logging.LoggerV2) *OutboxPublisher {
+	return &OutboxPublisher{
+		outbox: outbox,
+		logger: logger,
+	}
+}
+
+// PublishOrderCreated enqueues an order created event.
+func (p *OutboxPublisher) PublishOrderCreated(ctx context.Context, order *models.Order) error {
+	event, err := NewOrderCreatedEvent(ctx, order)
+	if err != nil {
+		return err
+	}
+	return p.enqueue(ctx, event)
+}
+
+// PublishOrderStatusChanged enqueues an order status changed event.
+func (p *OutboxPublisher) PublishOrderStatusChanged(ctx context.Context, order *models.Order, previousStatus models.OrderStatus) error {
+	event, err := NewOrderStatusChangedEvent(ctx, order, previousStatus)
+	if err != nil {
+		return err
+	}
+	return p.enqueue(ctx, event)
+}
+
+// PublishOrderCancelled enqueues an order cancelled event.
+func (p *OutboxPublisher) PublishOrderCancelled(ctx context.Context, order *models.Order, reason string) error {
+	event, err := NewOrderCancelledEvent(ctx, order, reason)
+	if err != nil {
+		return err
+	}
+	return p.enqueue(ctx, event)
+}
+
+func (p *OutboxPublisher) enqueue(ctx context.Context, event *OrderEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.outbox.Enqueue(ctx, &repository.OutboxMessage{
+		EventID:     event.ID,
+		EventType:   string(event.Type),
+		AggregateID: event.OrderID,
+		Payload:     payload,
+	})
+}
+
+// OutboxRelay drains the order_outbox table into Kafka.
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository
+	tx        repository.Transactor
+	publisher *KafkaPublisher
+	cfg       config.OutboxConfig
+	logger    *logging.LoggerV2
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewOutboxRelay creates a new outbox relay.
+func NewOutboxRelay(
+	outbox repository.OutboxRepository,
+	tx repository.Transactor,
+	publisher *KafkaPublisher,
+	cfg config.OutboxConfig,
+	logger *logging.LoggerV2,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		tx:        tx,
+		publisher: publisher,
+		cfg:       cfg,
+		logger:    logger,
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+// Start polls the outbox until ctx is cancelled or Stop is called.
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	defer close(r.doneCh)
+
+	r.logger.Info("Starting outbox relay", logging.Fields{
+		"poll_interval": r.cfg.PollInterval.String(),
+		"batch_size":    r.cfg.BatchSize,
+	})
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back.
+		for {
+			relayed, err := r.relayBatch(ctx)
+			if err != nil {
+				r.logger.Error("Outbox relay batch failed", logging.Fields{"error": err.Error()})
+				break
+			}
+			if relayed < r.cfg.BatchSize {
+				break
+			}
+		}
+		r.updateLagMetrics(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.stopCh:
+			r.logger.Info("Outbox relay stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals the relay to stop and waits for the current batch to finish.
+func (r *OutboxRelay) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+	<-r.doneCh
+}
+
+// relayBatch claims a batch and publishes it inside one transaction, so the
+// claimed rows stay locked while Kafka is written to. Publishing is bounded
+// by BatchTimeout: once it expires the remaining messages are released
+// unpublished and picked up by the next batch.
+func (r *OutboxRelay) relayBatch(ctx context.Context) (int, error) {
+	relayed := 0
+
+	err := r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		messages, err := r.outbox.ClaimPending(ctx, r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		publishCtx := ctx
+		if r.cfg.BatchTimeout > 0 {
+			var cancel context.CancelFunc
+			publishCtx, cancel = context.WithTimeout(ctx, r.cfg.BatchTimeout)
+			defer cancel()
+		}
+
+		for _, msg := range messages {
+			if publishCtx.Err() != nil {
+				r.logger.Info("Outbox batch timed out, releasing remaining messages", logging.Fields{
+					"relayed":  relayed,
+					"released": len(messages) - relayed,
+				})
+				break
+			}
+			if err := r.relay(ctx, publishCtx, msg); err != nil {
+				return err
+			}
+			relayed++
+		}
+		return nil
+	})
+
+	return relayed, err
+}
+
+// relay publishes a single message and records the outcome. Only bookkeeping
+// errors are returned; publish failures are scheduled for retry, except for
+// events that do not match their schema, which no retry would fix. The
+// publish uses publishCtx and the bookkeeping uses the transaction's ctx, so
+// an expired batch timeout still lets the outcome be recorded.
+func (r *OutboxRelay) relay(ctx, publishCtx context.Context, msg *repository.OutboxMessage) error {
+	var event OrderEvent
+	err := json.Unmarshal(msg.Payload, &event)
+	if err == nil {
+		err = upgradeLegacyEvent(&event)
+	}
+	if err != nil {
+		r.logger.Error("Discarding undecodable outbox message", logging.Fields{
+			"outbox_id": msg.ID,
+			"error":     err.Error(),
+		})
+		outboxRelayedTotal.WithLabelValues("failed").Inc()
+		return r.outbox.MarkFailed(ctx, msg.ID, err.Error())
+	}
+
+	publishErr := r.publisher.PublishEvent(publishCtx, &event)
+	if publishErr == nil {
+		outboxRelayedTotal.WithLabelValues("sent").Inc()
+		return r.outbox.MarkSent(ctx, msg.ID)
+	}
+
+	var invalid *schemas.ValidationError
+	if stderrors.As(publishErr, &invalid) || stderrors.Is(publishErr, schemas.ErrUnknownSchema) {
+		outboxRelayedTotal.WithLabelValues("failed").Inc()
+		return r.outbox.MarkFailed(ctx, msg.ID, publishErr.Error())
+	}
+
+	attempts := msg.Attempts + 1
+	if attempts >= r.cfg.MaxAttempts {
+		r.logger.Error("Outbox message exhausted delivery attempts", logging.Fields{
+			"outbox_id": msg.ID,
+			"event_id":  msg.EventID,
+			"attempts":  attempts,
+			"error":     publishErr.Error(),
+		})
+		outboxRelayedTotal.WithLabelValues("failed").Inc()
+		return r.outbox.MarkFailed(ctx, msg.ID, publishErr.Error())
+	}
+
+	outboxRelayedTotal.WithLabelValues("retry").Inc()
+	return r.outbox.MarkRetry(ctx, msg.ID, publishErr.Error(), time.Now().Add(r.backoff(attempts)))
+}
+
+// backoff returns an exponential delay with full jitter, capped at MaxBackoff.
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff << uint(attempts-1)
+	if delay <= 0 || delay > r.cfg.MaxBackoff {
+		delay = r.cfg.MaxBackoff
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func (r *OutboxRelay) updateLagMetrics(ctx context.Context) {
+	stats, err := r.outbox.Stats(ctx)
+	if err != nil {
+		r.logger.Error("Failed to read outbox stats", logging.Fields{"error": err.Error()})
+		return
+	}
+
+	outboxPendingMessages.Set(float64(stats.Pending))
+	outboxFailedMessages.Set(float64(stats.Failed))
+	if stats.OldestPendingAt != nil {
+		outboxLagSeconds.Set(time.Since(*stats.OldestPendingAt).Seconds())
+	} else {
+		outboxLagSeconds.Set(0)
+	}
+}
+
+// NewOutboxPruner creates a pruner that deletes delivered messages older than
+// the configured retention, keeping the outbox table from growing without
+// bound. Failed messages are kept for inspection.
+func NewOutboxPruner(outbox repository.OutboxRepository, cfg config.OutboxConfig, logger *logging.LoggerV2) *Pruner {
+	p := NewPruner("outbox messages", outbox.DeleteSentBefore, PruneConfig{
+		Retention: cfg.Retention,
+		Interval:  cfg.PruneInterval,
+		BatchSize: cfg.PruneBatchSize,
+	}, logger)
+	p.recordPruned = func(deleted int64) {
+		outboxPrunedTotal.Add(float64(deleted))
+	}
+	return p
+}
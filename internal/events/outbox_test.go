@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// sentOutbox holds outbox messages by delivery time. Only pruning is
+// implemented.
+type sentOutbox struct {
+	repository.OutboxRepository
+	sentAt  []time.Time
+	batches int
+}
+
+func (o *sentOutbox) DeleteSentBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	o.batches++
+	var kept []time.Time
+	var deleted int64
+	for _, at := range o.sentAt {
+		if at.Before(before) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	o.sentAt = kept
+	return deleted, nil
+}
+
+func TestOutboxPrunerDeletesDeliveredMessagesPastRetention(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	outbox := &sentOutbox{}
+	for i := 0; i < 3; i++ {
+		outbox.sentAt = append(outbox.sentAt, now.Add(-8*24*time.Hour))
+	}
+	outbox.sentAt = append(outbox.sentAt, now.Add(-6*24*time.Hour))
+
+	p := NewOutboxPruner(outbox, config.OutboxConfig{
+		Retention:      7 * 24 * time.Hour,
+		PruneInterval:  time.Hour,
+		PruneBatchSize: 2,
+	}, logging.NewLoggerV2("test"))
+	p.now = func() time.Time { return now }
+
+	deleted, err := p.prune(context.Background())
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if deleted != 3 || len(outbox.sentAt) != 1 {
+		t.Errorf("expected the 3 expired messages to be deleted, deleted %d and kept %d", deleted, len(outbox.sentAt))
+	}
+	if outbox.batches != 2 {
+		t.Errorf("expected 2 batches of at most 2, got %d", outbox.batches)
+	}
+}
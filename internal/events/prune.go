@@ -0,0 +1,140 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// DeleteBeforeFunc deletes up to limit rows that expired before before and
+// returns how many it deleted.
+type DeleteBeforeFunc func(ctx context.Context, before time.Time, limit int) (int64, error)
+
+// PruneConfig controls a Pruner. Rows are deleted once they are older than
+// Retention, every Interval, at most BatchSize per statement.
+type PruneConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+// Pruner periodically deletes expired rows of one table in bounded
+// batches, keeping it from growing without bound.
+type Pruner struct {
+	name         string
+	deleteBefore DeleteBeforeFunc
+	recordPruned func(deleted int64)
+	cfg          PruneConfig
+	logger       *logging.LoggerV2
+	now          func() time.Time
+	stopCh       chan struct{}
+	doneCh       chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewPruner creates a pruner for the rows that deleteBefore removes. name
+// describes them in logs.
+func NewPruner(name string, deleteBefore DeleteBeforeFunc, cfg PruneConfig, logger *logging.LoggerV2) *Pruner {
+	return &Pruner{
+		name:         name,
+		deleteBefore: deleteBefore,
+		recordPruned: func(int64) {},
+		cfg:          cfg,
+		logger:       logger,
+		now:          time.Now,
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// NewIdempotencyPruner creates a pruner that deletes expired idempotency
+// keys. Acquire takes expired keys over, so nothing reads them once they
+// are past their expiry.
+func NewIdempotencyPruner(store repository.IdempotencyStore, cfg config.IdempotencyConfig, logger *logging.LoggerV2) *Pruner {
+	p := NewPruner("idempotency keys", store.DeleteExpiredBefore, PruneConfig{
+		Interval:  cfg.PruneInterval,
+		BatchSize: cfg.PruneBatchSize,
+	}, logger)
+	p.recordPruned = recordIdempotencyPruned
+	return p
+}
+
+// Start prunes until ctx is cancelled or Stop is called.
+func (p *Pruner) Start(ctx context.Context) error {
+	defer close(p.doneCh)
+
+	p.logger.Info("Starting pruner", logging.Fields{
+		"rows":           p.name,
+		"retention":      p.cfg.Retention.String(),
+		"prune_interval": p.cfg.Interval.String(),
+	})
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.prune(ctx); err != nil {
+			p.logger.Error("Pruning failed", logging.Fields{
+				"rows":  p.name,
+				"error": err.Error(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.stopCh:
+			p.logger.Info("Pruner stopped", logging.Fields{"rows": p.name})
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals the pruner to stop and waits for the current run to finish.
+func (p *Pruner) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+	<-p.doneCh
+}
+
+// prune deletes expired rows batch by batch until none are left and
+// returns how many it deleted.
+func (p *Pruner) prune(ctx context.Context) (int64, error) {
+	cutoff := p.now().Add(-p.cfg.Retention)
+
+	var total int64
+	for {
+		deleted, err := p.deleteBefore(ctx, cutoff, p.cfg.BatchSize)
+		total += deleted
+		p.recordPruned(deleted)
+		if err != nil {
+			return total, err
+		}
+		if deleted < int64(p.cfg.BatchSize) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case <-p.stopCh:
+			return total, nil
+		default:
+		}
+	}
+
+	if total > 0 {
+		p.logger.Info("Pruned expired rows", logging.Fields{
+			"rows":    p.name,
+			"deleted": total,
+			"before":  cutoff,
+		})
+	}
+	return total, nil
+}
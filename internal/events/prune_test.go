@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// expiringKeys holds idempotency keys by expiry. Only pruning is
+// implemented.
+type expiringKeys struct {
+	repository.IdempotencyStore
+	expiresAt []time.Time
+}
+
+func (k *expiringKeys) DeleteExpiredBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var kept []time.Time
+	var deleted int64
+	for _, at := range k.expiresAt {
+		if at.Before(before) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	k.expiresAt = kept
+	return deleted, nil
+}
+
+func TestIdempotencyPrunerDeletesOnlyExpiredKeys(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	keys := &expiringKeys{expiresAt: []time.Time{
+		now.Add(-time.Hour),
+		now.Add(-time.Minute),
+		now.Add(time.Minute),
+	}}
+
+	p := NewIdempotencyPruner(keys, config.IdempotencyConfig{
+		PruneInterval:  time.Hour,
+		PruneBatchSize: 10,
+	}, logging.NewLoggerV2("test"))
+	p.now = func() time.Time { return now }
+
+	deleted, err := p.prune(context.Background())
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if deleted != 2 || len(keys.expiresAt) != 1 {
+		t.Errorf("expected the 2 expired keys to be deleted, deleted %d and kept %d", deleted, len(keys.expiresAt))
+	}
+}
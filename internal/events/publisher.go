@@ -3,15 +3,23 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/schemas"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/tracing"
 	"github.com/tm-acme-shop/acme-shop-shared-go/interfaces"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 	"github.com/tm-acme-shop/acme-shop-shared-go/middleware"
 	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Ensure KafkaPublisher implements interfaces.OrderEventPublisher
@@ -27,13 +35,16 @@ const (
 	EventTypeOrderRefunded      EventType = "order.refunded"
 )
 
-// OrderEvent represents an order-related event.
+// OrderEvent represents an order-related event. It is stored in the outbox
+// as JSON and published as a CloudEvent whose data is Data, described by
+// the schema DataSchema names.
 type OrderEvent struct {
 	ID             string            `json:"id"`
 	Type           EventType         `json:"type"`
 	OrderID        string            `json:"order_id"`
 	UserID         string            `json:"user_id"`
 	Data           json.RawMessage   `json:"data"`
+	DataSchema     string            `json:"data_schema,omitempty"`
 	Metadata       map[string]string `json:"metadata"`
 	Timestamp      time.Time         `json:"timestamp"`
 	CorrelationID  string            `json:"correlation_id,omitempty"`
@@ -41,13 +52,15 @@ type OrderEvent struct {
 
 // KafkaPublisher publishes order events to Kafka.
 type KafkaPublisher struct {
-	writer *kafka.Writer
-	topic  string
-	logger *logging.LoggerV2
+	writer  *kafka.Writer
+	topic   string
+	encoder EventEncoder
+	logger  *logging.LoggerV2
 }
 
-// NewKafkaPublisher creates a new Kafka-based event publisher.
-func NewKafkaPublisher(cfg config.KafkaConfig, logger *logging.LoggerV2) *KafkaPublisher {
+// NewKafkaPublisher creates a new Kafka-based event publisher writing event
+// data with encoder.
+func NewKafkaPublisher(cfg config.KafkaConfig, encoder EventEncoder, logger *logging.LoggerV2) *KafkaPublisher {
 	writer := &kafka.Writer{
 		Addr:         kafka.TCP(cfg.Brokers...),
 		Topic:        cfg.OrdersTopic,
@@ -57,9 +70,10 @@ func NewKafkaPublisher(cfg config.KafkaConfig, logger *logging.LoggerV2) *KafkaP
 	}
 
 	return &KafkaPublisher{
-		writer: writer,
-		topic:  cfg.OrdersTopic,
-		logger: logger,
+		writer:  writer,
+		topic:   cfg.OrdersTopic,
+		encoder: encoder,
+		logger:  logger,
 	}
 }
 
@@ -69,13 +83,11 @@ func (p *KafkaPublisher) PublishOrderCreated(ctx context.Context, order *models.
 		"order_id": order.ID,
 	})
 
-	data, err := json.Marshal(order)
+	event, err := NewOrderCreatedEvent(ctx, order)
 	if err != nil {
 		return err
 	}
-
-	event := p.createEvent(ctx, EventTypeOrderCreated, order.ID, order.UserID, data)
-	return p.publish(ctx, event)
+	return p.PublishEvent(ctx, event)
 }
 
 // PublishOrderStatusChanged publishes an order status change event.
@@ -86,6 +98,45 @@ func (p *KafkaPublisher) PublishOrderStatusChanged(ctx context.Context, order *m
 		"new_status":      order.Status,
 	})
 
+	event, err := NewOrderStatusChangedEvent(ctx, order, previousStatus)
+	if err != nil {
+		return err
+	}
+	return p.PublishEvent(ctx, event)
+}
+
+// PublishOrderCancelled publishes an order cancellation event.
+func (p *KafkaPublisher) PublishOrderCancelled(ctx context.Context, order *models.Order, reason string) error {
+	p.logger.Debug("Publishing order cancelled event", logging.Fields{
+		"order_id": order.ID,
+		"reason":   reason,
+	})
+
+	event, err := NewOrderCancelledEvent(ctx, order, reason)
+	if err != nil {
+		return err
+	}
+	return p.PublishEvent(ctx, event)
+}
+
+// NewOrderCreatedEvent builds an order created event.
+func NewOrderCreatedEvent(ctx context.Context, order *models.Order) (*OrderEvent, error) {
+	payload := struct {
+		Order *models.Order `json:"order"`
+	}{
+		Order: order,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrderEvent(ctx, EventTypeOrderCreated, order.ID, order.UserID, data), nil
+}
+
+// NewOrderStatusChangedEvent builds an order status changed event.
+func NewOrderStatusChangedEvent(ctx context.Context, order *models.Order, previousStatus models.OrderStatus) (*OrderEvent, error) {
 	payload := struct {
 		Order          *models.Order      `json:"order"`
 		PreviousStatus models.OrderStatus `json:"previous_status"`
@@ -98,20 +149,14 @@ func (p *KafkaPublisher) PublishOrderStatusChanged(ctx context.Context, order *m
 
 	data, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	event := p.createEvent(ctx, EventTypeOrderStatusChanged, order.ID, order.UserID, data)
-	return p.publish(ctx, event)
+	return newOrderEvent(ctx, EventTypeOrderStatusChanged, order.ID, order.UserID, data), nil
 }
 
-// PublishOrderCancelled publishes an order cancellation event.
-func (p *KafkaPublisher) PublishOrderCancelled(ctx context.Context, order *models.Order, reason string) error {
-	p.logger.Debug("Publishing order cancelled event", logging.Fields{
-		"order_id": order.ID,
-		"reason":   reason,
-	})
-
+// NewOrderCancelledEvent builds an order cancelled event.
+func NewOrderCancelledEvent(ctx context.Context, order *models.Order, reason string) (*OrderEvent, error) {
 	payload := struct {
 		Order  *models.Order `json:"order"`
 		Reason string        `json:"reason"`
@@ -122,48 +167,85 @@ func (p *KafkaPublisher) PublishOrderCancelled(ctx context.Context, order *model
 
 	data, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	event := p.createEvent(ctx, EventTypeOrderCancelled, order.ID, order.UserID, data)
-	return p.publish(ctx, event)
+	return newOrderEvent(ctx, EventTypeOrderCancelled, order.ID, order.UserID, data), nil
 }
 
-func (p *KafkaPublisher) createEvent(ctx context.Context, eventType EventType, orderID, userID string, data []byte) *OrderEvent {
+func newOrderEvent(ctx context.Context, eventType EventType, orderID, userID string, data []byte) *OrderEvent {
 	event := &OrderEvent{
-		ID:        generateEventID(),
-		Type:      eventType,
-		OrderID:   orderID,
-		UserID:    userID,
-		Data:      data,
-		Metadata:  make(map[string]string),
-		Timestamp: time.Now(),
+		ID:         generateEventID(),
+		Type:       eventType,
+		OrderID:    orderID,
+		UserID:     userID,
+		Data:       data,
+		DataSchema: dataSchemaFor(eventType),
+		Metadata:   make(map[string]string),
+		Timestamp:  time.Now(),
 	}
 
 	// Add correlation ID from context
 	if requestID := ctx.Value(middleware.RequestIDKey); requestID != nil {
 		event.CorrelationID = requestID.(string)
 	}
+	// Carry the trace context with the event so spans started when the
+	// outbox relays it join the originating trace.
+	injectTrace(ctx, propagation.MapCarrier(event.Metadata))
 
 	return event
 }
 
-func (p *KafkaPublisher) publish(ctx context.Context, event *OrderEvent) error {
-	eventData, err := json.Marshal(event)
+// PublishEvent writes an already-built event to Kafka as a CloudEvent. Its
+// data is validated against its schema first, so an event consumers could
+// not read is never published.
+func (p *KafkaPublisher) PublishEvent(ctx context.Context, event *OrderEvent) error {
+	if err := schemas.Default().Validate(event.DataSchema, event.Data); err != nil {
+		recordPublished(p.topic, string(event.Type), err)
+		p.logger.Error("Refusing to publish invalid event", logging.Fields{
+			"event_id":    event.ID,
+			"event_type":  event.Type,
+			"data_schema": event.DataSchema,
+			"error":       err.Error(),
+		})
+		return err
+	}
+
+	msg, err := cloudEventMessage(event, p.encoder)
 	if err != nil {
+		recordPublished(p.topic, string(event.Type), err)
+		p.logger.Error("Failed to encode event", logging.Fields{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+			"encoding":   p.encoder.ContentType(),
+			"error":      err.Error(),
+		})
 		return err
 	}
 
-	msg := kafka.Message{
-		Key:   []byte(event.OrderID),
-		Value: eventData,
-		Headers: []kafka.Header{
-			{Key: "event_type", Value: []byte(event.Type)},
-			{Key: "event_id", Value: []byte(event.ID)},
-		},
+	// The outbox relay publishes outside the request that produced the
+	// event, so fall back to the trace context stored with it.
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = extractTrace(ctx, propagation.MapCarrier(event.Metadata))
 	}
+	ctx, span := tracing.Tracer().Start(ctx, p.topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(p.topic),
+			semconv.MessagingMessageID(event.ID),
+			attribute.String("orders.event_type", string(event.Type)),
+			attribute.String("orders.order_id", event.OrderID),
+		),
+	)
+	defer span.End()
+	injectTrace(ctx, kafkaHeaders{msg: &msg})
 
 	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		recordPublished(p.topic, string(event.Type), err)
 		p.logger.Error("Failed to publish event", logging.Fields{
 			"event_id":   event.ID,
 			"event_type": event.Type,
@@ -172,6 +254,7 @@ func (p *KafkaPublisher) publish(ctx context.Context, event *OrderEvent) error {
 		})
 		return err
 	}
+	recordPublished(p.topic, string(event.Type), nil)
 
 	p.logger.Info("Event published", logging.Fields{
 		"event_id":   event.ID,
@@ -182,6 +265,28 @@ func (p *KafkaPublisher) publish(ctx context.Context, event *OrderEvent) error {
 	return nil
 }
 
+// Ping fetches broker metadata for the orders topic, checking that the
+// cluster is reachable and the topic has a leader for each partition.
+func (p *KafkaPublisher) Ping(ctx context.Context) error {
+	client := &kafka.Client{Addr: p.writer.Addr}
+	resp, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{p.topic}})
+	if err != nil {
+		return err
+	}
+
+	for _, topic := range resp.Topics {
+		if topic.Error != nil {
+			return fmt.Errorf("topic %s: %w", topic.Name, topic.Error)
+		}
+		for _, partition := range topic.Partitions {
+			if partition.Error != nil {
+				return fmt.Errorf("topic %s partition %d: %w", topic.Name, partition.ID, partition.Error)
+			}
+		}
+	}
+	return nil
+}
+
 // Close closes the Kafka writer.
 func (p *KafkaPublisher) Close() error {
 	p.logger.Info("Closing Kafka publisher")
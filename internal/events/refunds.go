@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// Ensure the publishers implement service.RefundEventPublisher
+var (
+	_ service.RefundEventPublisher = (*KafkaPublisher)(nil)
+	_ service.RefundEventPublisher = (*OutboxPublisher)(nil)
+	_ service.RefundEventPublisher = (*MockEventPublisher)(nil)
+)
+
+// NewOrderRefundedEvent builds an order refunded event.
+func NewOrderRefundedEvent(ctx context.Context, order *models.Order, refund *repository.OrderRefund, refunded models.Money) (*OrderEvent, error) {
+	payload := struct {
+		Refund        *repository.OrderRefund `json:"refund"`
+		TotalRefunded models.Money            `json:"total_refunded"`
+		Remaining     models.Money            `json:"remaining"`
+		OrderStatus   models.OrderStatus      `json:"order_status"`
+	}{
+		Refund:        refund,
+		TotalRefunded: refunded,
+		Remaining: models.Money{
+			Amount:   order.Total.Amount - refunded.Amount,
+			Currency: order.Total.Currency,
+		},
+		OrderStatus: order.Status,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrderEvent(ctx, EventTypeOrderRefunded, order.ID, order.UserID, data), nil
+}
+
+// PublishOrderRefunded publishes an order refunded event.
+func (p *KafkaPublisher) PublishOrderRefunded(ctx context.Context, order *models.Order, refund *repository.OrderRefund, refunded models.Money) error {
+	p.logger.Debug("Publishing order refunded event", logging.Fields{
+		"order_id":  order.ID,
+		"refund_id": refund.ID,
+		"amount":    refund.Amount.Amount,
+	})
+
+	event, err := NewOrderRefundedEvent(ctx, order, refund, refunded)
+	if err != nil {
+		return err
+	}
+	return p.PublishEvent(ctx, event)
+}
+
+// PublishOrderRefunded enqueues an order refunded event.
+func (p *OutboxPublisher) PublishOrderRefunded(ctx context.Context, order *models.Order, refund *repository.OrderRefund, refunded models.Money) error {
+	event, err := NewOrderRefundedEvent(ctx, order, refund, refunded)
+	if err != nil {
+		return err
+	}
+	return p.enqueue(ctx, event)
+}
+
+func (m *MockEventPublisher) PublishOrderRefunded(ctx context.Context, order *models.Order, refund *repository.OrderRefund, refunded models.Money) error {
+	m.Events = append(m.Events, &OrderEvent{
+		Type:    EventTypeOrderRefunded,
+		OrderID: order.ID,
+	})
+	return nil
+}
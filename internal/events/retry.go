@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+)
+
+// Headers describing why a payment event was moved to a retry or
+// dead-letter topic. The original-* headers always refer to the message
+// as first read from the payments topic.
+const (
+	HeaderRetryAttempt      = "x-retry-attempt"
+	HeaderRetryNotBefore    = "x-retry-not-before"
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderError             = "x-error"
+	HeaderFailedAt          = "x-failed-at"
+)
+
+// failureHeaders are removed when a dead-lettered message is replayed.
+var failureHeaders = []string{
+	HeaderRetryAttempt,
+	HeaderRetryNotBefore,
+	HeaderOriginalTopic,
+	HeaderOriginalPartition,
+	HeaderOriginalOffset,
+	HeaderError,
+	HeaderFailedAt,
+}
+
+// maxErrorHeaderLength bounds the error text carried in HeaderError.
+const maxErrorHeaderLength = 1024
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// isPermanent reports whether err would recur however often the message is
+// retried: undecodable messages, unknown orders and rejected transitions.
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	var validation *errors.ValidationError
+	return stderrors.As(err, &permanent) ||
+		stderrors.Is(err, errors.ErrNotFound) ||
+		stderrors.As(err, &validation)
+}
+
+// retryTopic names the topic for the given retry attempt of base.
+func retryTopic(base string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", base, attempt)
+}
+
+// retryAttempt returns how many times msg has already been retried.
+func retryAttempt(msg kafka.Message) int {
+	attempt, _ := strconv.Atoi(kafkaHeaders{msg: &msg}.Get(HeaderRetryAttempt))
+	return attempt
+}
+
+// failedMessage returns the copy of msg to write to topic after it failed
+// with cause. A zero notBefore means the message is dead-lettered.
+func failedMessage(msg kafka.Message, topic string, attempt int, notBefore, failedAt time.Time, cause error) kafka.Message {
+	out := kafka.Message{
+		Topic:   topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: append([]kafka.Header(nil), msg.Headers...),
+	}
+	headers := kafkaHeaders{msg: &out}
+
+	if headers.Get(HeaderOriginalTopic) == "" {
+		headers.Set(HeaderOriginalTopic, msg.Topic)
+		headers.Set(HeaderOriginalPartition, strconv.Itoa(msg.Partition))
+		headers.Set(HeaderOriginalOffset, strconv.FormatInt(msg.Offset, 10))
+	}
+
+	errText := cause.Error()
+	if len(errText) > maxErrorHeaderLength {
+		errText = errText[:maxErrorHeaderLength]
+	}
+	headers.Set(HeaderRetryAttempt, strconv.Itoa(attempt))
+	headers.Set(HeaderError, errText)
+	headers.Set(HeaderFailedAt, failedAt.UTC().Format(time.RFC3339Nano))
+	if !notBefore.IsZero() {
+		headers.Set(HeaderRetryNotBefore, notBefore.UTC().Format(time.RFC3339Nano))
+	}
+	return out
+}
+
+// retryDue returns when a message read from a retry topic may be handled.
+func retryDue(msg kafka.Message) time.Time {
+	due, err := time.Parse(time.RFC3339Nano, kafkaHeaders{msg: &msg}.Get(HeaderRetryNotBefore))
+	if err != nil {
+		return time.Time{}
+	}
+	return due
+}
+
+// waitFor sleeps for d unless ctx ends or stop is closed first.
+func waitFor(ctx context.Context, stop <-chan struct{}, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-stop:
+		return errConsumerStopped
+	}
+}
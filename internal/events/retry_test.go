@@ -0,0 +1,125 @@
+package events
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+type recordingWriter struct {
+	messages []kafka.Message
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *recordingWriter) Close() error { return nil }
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{stderrors.New("connection reset"), false},
+		{&permanentError{err: stderrors.New("invalid character")}, true},
+		{errors.ErrNotFound, true},
+		{errors.NewValidationError("status", "invalid transition"), true},
+	}
+
+	for _, tt := range tests {
+		if got := isPermanent(tt.err); got != tt.want {
+			t.Errorf("isPermanent(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestForwardRoutesFailures(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	writer := &recordingWriter{}
+	c := &KafkaConsumer{
+		writer:      writer,
+		retryTopics: []string{"payments.retry.1", "payments.retry.2"},
+		retryDelays: []time.Duration{10 * time.Second, time.Minute},
+		dlqTopic:    "payments.dlq",
+		logger:      logging.NewLoggerV2("test"),
+		stopCh:      make(chan struct{}),
+		now:         func() time.Time { return now },
+	}
+
+	msg := kafka.Message{
+		Topic:     "payments",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("ord_1"),
+		Value:     []byte(`{"id":"evt_1"}`),
+		Headers:   []kafka.Header{{Key: "traceparent", Value: []byte("00-abc")}},
+	}
+	transient := stderrors.New("database is unavailable")
+
+	// A transient failure on the payments topic goes to the first retry topic.
+	if err := c.forward(context.Background(), &consumerStage{}, msg, transient); err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+	retry := writer.messages[0]
+	headers := kafkaHeaders{msg: &retry}
+	if retry.Topic != "payments.retry.1" || string(retry.Key) != "ord_1" {
+		t.Fatalf("unexpected retry message %+v", retry)
+	}
+	if headers.Get(HeaderRetryAttempt) != "1" || headers.Get(HeaderOriginalOffset) != "42" || headers.Get("traceparent") != "00-abc" {
+		t.Errorf("unexpected retry headers %v", retry.Headers)
+	}
+	if due := retryDue(retry); !due.Equal(now.Add(10 * time.Second)) {
+		t.Errorf("expected the retry to be due in 10s, got %v", due)
+	}
+
+	// Failing the last retry dead-letters the message, keeping its origin.
+	retry.Topic, retry.Offset = "payments.retry.2", 7
+	if err := c.forward(context.Background(), &consumerStage{attempt: 2}, retry, transient); err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+	dead := writer.messages[1]
+	headers = kafkaHeaders{msg: &dead}
+	if dead.Topic != "payments.dlq" {
+		t.Fatalf("expected the DLQ, got %s", dead.Topic)
+	}
+	if headers.Get(HeaderOriginalTopic) != "payments" || headers.Get(HeaderOriginalOffset) != "42" {
+		t.Errorf("expected the original position to be kept, got %v", dead.Headers)
+	}
+	if headers.Get(HeaderRetryAttempt) != "3" || headers.Get(HeaderError) != "database is unavailable" {
+		t.Errorf("unexpected DLQ headers %v", dead.Headers)
+	}
+
+	// A permanent failure skips the retry topics.
+	if err := c.forward(context.Background(), &consumerStage{}, msg, errors.ErrNotFound); err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+	if topic := writer.messages[2].Topic; topic != "payments.dlq" {
+		t.Errorf("expected a permanent failure to be dead-lettered, got %s", topic)
+	}
+
+	replay := replayMessage(dead, "payments", now)
+	if replay.Topic != "payments" || retryAttempt(replay) != 0 {
+		t.Errorf("expected a fresh message on the payments topic, got %+v", replay)
+	}
+	if len(replay.Headers) != 2 || (kafkaHeaders{msg: &replay}).Get("traceparent") != "00-abc" {
+		t.Errorf("expected only the trace and replay headers, got %v", replay.Headers)
+	}
+}
+
+func TestHandleMessageRejectsInvalidEvents(t *testing.T) {
+	c := &KafkaConsumer{logger: logging.NewLoggerV2("test")}
+
+	for _, value := range []string{"not json", `{"type":"payment.completed","order_id":"ord_1"}`} {
+		err := c.handleMessage(context.Background(), kafka.Message{Topic: "payments", Value: []byte(value)})
+		if !isPermanent(err) {
+			t.Errorf("expected a permanent error for %s, got %v", value, err)
+		}
+	}
+}
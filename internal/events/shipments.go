@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// Ensure the publishers implement service.ShipmentEventPublisher
+var (
+	_ service.ShipmentEventPublisher = (*KafkaPublisher)(nil)
+	_ service.ShipmentEventPublisher = (*OutboxPublisher)(nil)
+	_ service.ShipmentEventPublisher = (*MockEventPublisher)(nil)
+)
+
+const (
+	EventTypeShipmentCreated       EventType = "order.shipment_created"
+	EventTypeShipmentStatusChanged EventType = "order.shipment_status_changed"
+)
+
+// NewShipmentCreatedEvent builds a shipment created event.
+func NewShipmentCreatedEvent(ctx context.Context, order *models.Order, shipment *repository.Shipment) (*OrderEvent, error) {
+	payload := struct {
+		Shipment    *repository.Shipment `json:"shipment"`
+		OrderStatus models.OrderStatus   `json:"order_status"`
+	}{
+		Shipment:    shipment,
+		OrderStatus: order.Status,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrderEvent(ctx, EventTypeShipmentCreated, order.ID, order.UserID, data), nil
+}
+
+// NewShipmentStatusChangedEvent builds a shipment status changed event.
+func NewShipmentStatusChangedEvent(ctx context.Context, order *models.Order, shipment *repository.Shipment, previousStatus repository.ShipmentStatus) (*OrderEvent, error) {
+	payload := struct {
+		Shipment       *repository.Shipment      `json:"shipment"`
+		PreviousStatus repository.ShipmentStatus `json:"previous_status"`
+		NewStatus      repository.ShipmentStatus `json:"new_status"`
+		OrderStatus    models.OrderStatus        `json:"order_status"`
+	}{
+		Shipment:       shipment,
+		PreviousStatus: previousStatus,
+		NewStatus:      shipment.Status,
+		OrderStatus:    order.Status,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrderEvent(ctx, EventTypeShipmentStatusChanged, order.ID, order.UserID, data), nil
+}
+
+// PublishShipmentCreated publishes a shipment created event.
+func (p *KafkaPublisher) PublishShipmentCreated(ctx context.Context, order *models.Order, shipment *repository.Shipment) error {
+	p.logger.Debug("Publishing shipment created event", logging.Fields{
+		"order_id":    order.ID,
+		"shipment_id": shipment.ID,
+	})
+
+	event, err := NewShipmentCreatedEvent(ctx, order, shipment)
+	if err != nil {
+		return err
+	}
+	return p.PublishEvent(ctx, event)
+}
+
+// PublishShipmentStatusChanged publishes a shipment status changed event.
+func (p *KafkaPublisher) PublishShipmentStatusChanged(ctx context.Context, order *models.Order, shipment *repository.Shipment, previousStatus repository.ShipmentStatus) error {
+	p.logger.Debug("Publishing shipment status changed event", logging.Fields{
+		"order_id":        order.ID,
+		"shipment_id":     shipment.ID,
+		"previous_status": previousStatus,
+		"new_status":      shipment.Status,
+	})
+
+	event, err := NewShipmentStatusChangedEvent(ctx, order, shipment, previousStatus)
+	if err != nil {
+		return err
+	}
+	return p.PublishEvent(ctx, event)
+}
+
+// PublishShipmentCreated enqueues a shipment created event.
+func (p *OutboxPublisher) PublishShipmentCreated(ctx context.Context, order *models.Order, shipment *repository.Shipment) error {
+	event, err := NewShipmentCreatedEvent(ctx, order, shipment)
+	if err != nil {
+		return err
+	}
+	return p.enqueue(ctx, event)
+}
+
+// PublishShipmentStatusChanged enqueues a shipment status changed event.
+func (p *OutboxPublisher) PublishShipmentStatusChanged(ctx context.Context, order *models.Order, shipment *repository.Shipment, previousStatus repository.ShipmentStatus) error {
+	event, err := NewShipmentStatusChangedEvent(ctx, order, shipment, previousStatus)
+	if err != nil {
+		return err
+	}
+	return p.enqueue(ctx, event)
+}
+
+func (m *MockEventPublisher) PublishShipmentCreated(ctx context.Context, order *models.Order, shipment *repository.Shipment) error {
+	m.Events = append(m.Events, &OrderEvent{
+		Type:    EventTypeShipmentCreated,
+		OrderID: order.ID,
+	})
+	return nil
+}
+
+func (m *MockEventPublisher) PublishShipmentStatusChanged(ctx context.Context, order *models.Order, shipment *repository.Shipment, previousStatus repository.ShipmentStatus) error {
+	m.Events = append(m.Events, &OrderEvent{
+		Type:    EventTypeShipmentStatusChanged,
+		OrderID: order.ID,
+	})
+	return nil
+}
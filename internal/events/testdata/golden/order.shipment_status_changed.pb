@@ -0,0 +1,4 @@
+2`
+A
+shp_1ord_12shipped:
+itm_1prd_1BȱJȱRȱpendingshipped"	confirmed
\ No newline at end of file
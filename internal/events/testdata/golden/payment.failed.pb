@@ -0,0 +1,2 @@
+
+pay_1ord_1failed"USD*card_declined
\ No newline at end of file
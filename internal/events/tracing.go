@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// kafkaHeaders adapts Kafka message headers to a propagation.TextMapCarrier
+// so trace context travels with the message.
+type kafkaHeaders struct {
+	msg *kafka.Message
+}
+
+var _ propagation.TextMapCarrier = kafkaHeaders{}
+
+func (h kafkaHeaders) Get(key string) string {
+	for _, header := range h.msg.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (h kafkaHeaders) Set(key, value string) {
+	for i, header := range h.msg.Headers {
+		if header.Key == key {
+			h.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	h.msg.Headers = append(h.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (h kafkaHeaders) Keys() []string {
+	keys := make([]string, len(h.msg.Headers))
+	for i, header := range h.msg.Headers {
+		keys[i] = header.Key
+	}
+	return keys
+}
+
+// injectTrace writes the trace context of ctx into carrier.
+func injectTrace(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+// extractTrace returns ctx carrying the trace context found in carrier.
+func extractTrace(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
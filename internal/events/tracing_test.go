@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestKafkaHeadersPropagateTrace(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "event_type", Value: []byte("order.created")}}}
+	injectTrace(ctx, kafkaHeaders{msg: &msg})
+
+	if got := (kafkaHeaders{msg: &msg}).Get("traceparent"); got != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Fatalf("unexpected traceparent header %q", got)
+	}
+
+	// Injecting again replaces the header rather than adding a second one.
+	injectTrace(ctx, kafkaHeaders{msg: &msg})
+	if len(msg.Headers) != 2 {
+		t.Errorf("expected 2 headers, got %d", len(msg.Headers))
+	}
+
+	extracted := trace.SpanContextFromContext(extractTrace(context.Background(), kafkaHeaders{msg: &msg}))
+	if extracted.TraceID() != parent.TraceID() || extracted.SpanID() != parent.SpanID() {
+		t.Errorf("expected the parent span context, got %v", extracted)
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-shared-go/middleware"
+)
+
+// Audit returns middleware that attributes order changes made by the request
+// to its caller, so they are recorded in the order status history.
+func (h *Handlers) Audit(source service.ChangeSource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := service.AuditInfo{
+			Source:    source,
+			Actor:     c.GetString("user_id"),
+			RequestID: c.GetString(middleware.RequestIDKey),
+		}
+		if info.Actor == "" {
+			info.Actor = c.GetHeader(middleware.HeaderUserID)
+		}
+		if info.RequestID == "" {
+			info.RequestID = c.GetHeader(middleware.HeaderRequestID)
+		}
+
+		c.Request = c.Request.WithContext(service.WithAudit(c.Request.Context(), info))
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-shared-go/middleware"
+)
+
+func TestAudit_AttachesCallerToContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &Handlers{}
+	router := gin.New()
+
+	var got service.AuditInfo
+	router.POST("/orders/:id/cancel", h.Audit(service.ChangeSourceAPI), func(c *gin.Context) {
+		got = service.AuditFromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/ord-1/cancel", nil)
+	req.Header.Set(middleware.HeaderUserID, "user-42")
+	req.Header.Set(middleware.HeaderRequestID, "req-7")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := service.AuditInfo{Actor: "user-42", Source: service.ChangeSourceAPI, RequestID: "req-7"}
+	if got != want {
+		t.Errorf("Expected audit info %+v, got %+v", want, got)
+	}
+}
+
+func TestAuditFromContext_DefaultsToSystem(t *testing.T) {
+	got := service.AuditFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	if got.Source != service.ChangeSourceSystem || got.Actor != "system" {
+		t.Errorf("Expected system attribution, got %+v", got)
+	}
+}
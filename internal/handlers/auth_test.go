@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/auth"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+func newAuthTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	authenticator, err := auth.NewAuthenticator(config.AuthConfig{
+		JWTSecrets: []string{"secret"},
+		APIKeys:    map[string]string{"warehouse": "wh-key"},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	h := &Handlers{authenticator: authenticator}
+
+	router := gin.New()
+	api := router.Group("/api/v2", h.Authenticate())
+	api.GET("/orders", h.ListOrders)
+	api.GET("/users/:user_id/orders", h.GetUserOrders)
+	api.POST("/orders/:id/refund", h.RequireRole(auth.RoleSupport, auth.RoleAdmin, auth.RoleService), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	v1 := router.Group("/api/v1", h.Authenticate())
+	v1.GET("/orders", h.ListOrdersV1)
+	v1.GET("/users/:user_id/orders", h.GetUserOrdersV1)
+	return router
+}
+
+func bearer(t *testing.T, subject string, roles ...string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   subject,
+		"roles": roles,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func TestAuthenticateAndRequireRole(t *testing.T) {
+	router := newAuthTestRouter(t)
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		wantCode int
+	}{
+		{name: "no credentials", wantCode: http.StatusUnauthorized},
+		{name: "invalid token", header: "Authorization", value: "Bearer nonsense", wantCode: http.StatusUnauthorized},
+		{name: "customer", header: "Authorization", value: bearer(t, "user_1"), wantCode: http.StatusForbidden},
+		{name: "support", header: "Authorization", value: bearer(t, "agent_1", "support"), wantCode: http.StatusNoContent},
+		{name: "service API key", header: auth.HeaderAPIKey, value: "wh-key", wantCode: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/orders/ord_1/refund", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestCustomersCannotListOtherUsersOrders(t *testing.T) {
+	router := newAuthTestRouter(t)
+
+	for _, path := range []string{
+		"/api/v2/orders?user_id=user_2",
+		"/api/v2/users/user_2/orders",
+		"/api/v1/orders?user_id=2",
+		"/api/v1/users/2/orders",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", bearer(t, "1", "customer"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected status 403, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestV1RequiresAuthentication(t *testing.T) {
+	router := newAuthTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/2/orders", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+)
+
+// orderETag formats an order version as a strong entity tag.
+func orderETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion returns the order version named by the If-Match header, or
+// repository.AnyVersion when the header is absent or "*". It responds with
+// 400 and returns false when the header is not an order ETag.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return repository.AnyVersion, true
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match must be a single order ETag"})
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version < repository.InitialVersion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match must be a single order ETag"})
+		return 0, false
+	}
+	return version, true
+}
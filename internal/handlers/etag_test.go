@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		header  string
+		version int64
+		ok      bool
+	}{
+		{"", repository.AnyVersion, true},
+		{"*", repository.AnyVersion, true},
+		{`"3"`, 3, true},
+		{`W/"3"`, 3, true},
+		{"3", 0, false},
+		{`"abc"`, 0, false},
+		{`"0"`, 0, false},
+		{`"1", "2"`, 0, false},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+		if tt.header != "" {
+			c.Request.Header.Set("If-Match", tt.header)
+		}
+
+		version, ok := ifMatchVersion(c)
+		if ok != tt.ok || version != tt.version {
+			t.Errorf("If-Match %q: expected (%d, %v), got (%d, %v)", tt.header, tt.version, tt.ok, version, ok)
+		}
+		if !ok && w.Code != http.StatusBadRequest {
+			t.Errorf("If-Match %q: expected status 400, got %d", tt.header, w.Code)
+		}
+	}
+}
+
+func TestHandleError_Concurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	handleError(c, repository.ErrVersionConflict)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	handleError(c, &service.PreconditionFailedError{Expected: 2, Current: 4})
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status 412, got %d", w.Code)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"4"` {
+		t.Errorf(`Expected ETag "4", got %s`, etag)
+	}
+}
@@ -1,29 +1,49 @@
 package handlers
 
 import (
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/auth"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/health"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/ratelimit"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 )
 
 // Handlers holds all HTTP handlers for the orders service.
 type Handlers struct {
-	orderService   *service.OrderService
-	paymentService *service.PaymentService
-	config         *config.Config
-	logger         *logging.LoggerV2
+	orderService     *service.OrderService
+	paymentService   *service.PaymentService
+	returnService    *service.ReturnService
+	idempotencyStore repository.IdempotencyStore
+	readiness        *health.Registry
+	authenticator    *auth.Authenticator
+	limiter          ratelimit.Limiter
+	config           *config.Config
+	logger           *logging.LoggerV2
 }
 
-// NewHandlers creates a new handlers instance.
+// NewHandlers creates a new handlers instance. A nil authenticator disables
+// authentication and a nil limiter disables rate limiting.
 func NewHandlers(
 	orderService *service.OrderService,
 	paymentService *service.PaymentService,
+	returnService *service.ReturnService,
+	idempotencyStore repository.IdempotencyStore,
+	readiness *health.Registry,
+	authenticator *auth.Authenticator,
+	limiter ratelimit.Limiter,
 	cfg *config.Config,
 ) *Handlers {
 	return &Handlers{
-		orderService:   orderService,
-		paymentService: paymentService,
-		config:         cfg,
-		logger:         logging.NewLoggerV2("handlers"),
+		orderService:     orderService,
+		paymentService:   paymentService,
+		returnService:    returnService,
+		idempotencyStore: idempotencyStore,
+		readiness:        readiness,
+		authenticator:    authenticator,
+		limiter:          limiter,
+		config:           cfg,
+		logger:           logging.NewLoggerV2("handlers"),
 	}
 }
@@ -2,12 +2,18 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/health"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 	"github.com/tm-acme-shop/acme-shop-shared-go/models"
 )
 
@@ -42,19 +48,92 @@ func TestHealth(t *testing.T) {
 func TestReady(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	up := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name       string
+		checkers   []health.Checker
+		wantCode   int
+		wantStatus health.Readiness
+	}{
+		{
+			name:       "ready",
+			checkers:   []health.Checker{{Name: "postgres", Critical: true, Check: up}},
+			wantCode:   http.StatusOK,
+			wantStatus: health.Ready,
+		},
+		{
+			name: "degraded",
+			checkers: []health.Checker{
+				{Name: "postgres", Critical: true, Check: up},
+				{Name: "redis", Check: down},
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: health.Degraded,
+		},
+		{
+			name:       "not ready",
+			checkers:   []health.Checker{{Name: "postgres", Critical: true, Check: down}},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: health.NotReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readiness := health.NewRegistry(time.Second)
+			for _, checker := range tt.checkers {
+				readiness.Register(checker)
+			}
+			h := &Handlers{readiness: readiness, logger: logging.NewLoggerV2("test")}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/ready", nil)
+
+			h.Ready(c)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+
+			var resp struct {
+				Status     health.Readiness         `json:"status"`
+				Components []health.ComponentStatus `json:"components"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Expected readiness %s, got %s", tt.wantStatus, resp.Status)
+			}
+			if len(resp.Components) != len(tt.checkers) {
+				t.Errorf("Expected %d components, got %d", len(tt.checkers), len(resp.Components))
+			}
+			if strings.Contains(w.Body.String(), "connection refused") {
+				t.Errorf("Expected dependency errors to be hidden, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
 	h := &Handlers{}
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
-	h.Ready(c)
+	h.Live(c)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 }
 
-func TestLive(t *testing.T) {
+func TestMetricsLegacy(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	h := &Handlers{}
@@ -62,11 +141,22 @@ func TestLive(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
-	h.Live(c)
+	h.MetricsLegacy(c)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
+	if w.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected a Deprecation header, got %q", w.Header().Get("Deprecation"))
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if _, ok := resp["goroutines"]; !ok {
+		t.Errorf("Expected the legacy runtime stats, got %v", resp)
+	}
 }
 
 func TestHandleError_NotFound(t *testing.T) {
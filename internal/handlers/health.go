@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/health"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 )
 
 var startTime = time.Now()
@@ -19,11 +21,29 @@ func (h *Handlers) Health(c *gin.Context) {
 }
 
 // Ready handles GET /ready
+// Responds 503 only when a critical dependency is down; failures of other
+// dependencies are reported as "degraded" with 200 so traffic keeps flowing.
 func (h *Handlers) Ready(c *gin.Context) {
-	// TODO(TEAM-PLATFORM): Add actual readiness checks (DB, Redis, Kafka)
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ready",
-		"service": "orders-service",
+	report := h.readiness.Run(c.Request.Context())
+	for _, component := range report.Components {
+		if component.Status == health.StatusDown {
+			h.logger.Error("Readiness check failed", logging.Fields{
+				"component": component.Name,
+				"critical":  component.Critical,
+				"error":     component.Cause.Error(),
+			})
+		}
+	}
+
+	status := http.StatusOK
+	if report.Status == health.NotReady {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, gin.H{
+		"status":     report.Status,
+		"service":    "orders-service",
+		"components": report.Components,
 	})
 }
 
@@ -34,20 +54,24 @@ func (h *Handlers) Live(c *gin.Context) {
 	})
 }
 
-// Metrics handles GET /metrics (Prometheus format)
-func (h *Handlers) Metrics(c *gin.Context) {
-	// TODO(TEAM-PLATFORM): Use prometheus client library
+// MetricsLegacy handles GET /metrics/legacy with the JSON runtime stats that
+// /metrics served before it switched to the Prometheus format.
+//
+// Deprecated: scrape /metrics instead.
+func (h *Handlers) MetricsLegacy(c *gin.Context) {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 
+	c.Header("Deprecation", "true")
+	c.Header("Link", `</metrics>; rel="successor-version"`)
 	c.JSON(http.StatusOK, gin.H{
-		"uptime_seconds":    time.Since(startTime).Seconds(),
-		"goroutines":        runtime.NumGoroutine(),
-		"heap_alloc_bytes":  m.HeapAlloc,
-		"heap_sys_bytes":    m.HeapSys,
-		"heap_objects":      m.HeapObjects,
-		"gc_runs":           m.NumGC,
-		"go_version":        runtime.Version(),
+		"uptime_seconds":   time.Since(startTime).Seconds(),
+		"goroutines":       runtime.NumGoroutine(),
+		"heap_alloc_bytes": m.HeapAlloc,
+		"heap_sys_bytes":   m.HeapSys,
+		"heap_objects":     m.HeapObjects,
+		"gc_runs":          m.NumGC,
+		"go_version":       runtime.Version(),
 	})
 }
 
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+const (
+	// HeaderIdempotencyKey is the request header clients use to make POSTs safe to retry.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses replayed from a stored key.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency returns middleware that honors the Idempotency-Key header.
+// The first request for a key stores a fingerprint of the request and the
+// response it produced; retries with the same body replay that response, and
+// retries with a different body are rejected with 422.
+func (h *Handlers) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" || h.idempotencyStore == nil {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency key too long (max 255 characters)"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := idempotencyScope(c)
+		requestHash := fingerprintRequest(c.Request.Method, c.Request.URL.Path, body)
+
+		record, acquired, err := h.idempotencyStore.Acquire(
+			c.Request.Context(), key, scope, requestHash, h.config.Idempotency.TTL,
+		)
+		if err != nil {
+			h.logger.Error("Idempotency key lookup failed", logging.Fields{
+				"scope": scope,
+				"error": err.Error(),
+			})
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		if !acquired {
+			switch {
+			case record.RequestHash != requestHash:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+					"error": "idempotency key has already been used with a different request",
+				})
+			case !record.Completed:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error": "a request with this idempotency key is still being processed",
+				})
+			default:
+				h.logger.Debug("Replaying idempotent response", logging.Fields{"scope": scope})
+				c.Header(HeaderIdempotentReplayed, "true")
+				c.Data(record.StatusCode, "application/json; charset=utf-8", record.ResponseBody)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Store the outcome even if the client has gone away.
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		// Recovery sits outside this middleware, so a panicking handler
+		// would otherwise leave the key in progress until it expires.
+		defer func() {
+			if r := recover(); r != nil {
+				h.releaseIdempotencyKey(ctx, key, scope)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+
+		// Server errors are not stored so the client can retry them.
+		if status >= http.StatusInternalServerError {
+			h.releaseIdempotencyKey(ctx, key, scope)
+			return
+		}
+
+		if err := h.idempotencyStore.Complete(ctx, key, scope, status, recorder.body.Bytes()); err != nil {
+			h.logger.Error("Failed to complete idempotency key", logging.Fields{
+				"scope": scope,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// releaseIdempotencyKey frees a key so the request can be retried.
+func (h *Handlers) releaseIdempotencyKey(ctx context.Context, key, scope string) {
+	if err := h.idempotencyStore.Release(ctx, key, scope); err != nil {
+		h.logger.Error("Failed to release idempotency key", logging.Fields{
+			"scope": scope,
+			"error": err.Error(),
+		})
+	}
+}
+
+// idempotencyScope namespaces keys by route and caller so two users cannot
+// collide on the same key.
+func idempotencyScope(c *gin.Context) string {
+	scope := c.Request.Method + " " + c.FullPath()
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(string); ok && id != "" {
+			scope += " " + id
+		}
+	}
+	return scope
+}
+
+func fingerprintRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{'\n'})
+	hash.Write([]byte(path))
+	hash.Write([]byte{'\n'})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder captures the response body while still writing it through.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
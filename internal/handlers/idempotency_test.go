@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+type memoryIdempotencyStore struct {
+	records map[string]*repository.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*repository.IdempotencyRecord)}
+}
+
+func (m *memoryIdempotencyStore) Acquire(ctx context.Context, key, scope, requestHash string, ttl time.Duration) (*repository.IdempotencyRecord, bool, error) {
+	if record, ok := m.records[scope+"|"+key]; ok {
+		return record, false, nil
+	}
+	record := &repository.IdempotencyRecord{Key: key, Scope: scope, RequestHash: requestHash}
+	m.records[scope+"|"+key] = record
+	return record, true, nil
+}
+
+func (m *memoryIdempotencyStore) Complete(ctx context.Context, key, scope string, statusCode int, body []byte) error {
+	record := m.records[scope+"|"+key]
+	record.StatusCode = statusCode
+	record.ResponseBody = body
+	record.Completed = true
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Release(ctx context.Context, key, scope string) error {
+	delete(m.records, scope+"|"+key)
+	return nil
+}
+
+func (m *memoryIdempotencyStore) DeleteExpiredBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotencyTestRouter(calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	h := &Handlers{
+		idempotencyStore: newMemoryIdempotencyStore(),
+		config:           &config.Config{Idempotency: config.IdempotencyConfig{TTL: time.Hour}},
+		logger:           logging.NewLoggerV2("test"),
+	}
+
+	router := gin.New()
+	router.POST("/orders", h.Idempotency(), func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusCreated, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func doIdempotentRequest(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set(HeaderIdempotencyKey, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(&calls)
+
+	first := doIdempotentRequest(router, "key-1", `{"user_id":"user_123"}`)
+	second := doIdempotentRequest(router, "key-1", `{"user_id":"user_123"}`)
+
+	if calls != 1 {
+		t.Fatalf("Expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated {
+		t.Errorf("Expected replayed status 201, got %d", second.Code)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("Expected replayed body %s, got %s", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get(HeaderIdempotentReplayed) != "true" {
+		t.Error("Expected replayed response to be marked")
+	}
+}
+
+func TestIdempotency_RejectsDifferentBody(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(&calls)
+
+	doIdempotentRequest(router, "key-1", `{"user_id":"user_123"}`)
+	w := doIdempotentRequest(router, "key-1", `{"user_id":"user_456"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(&calls)
+
+	doIdempotentRequest(router, "", `{}`)
+	doIdempotentRequest(router, "", `{}`)
+
+	if calls != 2 {
+		t.Errorf("Expected handler to run twice without a key, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_ReleasesKeyOnPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newMemoryIdempotencyStore()
+	h := &Handlers{
+		idempotencyStore: store,
+		config:           &config.Config{Idempotency: config.IdempotencyConfig{TTL: time.Hour}},
+		logger:           logging.NewLoggerV2("test"),
+	}
+
+	calls := 0
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.POST("/orders", h.Idempotency(), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := doIdempotentRequest(router, "key-1", `{}`)
+	if first.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500 from the panicking handler, got %d", first.Code)
+	}
+	if len(store.records) != 0 {
+		t.Fatalf("Expected the key to be released after a panic, %d records left", len(store.records))
+	}
+
+	second := doIdempotentRequest(router, "key-1", `{}`)
+	if second.Code != http.StatusCreated {
+		t.Errorf("Expected retry to run the handler and return 201, got %d", second.Code)
+	}
+}
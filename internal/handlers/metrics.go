@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "orders_http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "orders_http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	})
+)
+
+// Instrument returns middleware that records request durations. Requests are
+// labelled with the route template rather than the raw path so order IDs do
+// not end up in label values.
+func (h *Handlers) Instrument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestDuration.WithLabelValues(
+			c.Request.Method,
+			route,
+			strconv.Itoa(c.Writer.Status()),
+		).Observe(time.Since(start).Seconds())
+	}
+}
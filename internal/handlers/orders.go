@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	stderrors "errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/clients"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
 	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
@@ -23,6 +27,15 @@ func (h *Handlers) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	// Customers may only order for themselves
+	if p, ok := principal(c); ok && !p.CanAccessAllOrders() {
+		if req.UserID != "" && req.UserID != p.Subject {
+			c.JSON(http.StatusForbidden, gin.H{"error": "customers may only create orders for themselves"})
+			return
+		}
+		req.UserID = p.Subject
+	}
+
 	// Get user ID from context if not provided
 	if req.UserID == "" {
 		if userID, exists := c.Get("user_id"); exists {
@@ -36,7 +49,8 @@ func (h *Handlers) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, order)
+	c.Header("ETag", orderETag(repository.InitialVersion))
+	c.JSON(http.StatusCreated, repository.VersionedOrder{Order: order, Version: repository.InitialVersion})
 }
 
 // API-105: Initial orders v1 API (2022-04)
@@ -52,19 +66,26 @@ func (h *Handlers) CreateOrderV1(c *gin.Context) {
 		return
 	}
 
+	userID := strconv.FormatInt(req.UserID, 10)
+	if !canAccessUser(c, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "customers may only create orders for themselves"})
+		return
+	}
+
 	// TODO(TEAM-API): Migrate to v2 API
 	// For now, convert to v2 format internally
 	v2Req := &models.CreateOrderRequest{
-		UserID: strconv.FormatInt(req.UserID, 10),
+		UserID: userID,
 		Items:  make([]models.OrderItem, len(req.Items)),
 	}
 
 	for i, item := range req.Items {
+		unitPrice := service.MoneyFromMajor(item.Price, req.Currency)
 		v2Req.Items[i] = models.OrderItem{
 			ProductID: strconv.FormatInt(item.ProductID, 10),
 			Quantity:  item.Quantity,
-			UnitPrice: models.NewMoney(item.Price, req.Currency),
-			Total:     models.NewMoney(item.Price*float64(item.Quantity), req.Currency),
+			UnitPrice: unitPrice,
+			Total:     models.Money{Amount: unitPrice.Amount * int64(item.Quantity), Currency: req.Currency},
 		}
 	}
 
@@ -79,7 +100,7 @@ func (h *Handlers) CreateOrderV1(c *gin.Context) {
 		order.ID,
 		order.UserID,
 		string(order.Status),
-		order.Total.ToFloat(),
+		service.ToMajor(order.Total),
 		order.Total.Currency,
 	)
 
@@ -90,15 +111,70 @@ func (h *Handlers) CreateOrderV1(c *gin.Context) {
 func (h *Handlers) GetOrder(c *gin.Context) {
 	orderID := c.Param("id")
 
-	order, err := h.orderService.GetOrder(c.Request.Context(), orderID)
+	order, err := h.orderService.GetOrderVersioned(c.Request.Context(), orderID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
+	// Report other customers' orders as missing rather than forbidden
+	if !canAccessUser(c, order.UserID) {
+		handleError(c, errors.ErrNotFound)
+		return
+	}
+
+	c.Header("ETag", orderETag(order.Version))
 	c.JSON(http.StatusOK, order)
 }
 
+// GetOrderTaxLines handles GET /api/v2/orders/:id/tax
+func (h *Handlers) GetOrderTaxLines(c *gin.Context) {
+	orderID := c.Param("id")
+
+	lines, err := h.orderService.GetOrderTaxLines(c.Request.Context(), orderID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": orderID,
+		"lines":    lines,
+	})
+}
+
+// GetOrderHistory handles GET /api/v2/orders/:id/history
+func (h *Handlers) GetOrderHistory(c *gin.Context) {
+	orderID := c.Param("id")
+
+	history, err := h.orderService.GetOrderHistory(c.Request.Context(), orderID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": orderID,
+		"history":  history,
+	})
+}
+
+// GetOrderLifecycle handles GET /api/v2/orders/lifecycle
+// It renders the order state machine as Mermaid (default) or, with
+// ?format=dot, as a Graphviz digraph.
+func (h *Handlers) GetOrderLifecycle(c *gin.Context) {
+	lifecycle := h.orderService.Lifecycle()
+
+	switch c.DefaultQuery("format", "mermaid") {
+	case "mermaid":
+		c.String(http.StatusOK, lifecycle.Mermaid())
+	case "dot":
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(lifecycle.DOT()))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be mermaid or dot"})
+	}
+}
+
 // GetOrderV1 handles GET /api/v1/orders/:id
 // Deprecated: Use GetOrder (v2) instead.
 // TODO(TEAM-API): Remove after v1 API migration complete
@@ -113,6 +189,9 @@ func (h *Handlers) GetOrderV1(c *gin.Context) {
 	}
 
 	order, err := h.orderService.GetOrderV1(c.Request.Context(), orderID)
+	if err == nil && !canAccessUser(c, strconv.FormatInt(order.UserID, 10)) {
+		err = errors.ErrNotFound
+	}
 	if err != nil {
 		handleError(c, err)
 		return
@@ -136,12 +215,18 @@ func (h *Handlers) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), orderID, &req)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), orderID, version, &req)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
+	c.Header("ETag", orderETag(order.Version))
 	c.JSON(http.StatusOK, order)
 }
 
@@ -174,6 +259,7 @@ func (h *Handlers) UpdateOrderStatusV1(c *gin.Context) {
 	order, err := h.orderService.UpdateOrderStatus(
 		c.Request.Context(),
 		strconv.FormatInt(orderID, 10),
+		repository.AnyVersion,
 		v2Req,
 	)
 	if err != nil {
@@ -205,60 +291,131 @@ func (h *Handlers) CancelOrder(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.CancelOrder(c.Request.Context(), orderID, req.Reason)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.CancelOrder(c.Request.Context(), orderID, version, req.Reason)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
+	c.Header("ETag", orderETag(order.Version))
 	c.JSON(http.StatusOK, order)
 }
 
 // ListOrders handles GET /api/v2/orders
+// Results are paginated by cursor: pass the returned next_cursor as ?cursor=
+// to fetch the following page, keeping the other parameters unchanged.
 func (h *Handlers) ListOrders(c *gin.Context) {
-	filter := &models.OrderListFilter{}
-
-	// Parse query parameters
-	if userID := c.Query("user_id"); userID != "" {
-		filter.UserID = userID
-	}
-
-	if status := c.Query("status"); status != "" {
-		s := models.OrderStatus(status)
-		filter.Status = &s
-	}
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			filter.Limit = limit
-		}
+	q, err := parseOrderQuery(c)
+	if err != nil {
+		handleError(c, err)
+		return
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			filter.Offset = offset
+	// Customers only ever see their own orders
+	if p, ok := principal(c); ok && !p.CanAccessAllOrders() {
+		if q.UserID != "" && q.UserID != p.Subject {
+			c.JSON(http.StatusForbidden, gin.H{"error": "customers may only list their own orders"})
+			return
 		}
+		q.UserID = p.Subject
 	}
 
-	if err := service.ValidateOrderListFilter(filter); err != nil {
+	if err := service.ValidateOrderQuery(q); err != nil {
 		handleError(c, err)
 		return
 	}
 
-	orders, total, err := h.orderService.ListOrders(c.Request.Context(), filter)
+	page, err := h.orderService.ListOrders(c.Request.Context(), q)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
+	var nextCursor interface{}
+	if page.NextCursor != "" {
+		nextCursor = page.NextCursor
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"orders": orders,
-		"total":  total,
-		"limit":  filter.Limit,
-		"offset": filter.Offset,
+		"orders":      page.Orders,
+		"total":       page.Total,
+		"limit":       q.Limit,
+		"offset":      q.Offset,
+		"next_cursor": nextCursor,
 	})
 }
 
+func parseOrderQuery(c *gin.Context) (*repository.OrderQuery, error) {
+	q := &repository.OrderQuery{
+		UserID:    c.Query("user_id"),
+		Currency:  c.Query("currency"),
+		PaymentID: c.Query("payment_id"),
+		Country:   c.Query("country"),
+		ProductID: c.Query("product_id"),
+		Sort:      repository.OrderSort(c.Query("sort")),
+		Cursor:    c.Query("cursor"),
+	}
+
+	// status may be repeated or comma-separated
+	for _, value := range c.QueryArray("status") {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				q.Statuses = append(q.Statuses, models.OrderStatus(status))
+			}
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, errors.NewValidationError("limit", "limit must be an integer")
+		}
+		q.Limit = limit
+	}
+
+	// Deprecated: offset paging is kept for existing clients; use cursor.
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			return nil, errors.NewValidationError("offset", "offset must be an integer")
+		}
+		q.Offset = offset
+	}
+
+	for field, dst := range map[string]**time.Time{
+		"created_from": &q.CreatedFrom,
+		"created_to":   &q.CreatedTo,
+	} {
+		if value := c.Query(field); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, errors.NewValidationError(field, field+" must be an RFC 3339 timestamp")
+			}
+			*dst = &t
+		}
+	}
+
+	for field, dst := range map[string]**int64{
+		"min_total": &q.MinTotal,
+		"max_total": &q.MaxTotal,
+	} {
+		if value := c.Query(field); value != "" {
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, errors.NewValidationError(field, field+" must be an amount in minor units")
+			}
+			*dst = &amount
+		}
+	}
+
+	return q, nil
+}
+
 // ListOrdersV1 handles GET /api/v1/orders
 // Deprecated: Use ListOrders (v2) instead.
 // TODO(TEAM-API): Remove after v1 API migration complete
@@ -283,6 +440,11 @@ func (h *Handlers) ListOrdersV1(c *gin.Context) {
 		return
 	}
 
+	if !canAccessUser(c, userIDStr) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "customers may only list their own orders"})
+		return
+	}
+
 	orders, err := h.orderService.GetUserOrdersV1(c.Request.Context(), userID)
 	if err != nil {
 		handleError(c, err)
@@ -299,6 +461,11 @@ func (h *Handlers) ListOrdersV1(c *gin.Context) {
 func (h *Handlers) GetUserOrders(c *gin.Context) {
 	userID := c.Param("user_id")
 
+	if !canAccessUser(c, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "customers may only list their own orders"})
+		return
+	}
+
 	limit := 20
 	offset := 0
 
@@ -341,6 +508,11 @@ func (h *Handlers) GetUserOrdersV1(c *gin.Context) {
 		return
 	}
 
+	if !canAccessUser(c, userIDStr) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "customers may only list their own orders"})
+		return
+	}
+
 	orders, err := h.orderService.GetUserOrdersV1(c.Request.Context(), userID)
 	if err != nil {
 		handleError(c, err)
@@ -354,26 +526,50 @@ func (h *Handlers) GetUserOrdersV1(c *gin.Context) {
 func (h *Handlers) RefundOrder(c *gin.Context) {
 	orderID := c.Param("id")
 
-	var req struct {
-		Reason string `json:"reason"`
-	}
+	var req service.RefundOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
 
-	if req.Reason == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+	if err := service.ValidateRefundOrderRequest(&req); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	refundResp, err := h.orderService.RefundOrder(c.Request.Context(), orderID, req.Reason)
+	refund, err := h.orderService.RefundOrder(c.Request.Context(), orderID, version, &req)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, refundResp)
+	// A pending refund is settled later from the payment service's events.
+	status := http.StatusOK
+	if refund.Status == models.PaymentStatusPending {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, refund)
+}
+
+// ListRefunds handles GET /api/v2/orders/:id/refunds
+func (h *Handlers) ListRefunds(c *gin.Context) {
+	orderID := c.Param("id")
+
+	refunds, err := h.orderService.ListRefunds(c.Request.Context(), orderID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": orderID,
+		"refunds":  refunds,
+	})
 }
 
 func handleError(c *gin.Context, err error) {
@@ -390,5 +586,44 @@ func handleError(c *gin.Context, err error) {
 		return
 	}
 
+	var mismatchErr *service.PriceMismatchError
+	if stderrors.As(err, &mismatchErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":      "order totals do not match server pricing",
+			"mismatches": mismatchErr.Mismatches,
+			"tolerance":  mismatchErr.Tolerance,
+		})
+		return
+	}
+
+	if stderrors.Is(err, repository.ErrVersionConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "order was modified concurrently, retry the request"})
+		return
+	}
+
+	var preconditionErr *service.PreconditionFailedError
+	if stderrors.As(err, &preconditionErr) {
+		c.Header("ETag", orderETag(preconditionErr.Current))
+		c.JSON(http.StatusPreconditionFailed, gin.H{
+			"error":           "order has changed since it was read",
+			"current_version": preconditionErr.Current,
+		})
+		return
+	}
+
+	var signatureErr *clients.WebhookSignatureError
+	if stderrors.As(err, &signatureErr) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":  "invalid webhook signature",
+			"reason": signatureErr.Reason,
+		})
+		return
+	}
+
+	if clients.IsUnavailable(err) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "a dependent service is unavailable, retry later"})
+		return
+	}
+
 	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 }
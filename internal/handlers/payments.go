@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/clients"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 	"github.com/tm-acme-shop/acme-shop-shared-go/models"
@@ -33,7 +35,12 @@ func (h *Handlers) ProcessOrderPayment(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.orderService.ProcessOrderPayment(c.Request.Context(), orderID, &req)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.orderService.ProcessOrderPayment(c.Request.Context(), orderID, version, &req)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -67,12 +74,12 @@ func (h *Handlers) ProcessOrderPaymentV1(c *gin.Context) {
 	// Convert to v2 and process
 	v2Req := &models.ProcessPaymentRequest{
 		OrderID:   orderID,
-		Amount:    models.NewMoney(req.Amount, req.Currency),
+		Amount:    service.MoneyFromMajor(req.Amount, req.Currency),
 		Method:    models.PaymentMethodCreditCard,
 		CardToken: "legacy_token", // Legacy format doesn't use tokens
 	}
 
-	resp, err := h.orderService.ProcessOrderPayment(c.Request.Context(), orderID, v2Req)
+	resp, err := h.orderService.ProcessOrderPayment(c.Request.Context(), orderID, repository.AnyVersion, v2Req)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -201,7 +208,7 @@ func (h *Handlers) ProcessRefundV1(c *gin.Context) {
 		return
 	}
 
-	amount := models.NewMoney(req.Amount, req.Currency)
+	amount := service.MoneyFromMajor(req.Amount, req.Currency)
 
 	refundResp, err := h.paymentService.ProcessRefund(
 		c.Request.Context(),
@@ -226,7 +233,7 @@ func (h *Handlers) ProcessRefundV1(c *gin.Context) {
 
 // PaymentWebhook handles POST /api/v2/webhooks/payment
 func (h *Handlers) PaymentWebhook(c *gin.Context) {
-	signature := c.GetHeader("X-Payment-Signature")
+	signature := c.GetHeader(clients.HeaderPaymentSignature)
 
 	payload, err := io.ReadAll(c.Request.Body)
 	if err != nil {
@@ -235,9 +242,11 @@ func (h *Handlers) PaymentWebhook(c *gin.Context) {
 		return
 	}
 
+	// Validation errors are final; anything else returns 5xx so the
+	// payment service retries the delivery.
 	if err := h.paymentService.ProcessWebhook(c.Request.Context(), payload, signature); err != nil {
 		h.logger.Error("Webhook processing failed", logging.Fields{"error": err.Error()})
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -250,9 +259,9 @@ func (h *Handlers) PaymentWebhook(c *gin.Context) {
 func (h *Handlers) PaymentWebhookV1(c *gin.Context) {
 	logging.Infof("Legacy: Processing payment webhook via v1 API")
 
-	// Legacy uses different signature header
-	// TODO(TEAM-API): Remove legacy header after migration
-	signature := c.GetHeader("X-Legacy-Signature")
+	// X-Legacy-Signature is no longer trusted; v1 deliveries must be signed
+	// the same way as v2.
+	signature := c.GetHeader(clients.HeaderPaymentSignature)
 
 	payload, err := io.ReadAll(c.Request.Body)
 	if err != nil {
@@ -261,7 +270,7 @@ func (h *Handlers) PaymentWebhookV1(c *gin.Context) {
 	}
 
 	if err := h.paymentService.ProcessWebhook(c.Request.Context(), payload, signature); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleError(c, err)
 		return
 	}
 
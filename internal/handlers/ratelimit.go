@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/ratelimit"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimit returns middleware that charges every request to the caller's
+// bucket under policy and rejects it with 429 once the bucket is empty.
+// Authenticated callers are limited by subject (user or service), others by
+// client IP, so it must run after Authenticate.
+func (h *Handlers) RateLimit(policy ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.rateLimit(c, policy)
+	}
+}
+
+// RateLimitReadWrite is RateLimit with separate policies for safe requests
+// and for requests that change state.
+func (h *Handlers) RateLimitReadWrite(read, write ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			h.rateLimit(c, read)
+		default:
+			h.rateLimit(c, write)
+		}
+	}
+}
+
+func (h *Handlers) rateLimit(c *gin.Context, policy ratelimit.Policy) {
+	if h.limiter == nil {
+		c.Next()
+		return
+	}
+
+	key := policy.Name + ":" + rateLimitKey(c)
+	result, err := h.limiter.Allow(c.Request.Context(), key, policy)
+	ratelimit.RecordDecision(policy.Name, result, err)
+	if err != nil {
+		// An unavailable limiter must not take the API down with it.
+		h.logger.Error("Rate limit check failed", logging.Fields{
+			"policy": policy.Name,
+			"error":  err.Error(),
+		})
+		c.Next()
+		return
+	}
+
+	c.Header(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+	c.Header(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	c.Header(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded, retry later"})
+		return
+	}
+	c.Next()
+}
+
+// rateLimitKey identifies the caller a request is charged to.
+func rateLimitKey(c *gin.Context) string {
+	if p, ok := principal(c); ok {
+		return "sub:" + p.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &Handlers{limiter: ratelimit.NewMemoryLimiter()}
+	router := gin.New()
+	router.Use(h.RateLimitReadWrite(
+		ratelimit.Policy{Name: "read", Rate: 1, Burst: 2},
+		ratelimit.Policy{Name: "write", Rate: 1, Burst: 1},
+	))
+	router.Any("/orders", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/orders", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodGet, "10.0.0.1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get(HeaderRateLimitLimit) != "2" || w.Header().Get(HeaderRateLimitRemaining) != "1" {
+		t.Errorf("unexpected rate limit headers %v", w.Header())
+	}
+
+	if w := do(http.MethodPost, "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("expected writes to have their own quota, got %d", w.Code)
+	}
+	w = do(http.MethodPost, "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected Retry-After 1, got %q", w.Header().Get("Retry-After"))
+	}
+
+	if w := do(http.MethodPost, "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("expected another client to have its own quota, got %d", w.Code)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// CreateReturn handles POST /api/v2/orders/:id/returns
+func (h *Handlers) CreateReturn(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var req service.CreateReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind return request", logging.Fields{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := service.ValidateCreateReturnRequest(&req); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	ret, err := h.returnService.RequestReturn(c.Request.Context(), orderID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ret)
+}
+
+// ListReturns handles GET /api/v2/orders/:id/returns
+func (h *Handlers) ListReturns(c *gin.Context) {
+	orderID := c.Param("id")
+
+	returns, err := h.returnService.ListReturns(c.Request.Context(), orderID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": orderID,
+		"returns":  returns,
+	})
+}
+
+// GetReturn handles GET /api/v2/orders/:id/returns/:return_id
+func (h *Handlers) GetReturn(c *gin.Context) {
+	ret, err := h.returnService.GetReturn(c.Request.Context(), c.Param("id"), c.Param("return_id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// ApproveReturn handles POST /api/v2/orders/:id/returns/:return_id/approve
+func (h *Handlers) ApproveReturn(c *gin.Context) {
+	ret, err := h.returnService.ApproveReturn(c.Request.Context(), c.Param("id"), c.Param("return_id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// ReceiveReturn handles POST /api/v2/orders/:id/returns/:return_id/receive
+func (h *Handlers) ReceiveReturn(c *gin.Context) {
+	req, ok := bindReturnItems(c)
+	if !ok {
+		return
+	}
+
+	ret, err := h.returnService.ReceiveReturn(c.Request.Context(), c.Param("id"), c.Param("return_id"), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// InspectReturn handles POST /api/v2/orders/:id/returns/:return_id/inspect
+func (h *Handlers) InspectReturn(c *gin.Context) {
+	req, ok := bindReturnItems(c)
+	if !ok {
+		return
+	}
+
+	ret, err := h.returnService.InspectReturn(c.Request.Context(), c.Param("id"), c.Param("return_id"), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// RefundReturn handles POST /api/v2/orders/:id/returns/:return_id/refund
+func (h *Handlers) RefundReturn(c *gin.Context) {
+	ret, err := h.returnService.RefundReturn(c.Request.Context(), c.Param("id"), c.Param("return_id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// RejectReturn handles POST /api/v2/orders/:id/returns/:return_id/reject
+func (h *Handlers) RejectReturn(c *gin.Context) {
+	var req service.RejectReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := service.ValidateRejectReturnRequest(&req); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	ret, err := h.returnService.RejectReturn(c.Request.Context(), c.Param("id"), c.Param("return_id"), &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// bindReturnItems reads the optional body of the receive and inspect steps;
+// an empty body carries every unit over from the previous step.
+func bindReturnItems(c *gin.Context) (*service.ReturnItemsRequest, bool) {
+	var req service.ReturnItemsRequest
+	if c.Request.ContentLength == 0 {
+		return &req, true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return nil, false
+	}
+	return &req, true
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// CreateShipment handles POST /api/v2/orders/:id/shipments
+func (h *Handlers) CreateShipment(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var req service.CreateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind shipment request", logging.Fields{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := service.ValidateCreateShipmentRequest(&req); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	shipment, err := h.orderService.CreateShipment(c.Request.Context(), orderID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, shipment)
+}
+
+// ListShipments handles GET /api/v2/orders/:id/shipments
+func (h *Handlers) ListShipments(c *gin.Context) {
+	orderID := c.Param("id")
+
+	shipments, err := h.orderService.ListShipments(c.Request.Context(), orderID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id":  orderID,
+		"shipments": shipments,
+	})
+}
+
+// UpdateShipmentStatus handles PATCH /api/v2/orders/:id/shipments/:shipment_id
+func (h *Handlers) UpdateShipmentStatus(c *gin.Context) {
+	orderID := c.Param("id")
+	shipmentID := c.Param("shipment_id")
+
+	var req service.UpdateShipmentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := service.ValidateUpdateShipmentStatusRequest(&req); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	shipment, err := h.orderService.UpdateShipmentStatus(c.Request.Context(), orderID, shipmentID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
@@ -0,0 +1,159 @@
+// Package health runs readiness checks against the service's dependencies.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Status is the result of a single check.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Readiness is the overall result of a registry run.
+type Readiness string
+
+const (
+	// Ready means every check passed.
+	Ready Readiness = "ready"
+	// Degraded means only non-critical checks failed; the service keeps
+	// taking traffic with reduced functionality.
+	Degraded Readiness = "degraded"
+	// NotReady means a critical check failed.
+	NotReady Readiness = "not_ready"
+)
+
+// CheckFunc reports whether a dependency is usable. It must honour ctx.
+type CheckFunc func(ctx context.Context) error
+
+// Checker is a registered dependency check.
+type Checker struct {
+	Name string
+	// Critical checks make the service not ready when they fail; other
+	// failures only degrade it.
+	Critical bool
+	// Timeout bounds the check; the registry default is used when zero.
+	Timeout time.Duration
+	Check   CheckFunc
+}
+
+// ComponentStatus is the outcome of one check.
+type ComponentStatus struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	// Error summarises a failure as "timeout" or "unavailable". The probe
+	// is unauthenticated, so the underlying error, which can name hosts
+	// and addresses, is kept in Cause for logging only.
+	Error string `json:"error,omitempty"`
+	Cause error  `json:"-"`
+}
+
+// Report is the outcome of running every registered check.
+type Report struct {
+	Status     Readiness         `json:"status"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// Registry holds the dependency checks behind the readiness probe.
+type Registry struct {
+	mu             sync.RWMutex
+	checkers       []Checker
+	defaultTimeout time.Duration
+}
+
+// NewRegistry creates an empty registry whose checks time out after
+// defaultTimeout unless they set their own.
+func NewRegistry(defaultTimeout time.Duration) *Registry {
+	return &Registry{defaultTimeout: defaultTimeout}
+}
+
+// Register adds a check. Checks are reported in registration order.
+func (r *Registry) Register(checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, checker)
+}
+
+// Run executes every check concurrently and summarises the results.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := make([]Checker, len(r.checkers))
+	copy(checkers, r.checkers)
+	r.mu.RUnlock()
+
+	components := make([]ComponentStatus, len(checkers))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			components[i] = r.run(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	report := Report{Status: Ready, Components: components}
+	for _, component := range components {
+		if component.Status == StatusUp {
+			continue
+		}
+		if component.Critical {
+			report.Status = NotReady
+			break
+		}
+		report.Status = Degraded
+	}
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, checker Checker) ComponentStatus {
+	timeout := checker.Timeout
+	if timeout <= 0 {
+		timeout = r.defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runCheck(ctx, checker.Check)
+
+	status := ComponentStatus{
+		Name:      checker.Name,
+		Status:    StatusUp,
+		Critical:  checker.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = "unavailable"
+		if errors.Is(err, context.DeadlineExceeded) {
+			status.Error = "timeout"
+		}
+		status.Cause = err
+	}
+	return status
+}
+
+// runCheck returns when check does or ctx expires, whichever is first, so a
+// check that ignores its context cannot hold up the probe.
+func runCheck(ctx context.Context, check CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistryRun(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		checkers []Checker
+		want     Readiness
+	}{
+		{
+			name: "all up",
+			checkers: []Checker{
+				{Name: "postgres", Critical: true, Check: up},
+				{Name: "redis", Check: up},
+			},
+			want: Ready,
+		},
+		{
+			name: "non-critical down",
+			checkers: []Checker{
+				{Name: "postgres", Critical: true, Check: up},
+				{Name: "redis", Check: down},
+			},
+			want: Degraded,
+		},
+		{
+			name: "critical down",
+			checkers: []Checker{
+				{Name: "redis", Check: down},
+				{Name: "postgres", Critical: true, Check: down},
+			},
+			want: NotReady,
+		},
+		{
+			name: "critical timeout",
+			checkers: []Checker{
+				{Name: "postgres", Critical: true, Timeout: 10 * time.Millisecond, Check: hang},
+			},
+			want: NotReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(time.Second)
+			for _, checker := range tt.checkers {
+				registry.Register(checker)
+			}
+
+			report := registry.Run(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, report.Status)
+			}
+			if len(report.Components) != len(tt.checkers) {
+				t.Fatalf("expected %d components, got %d", len(tt.checkers), len(report.Components))
+			}
+			for i, component := range report.Components {
+				if component.Name != tt.checkers[i].Name {
+					t.Errorf("expected components in registration order, got %s at %d", component.Name, i)
+				}
+				if component.Status == StatusDown && component.Error == "" {
+					t.Errorf("expected an error for %s", component.Name)
+				}
+			}
+		})
+	}
+}
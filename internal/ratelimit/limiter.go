@@ -0,0 +1,89 @@
+// Package ratelimit implements token-bucket request quotas backed by
+// process memory or Redis.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+// Policy is a named token bucket: Burst requests may be made at once, and
+// the bucket refills at Rate tokens a second.
+type Policy struct {
+	Name  string
+	Rate  float64
+	Burst int
+}
+
+// NewPolicy converts a configured per-minute policy.
+func NewPolicy(name string, cfg config.RateLimitPolicy) Policy {
+	return Policy{
+		Name:  name,
+		Rate:  float64(cfg.PerMinute) / 60,
+		Burst: cfg.Burst,
+	}
+}
+
+// Result is the outcome of charging a request to a bucket.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a request would be allowed; zero when
+	// this one was.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Limiter charges requests against per-key buckets.
+type Limiter interface {
+	// Allow takes one token from key's bucket under policy if one is
+	// available.
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// refill returns the tokens in a bucket that held tokens elapsed ago.
+func refill(tokens float64, elapsed time.Duration, policy Policy) float64 {
+	if elapsed > 0 {
+		tokens += elapsed.Seconds() * policy.Rate
+	}
+	return math.Min(tokens, float64(policy.Burst))
+}
+
+// take tries to remove a token from a bucket holding tokens and returns
+// what is left along with the result.
+func take(tokens float64, policy Policy) (float64, Result) {
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return tokens, newResult(allowed, tokens, policy)
+}
+
+func newResult(allowed bool, tokens float64, policy Policy) Result {
+	result := Result{
+		Allowed:    allowed,
+		Limit:      policy.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: until(float64(policy.Burst)-tokens, policy.Rate),
+	}
+	if !allowed {
+		result.RetryAfter = until(1-tokens, policy.Rate)
+	}
+	return result
+}
+
+// until returns how long the bucket takes to gain missing tokens.
+func until(missing, rate float64) time.Duration {
+	if missing <= 0 {
+		return 0
+	}
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(missing / rate * float64(time.Second))
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped from memory.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	policy  Policy
+}
+
+// MemoryLimiter keeps buckets in process memory, so each replica enforces
+// its own limits.
+type MemoryLimiter struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryLimiter creates an empty in-memory limiter.
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow implements Limiter.
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	defer observeCheck("memory", time.Now())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(policy.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.policy = policy
+
+	var result Result
+	b.tokens, result = take(refill(b.tokens, now.Sub(b.updated), policy), policy)
+	b.updated = now
+	return result, nil
+}
+
+// sweep drops buckets that have refilled completely, since a new bucket
+// would be identical.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if refill(b.tokens, now.Sub(b.updated), b.policy) >= float64(b.policy.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+
+	policy := Policy{Name: "test", Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	for i, wantRemaining := range []int{1, 0} {
+		result, err := l.Allow(ctx, "user_1", policy)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Allowed || result.Remaining != wantRemaining {
+			t.Errorf("request %d: expected allowed with %d remaining, got %+v", i, wantRemaining, result)
+		}
+	}
+
+	result, _ := l.Allow(ctx, "user_1", policy)
+	if result.Allowed {
+		t.Fatal("expected the third request to be limited")
+	}
+	if result.RetryAfter != time.Second || result.ResetAfter != 2*time.Second {
+		t.Errorf("expected retry after 1s and reset after 2s, got %+v", result)
+	}
+
+	if result, _ := l.Allow(ctx, "user_2", policy); !result.Allowed {
+		t.Error("expected another key to have its own bucket")
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	result, _ = l.Allow(ctx, "user_1", policy)
+	if !result.Allowed || result.Remaining != 0 {
+		t.Errorf("expected one refilled token to be used, got %+v", result)
+	}
+
+	now = now.Add(time.Hour)
+	l.Allow(ctx, "user_3", policy)
+	if _, ok := l.buckets["user_1"]; ok {
+		t.Error("expected the refilled bucket to be swept")
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	policy := Policy{Rate: 0.5, Burst: 10}
+
+	result := newResult(false, 0.5, policy)
+	if result.Allowed || result.Remaining != 0 || result.Limit != 10 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("expected retry after 1s, got %v", result.RetryAfter)
+	}
+	if result.ResetAfter != 19*time.Second {
+		t.Errorf("expected reset after 19s, got %v", result.ResetAfter)
+	}
+}
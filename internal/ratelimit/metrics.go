@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_ratelimit_requests_total",
+		Help: "Requests checked against a rate limit policy by result (allowed, limited or error).",
+	}, []string{"policy", "result"})
+	checkDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "orders_ratelimit_check_duration_seconds",
+		Help:    "Time taken to check a rate limit by backend.",
+		Buckets: []float64{.0001, .0005, .001, .005, .01, .025, .05, .1},
+	}, []string{"backend"})
+)
+
+// RecordDecision counts a request checked under policy. A limiter error
+// is counted separately, since such requests are let through.
+func RecordDecision(policy string, result Result, err error) {
+	outcome := "allowed"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case !result.Allowed:
+		outcome = "limited"
+	}
+	requests.WithLabelValues(policy, outcome).Inc()
+}
+
+func observeCheck(backend string, start time.Time) {
+	checkDuration.WithLabelValues(backend).Observe(time.Since(start).Seconds())
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
@@ -38,10 +39,15 @@ func NewRedisOrderCache(cfg config.RedisConfig) *RedisOrderCache {
 		ttl = defaultCacheTTL
 	}
 
+	logger := logging.NewLoggerV2("order-cache")
+	if err := redisotel.InstrumentTracing(client, redisotel.WithDBStatement(false)); err != nil {
+		logger.Error("Failed to instrument Redis tracing", logging.Fields{"error": err.Error()})
+	}
+
 	return &RedisOrderCache{
 		client: client,
 		ttl:    ttl,
-		logger: logging.NewLoggerV2("order-cache"),
+		logger: logger,
 	}
 }
 
@@ -49,15 +55,16 @@ func NewRedisOrderCache(cfg config.RedisConfig) *RedisOrderCache {
 func (c *RedisOrderCache) Get(ctx context.Context, id string) (*models.Order, error) {
 	key := orderKeyPrefix + id
 
-	// TODO(TEAM-PLATFORM): Add metrics for cache hits/misses
 	logging.Infof("Cache: Getting order %s", id)
 
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
+		recordCacheLookup("get", false, nil)
 		c.logger.Debug("Cache miss", logging.Fields{"order_id": id})
 		return nil, nil
 	}
 	if err != nil {
+		recordCacheLookup("get", false, err)
 		c.logger.Error("Cache get error", logging.Fields{
 			"order_id": id,
 			"error":    err.Error(),
@@ -70,6 +77,7 @@ func (c *RedisOrderCache) Get(ctx context.Context, id string) (*models.Order, er
 		return nil, err
 	}
 
+	recordCacheLookup("get", true, nil)
 	c.logger.Debug("Cache hit", logging.Fields{"order_id": id})
 	return &order, nil
 }
@@ -98,6 +106,54 @@ func (c *RedisOrderCache) Set(ctx context.Context, order *models.Order) error {
 	return nil
 }
 
+// GetVersioned retrieves an order and its version from cache. Orders cached
+// without a version by Set count as a miss.
+func (c *RedisOrderCache) GetVersioned(ctx context.Context, id string) (*VersionedOrder, error) {
+	data, err := c.client.Get(ctx, orderKeyPrefix+id).Bytes()
+	if err == redis.Nil {
+		recordCacheLookup("get_versioned", false, nil)
+		return nil, nil
+	}
+	if err != nil {
+		recordCacheLookup("get_versioned", false, err)
+		c.logger.Error("Cache get error", logging.Fields{
+			"order_id": id,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	var order VersionedOrder
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+	if order.Order == nil || order.Version == 0 {
+		recordCacheLookup("get_versioned", false, nil)
+		return nil, nil
+	}
+
+	recordCacheLookup("get_versioned", true, nil)
+	return &order, nil
+}
+
+// SetVersioned stores an order with its version in cache. Get still reads
+// the entry as a plain order.
+func (c *RedisOrderCache) SetVersioned(ctx context.Context, order *VersionedOrder) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	if err := c.client.Set(ctx, orderKeyPrefix+order.ID, data, c.ttl).Err(); err != nil {
+		c.logger.Error("Cache set error", logging.Fields{
+			"order_id": order.ID,
+			"error":    err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
 // Delete removes an order from cache.
 func (c *RedisOrderCache) Delete(ctx context.Context, id string) error {
 	key := orderKeyPrefix + id
@@ -114,6 +170,11 @@ func (c *RedisOrderCache) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Ping checks that Redis is reachable.
+func (c *RedisOrderCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
 // GetByUserID retrieves cached orders for a user.
 func (c *RedisOrderCache) GetByUserID(ctx context.Context, userID string) ([]*models.Order, error) {
 	key := userOrdersPrefix + userID
@@ -123,9 +184,11 @@ func (c *RedisOrderCache) GetByUserID(ctx context.Context, userID string) ([]*mo
 
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
+		recordCacheLookup("get_by_user", false, nil)
 		return nil, nil
 	}
 	if err != nil {
+		recordCacheLookup("get_by_user", false, err)
 		return nil, err
 	}
 
@@ -134,6 +197,7 @@ func (c *RedisOrderCache) GetByUserID(ctx context.Context, userID string) ([]*mo
 		return nil, err
 	}
 
+	recordCacheLookup("get_by_user", true, nil)
 	return orders, nil
 }
 
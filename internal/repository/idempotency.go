@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// IdempotencyRecord is a stored request fingerprint and, once the request has
+// completed, the response that was returned for it.
+type IdempotencyRecord struct {
+	Key          string
+	Scope        string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	Completed    bool
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// IdempotencyStore persists idempotency keys for retried requests.
+type IdempotencyStore interface {
+	// Acquire claims key within scope for a request with the given hash.
+	// If the key is already held, the existing record is returned and
+	// acquired is false.
+	Acquire(ctx context.Context, key, scope, requestHash string, ttl time.Duration) (record *IdempotencyRecord, acquired bool, err error)
+	// Complete stores the response for a previously acquired key.
+	Complete(ctx context.Context, key, scope string, statusCode int, body []byte) error
+	// Release drops an acquired key so the request can be retried.
+	Release(ctx context.Context, key, scope string) error
+	// DeleteExpiredBefore removes up to limit keys that expired before the
+	// given time and returns how many were removed.
+	DeleteExpiredBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// PostgresIdempotencyStore implements IdempotencyStore using PostgreSQL.
+type PostgresIdempotencyStore struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresIdempotencyStore creates a new PostgreSQL idempotency store.
+func NewPostgresIdempotencyStore(db *sql.DB, logger *logging.LoggerV2) *PostgresIdempotencyStore {
+	return &PostgresIdempotencyStore{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Acquire claims an idempotency key. Expired keys are taken over. A held
+// key can be released or pruned between the claim and the read of the
+// existing record; the claim is then retried once.
+func (s *PostgresIdempotencyStore) Acquire(ctx context.Context, key, scope, requestHash string, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	record, acquired, err := s.acquire(ctx, key, scope, requestHash, ttl)
+	if err == sql.ErrNoRows {
+		record, acquired, err = s.acquire(ctx, key, scope, requestHash, ttl)
+	}
+	return record, acquired, err
+}
+
+func (s *PostgresIdempotencyStore) acquire(ctx context.Context, key, scope, requestHash string, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	now := time.Now()
+
+	query := `
+		INSERT INTO idempotency_keys (idempotency_key, scope, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (idempotency_key, scope) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = NULL,
+		    response_body = NULL,
+		    created_at = EXCLUDED.created_at,
+		    completed_at = NULL,
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < $4
+		RETURNING idempotency_key
+	`
+
+	var returnedKey string
+	err := instrument(s.db).QueryRowContext(ctx, query, key, scope, requestHash, now, now.Add(ttl)).Scan(&returnedKey)
+	if err == nil {
+		return &IdempotencyRecord{
+			Key:         key,
+			Scope:       scope,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}, true, nil
+	}
+	if err != sql.ErrNoRows {
+		s.logger.Error("Failed to acquire idempotency key", logging.Fields{
+			"scope": scope,
+			"error": err.Error(),
+		})
+		return nil, false, err
+	}
+
+	record, err := s.get(ctx, key, scope)
+	if err != nil {
+		return nil, false, err
+	}
+	return record, false, nil
+}
+
+// Complete stores the response for an acquired key.
+func (s *PostgresIdempotencyStore) Complete(ctx context.Context, key, scope string, statusCode int, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_body = $4, completed_at = $5
+		WHERE idempotency_key = $1 AND scope = $2
+	`
+
+	if _, err := instrument(s.db).ExecContext(ctx, query, key, scope, statusCode, body, time.Now()); err != nil {
+		s.logger.Error("Failed to store idempotent response", logging.Fields{
+			"scope": scope,
+			"error": err.Error(),
+		})
+		return err
+	}
+
+	return nil
+}
+
+// Release deletes an uncompleted key.
+func (s *PostgresIdempotencyStore) Release(ctx context.Context, key, scope string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE idempotency_key = $1 AND scope = $2 AND completed_at IS NULL
+	`
+
+	_, err := instrument(s.db).ExecContext(ctx, query, key, scope)
+	return err
+}
+
+// DeleteExpiredBefore deletes the oldest expired keys in bounded batches so
+// pruning never holds locks on a large part of the table.
+func (s *PostgresIdempotencyStore) DeleteExpiredBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE (idempotency_key, scope) IN (
+			SELECT idempotency_key, scope FROM idempotency_keys
+			WHERE expires_at < $1
+			ORDER BY expires_at
+			LIMIT $2
+		)
+	`
+
+	result, err := instrument(s.db).ExecContext(ctx, query, before, limit)
+	if err != nil {
+		s.logger.Error("Failed to prune idempotency keys", logging.Fields{
+			"before": before,
+			"error":  err.Error(),
+		})
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (s *PostgresIdempotencyStore) get(ctx context.Context, key, scope string) (*IdempotencyRecord, error) {
+	query := `
+		SELECT idempotency_key, scope, request_hash, status_code, response_body,
+		       completed_at, created_at, expires_at
+		FROM idempotency_keys
+		WHERE idempotency_key = $1 AND scope = $2
+	`
+
+	var record IdempotencyRecord
+	var statusCode sql.NullInt64
+	var completedAt sql.NullTime
+
+	err := instrument(s.db).QueryRowContext(ctx, query, key, scope).Scan(
+		&record.Key,
+		&record.Scope,
+		&record.RequestHash,
+		&statusCode,
+		&record.ResponseBody,
+		&completedAt,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode.Valid {
+		record.StatusCode = int(statusCode.Int64)
+	}
+	record.Completed = completedAt.Valid
+
+	return &record, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// scriptedDB is a database/sql driver that answers each query with the next
+// scripted result set and records the statements it was sent.
+type scriptedDB struct {
+	results [][][]driver.Value
+	queries *[]string
+}
+
+func (d *scriptedDB) Open(name string) (driver.Conn, error) { return d, nil }
+
+func (d *scriptedDB) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (d *scriptedDB) Close() error              { return nil }
+func (d *scriptedDB) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (d *scriptedDB) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	*d.queries = append(*d.queries, strings.Fields(query)[0])
+	if len(d.results) == 0 {
+		return nil, errors.New("unexpected query")
+	}
+	rows := d.results[0]
+	d.results = d.results[1:]
+	return &scriptedRows{rows: rows}, nil
+}
+
+type scriptedRows struct {
+	rows [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"idempotency_key"}
+	}
+	return make([]string, len(r.rows[0]))
+}
+func (r *scriptedRows) Close() error { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type scriptedConnector struct{ d *scriptedDB }
+
+func (c scriptedConnector) Connect(context.Context) (driver.Conn, error) { return c.d, nil }
+func (c scriptedConnector) Driver() driver.Driver                        { return c.d }
+
+func TestAcquireRetriesWhenHeldKeyDisappears(t *testing.T) {
+	var queries []string
+	db := sql.OpenDB(scriptedConnector{&scriptedDB{
+		results: [][][]driver.Value{
+			{},          // the key is held by another request
+			{},          // which released it before it could be read
+			{{"key_1"}}, // so the retried claim succeeds
+		},
+		queries: &queries,
+	}})
+	defer db.Close()
+	store := NewPostgresIdempotencyStore(db, logging.NewLoggerV2("test"))
+
+	record, acquired, err := store.Acquire(context.Background(), "key_1", "POST /orders", "hash", time.Hour)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if !acquired || record.Key != "key_1" {
+		t.Errorf("expected the retried claim to acquire key_1, got acquired=%v record=%+v", acquired, record)
+	}
+	assertEvents(t, queries, "INSERT", "SELECT", "INSERT")
+}
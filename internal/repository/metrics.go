@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "orders_db_query_duration_seconds",
+		Help:    "Duration of database statements by operation and result.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "result"})
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_cache_requests_total",
+		Help: "Order cache lookups by operation and result (hit, miss or error).",
+	}, []string{"operation", "result"})
+)
+
+// instrumentedQuerier records the duration of every statement run through
+// it and traces it as a client span. Time spent iterating rows is not
+// included.
+type instrumentedQuerier struct {
+	q querier
+}
+
+func instrument(q querier) querier {
+	return instrumentedQuerier{q: q}
+}
+
+func (i instrumentedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := startQuery(ctx, query)
+	result, err := i.q.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+func (i instrumentedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := startQuery(ctx, query)
+	rows, err := i.q.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (i instrumentedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, done := startQuery(ctx, query)
+	row := i.q.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+// startQuery opens a span for query; the returned function ends it and
+// records the statement's duration and result.
+func startQuery(ctx context.Context, query string) (context.Context, func(err error)) {
+	operation := queryOperation(query)
+	ctx, span := tracing.Tracer().Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(strings.TrimSpace(query)),
+			semconv.DBOperationName(operation),
+		),
+	)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		result := "ok"
+		if err != nil && err != sql.ErrNoRows {
+			result = "error"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		dbQueryDuration.WithLabelValues(operation, result).Observe(time.Since(start).Seconds())
+	}
+}
+
+// queryOperation names a statement by its verb and the table it targets,
+// such as "select_orders" or "insert_order_outbox", keeping the metric's
+// label cardinality bounded by the schema.
+func queryOperation(query string) string {
+	fields := strings.Fields(strings.ToLower(query))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	verb := fields[0]
+	var marker string
+	switch verb {
+	case "select", "delete":
+		marker = "from"
+	case "insert":
+		marker = "into"
+	case "update":
+		marker = "update"
+	default:
+		return verb
+	}
+
+	for i := 0; i < len(fields)-1; i++ {
+		if fields[i] != marker {
+			continue
+		}
+		if table := strings.Trim(fields[i+1], "(),;"); table != "" {
+			return verb + "_" + table
+		}
+	}
+	return verb
+}
+
+func recordCacheLookup(operation string, hit bool, err error) {
+	result := "miss"
+	switch {
+	case err != nil:
+		result = "error"
+	case hit:
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(operation, result).Inc()
+}
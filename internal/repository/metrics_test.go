@@ -0,0 +1,24 @@
+package repository
+
+import "testing"
+
+func TestQueryOperation(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT id, status FROM orders WHERE id = $1", "select_orders"},
+		{"\n\t\tINSERT INTO order_outbox (id, payload) VALUES ($1, $2)", "insert_order_outbox"},
+		{"UPDATE returns SET status = $2 WHERE id = $1", "update_returns"},
+		{"DELETE FROM idempotency_keys WHERE expires_at < $1", "delete_idempotency_keys"},
+		{"select count(*) from(select 1) t", "select"},
+		{"WITH pending AS (SELECT 1) SELECT * FROM pending", "with"},
+		{"   ", "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := queryOperation(tt.query); got != tt.want {
+			t.Errorf("queryOperation(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// OrderSort is a sort order accepted by ListPage.
+type OrderSort string
+
+const (
+	SortCreatedDesc OrderSort = "created_at_desc"
+	SortCreatedAsc  OrderSort = "created_at_asc"
+	SortUpdatedDesc OrderSort = "updated_at_desc"
+	SortUpdatedAsc  OrderSort = "updated_at_asc"
+	SortTotalDesc   OrderSort = "total_desc"
+	SortTotalAsc    OrderSort = "total_asc"
+)
+
+// DefaultOrderSort lists the newest orders first.
+const DefaultOrderSort = SortCreatedDesc
+
+type sortSpec struct {
+	column string
+	desc   bool
+	// key returns the value of column for order, as stored in a cursor.
+	key func(order *models.Order) interface{}
+}
+
+var orderSorts = map[OrderSort]sortSpec{
+	SortCreatedDesc: {column: "created_at", desc: true, key: func(o *models.Order) interface{} { return o.CreatedAt }},
+	SortCreatedAsc:  {column: "created_at", desc: false, key: func(o *models.Order) interface{} { return o.CreatedAt }},
+	SortUpdatedDesc: {column: "updated_at", desc: true, key: func(o *models.Order) interface{} { return o.UpdatedAt }},
+	SortUpdatedAsc:  {column: "updated_at", desc: false, key: func(o *models.Order) interface{} { return o.UpdatedAt }},
+	SortTotalDesc:   {column: "total_amount", desc: true, key: func(o *models.Order) interface{} { return o.Total.Amount }},
+	SortTotalAsc:    {column: "total_amount", desc: false, key: func(o *models.Order) interface{} { return o.Total.Amount }},
+}
+
+// ValidOrderSort reports whether sort is supported.
+func ValidOrderSort(sort OrderSort) bool {
+	_, ok := orderSorts[sort]
+	return ok
+}
+
+// OrderQuery selects a page of orders. Zero-valued fields do not filter.
+type OrderQuery struct {
+	UserID   string
+	Statuses []models.OrderStatus
+	// CreatedFrom and CreatedTo bound created_at, inclusive.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// MinTotal and MaxTotal bound the order total in minor units, inclusive.
+	MinTotal  *int64
+	MaxTotal  *int64
+	Currency  string
+	PaymentID string
+	// Country matches the shipping address country.
+	Country string
+	// ProductID matches orders containing at least one item of the product.
+	ProductID string
+
+	Sort  OrderSort
+	Limit int
+	// Cursor is the NextCursor of the previous page, or empty for the first.
+	Cursor string
+	// Offset skips that many orders instead of resuming from a cursor.
+	// Deprecated: kept for existing v2 clients; use Cursor.
+	Offset int
+}
+
+// OrderPage is one page of a keyset-paginated order listing.
+type OrderPage struct {
+	Orders []*models.Order `json:"orders"`
+	// NextCursor fetches the following page; it is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total counts every order matching the filters, across all pages.
+	Total int `json:"total"`
+}
+
+// orderCursor is the position after the last order of a page. It is handed
+// to clients base64-encoded and must be treated as opaque.
+type orderCursor struct {
+	Sort OrderSort  `json:"s"`
+	Time *time.Time `json:"t,omitempty"`
+	Int  *int64     `json:"n,omitempty"`
+	ID   string     `json:"id"`
+}
+
+func encodeCursor(sort OrderSort, order *models.Order) string {
+	cursor := orderCursor{Sort: sort, ID: order.ID}
+	switch key := orderSorts[sort].key(order).(type) {
+	case time.Time:
+		cursor.Time = &key
+	case int64:
+		cursor.Int = &key
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string, sort OrderSort) (interface{}, string, error) {
+	invalid := errors.NewValidationError("cursor", "invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, "", invalid
+	}
+
+	var cursor orderCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, "", invalid
+	}
+	if cursor.Sort != sort {
+		return nil, "", errors.NewValidationError("cursor", "cursor was issued for a different sort order")
+	}
+
+	switch {
+	case cursor.Time != nil:
+		return *cursor.Time, cursor.ID, nil
+	case cursor.Int != nil:
+		return *cursor.Int, cursor.ID, nil
+	default:
+		return nil, "", invalid
+	}
+}
+
+// queryBuilder accumulates WHERE conditions and their numbered placeholders.
+type queryBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+func newQueryBuilder() *queryBuilder {
+	return &queryBuilder{conds: []string{"deleted_at IS NULL"}}
+}
+
+// arg binds v and returns its placeholder.
+func (b *queryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func (b *queryBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+func (b *queryBuilder) whereClause() string {
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+// filterOrders adds the conditions of q, other than paging, to b.
+func (b *queryBuilder) filterOrders(q *OrderQuery) {
+	if q.UserID != "" {
+		b.where("user_id = " + b.arg(q.UserID))
+	}
+
+	if len(q.Statuses) > 0 {
+		placeholders := make([]string, len(q.Statuses))
+		for i, status := range q.Statuses {
+			placeholders[i] = b.arg(status)
+		}
+		b.where("status IN (" + strings.Join(placeholders, ", ") + ")")
+	}
+
+	if q.CreatedFrom != nil {
+		b.where("created_at >= " + b.arg(*q.CreatedFrom))
+	}
+	if q.CreatedTo != nil {
+		b.where("created_at <= " + b.arg(*q.CreatedTo))
+	}
+
+	if q.MinTotal != nil {
+		b.where("total_amount >= " + b.arg(*q.MinTotal))
+	}
+	if q.MaxTotal != nil {
+		b.where("total_amount <= " + b.arg(*q.MaxTotal))
+	}
+	if q.Currency != "" {
+		b.where("total_currency = " + b.arg(strings.ToUpper(q.Currency)))
+	}
+
+	if q.PaymentID != "" {
+		b.where("payment_id = " + b.arg(q.PaymentID))
+	}
+
+	if q.Country != "" {
+		b.where("upper(shipping_address->>'country') = " + b.arg(strings.ToUpper(q.Country)))
+	}
+
+	if q.ProductID != "" {
+		contains, _ := json.Marshal([]map[string]string{{"product_id": q.ProductID}})
+		b.where("items @> " + b.arg(string(contains)) + "::jsonb")
+	}
+}
+
+// after adds the keyset condition that resumes a listing after (key, id).
+func (b *queryBuilder) after(spec sortSpec, key interface{}, id string) {
+	op := ">"
+	if spec.desc {
+		op = "<"
+	}
+	b.where("(" + spec.column + ", id) " + op + " (" + b.arg(key) + ", " + b.arg(id) + ")")
+}
+
+func orderByClause(spec sortSpec) string {
+	dir := "ASC"
+	if spec.desc {
+		dir = "DESC"
+	}
+	return " ORDER BY " + spec.column + " " + dir + ", id " + dir
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func TestQueryBuilder_NumbersPlaceholdersPastNine(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	min, max := int64(100), int64(5000)
+
+	b := newQueryBuilder()
+	b.filterOrders(&OrderQuery{
+		UserID:      "user_1",
+		Statuses:    []models.OrderStatus{models.OrderStatusPending, models.OrderStatusShipped},
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		MinTotal:    &min,
+		MaxTotal:    &max,
+		Currency:    "usd",
+		PaymentID:   "pay_1",
+		Country:     "us",
+		ProductID:   "prod_1",
+	})
+
+	if len(b.args) != 11 {
+		t.Fatalf("Expected 11 args, got %d", len(b.args))
+	}
+
+	where := b.whereClause()
+	for _, want := range []string{
+		"status IN ($2, $3)",
+		"total_currency = $8",
+		"payment_id = $9",
+		"upper(shipping_address->>'country') = $10",
+		"items @> $11::jsonb",
+	} {
+		if !strings.Contains(where, want) {
+			t.Errorf("Expected WHERE clause to contain %q, got %s", want, where)
+		}
+	}
+	if b.args[10] != `[{"product_id":"prod_1"}]` {
+		t.Errorf("Unexpected product filter %v", b.args[10])
+	}
+}
+
+func TestOrderCursor_RoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 5, 10, 30, 0, 123, time.UTC)
+	order := &models.Order{ID: "ord_1", CreatedAt: created, Total: models.Money{Amount: 4200, Currency: "USD"}}
+
+	key, id, err := decodeCursor(encodeCursor(SortCreatedDesc, order), SortCreatedDesc)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if id != "ord_1" || !key.(time.Time).Equal(created) {
+		t.Errorf("Expected (%v, ord_1), got (%v, %s)", created, key, id)
+	}
+
+	key, _, err = decodeCursor(encodeCursor(SortTotalAsc, order), SortTotalAsc)
+	if err != nil || key.(int64) != 4200 {
+		t.Errorf("Expected total key 4200, got %v (%v)", key, err)
+	}
+}
+
+func TestOrderCursor_Rejected(t *testing.T) {
+	order := &models.Order{ID: "ord_1", CreatedAt: time.Now()}
+
+	if _, _, err := decodeCursor(encodeCursor(SortCreatedDesc, order), SortTotalDesc); err == nil {
+		t.Error("Expected cursor for another sort order to be rejected")
+	}
+	if _, _, err := decodeCursor("not a cursor!", SortCreatedDesc); err == nil {
+		t.Error("Expected malformed cursor to be rejected")
+	}
+}
+
+func TestAfter_Direction(t *testing.T) {
+	b := newQueryBuilder()
+	b.after(orderSorts[SortCreatedDesc], time.Now(), "ord_1")
+	if !strings.Contains(b.whereClause(), "(created_at, id) < ($1, $2)") {
+		t.Errorf("Unexpected keyset condition: %s", b.whereClause())
+	}
+
+	b = newQueryBuilder()
+	b.after(orderSorts[SortTotalAsc], int64(10), "ord_1")
+	if !strings.Contains(b.whereClause(), "(total_amount, id) > ($1, $2)") {
+		t.Errorf("Unexpected keyset condition: %s", b.whereClause())
+	}
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// OutboxStatus is the delivery state of an outbox message.
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// OutboxMessage is an event waiting to be relayed to Kafka.
+type OutboxMessage struct {
+	ID            int64
+	EventID       string
+	EventType     string
+	AggregateID   string
+	Payload       []byte
+	Status        OutboxStatus
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+}
+
+// OutboxStats summarises undelivered outbox messages.
+type OutboxStats struct {
+	Pending         int
+	Failed          int
+	OldestPendingAt *time.Time
+}
+
+// OutboxRepository stores events in the order_outbox table.
+type OutboxRepository interface {
+	// Enqueue inserts a message. Call it with a transactional context so the
+	// message commits together with the order change it describes.
+	Enqueue(ctx context.Context, msg *OutboxMessage) error
+	// ClaimPending locks up to limit deliverable messages for the current
+	// transaction, oldest first, skipping aggregates with an earlier
+	// undelivered message so per-order ordering is preserved.
+	ClaimPending(ctx context.Context, limit int) ([]*OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id int64, lastError string) error
+	Stats(ctx context.Context) (*OutboxStats, error)
+	// DeleteSentBefore removes up to limit messages delivered before the
+	// given time and returns how many were removed. Failed messages are kept.
+	DeleteSentBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// PostgresOutboxRepository implements OutboxRepository using PostgreSQL.
+type PostgresOutboxRepository struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresOutboxRepository creates a new PostgreSQL outbox repository.
+func NewPostgresOutboxRepository(db *sql.DB, logger *logging.LoggerV2) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Enqueue inserts a pending outbox message.
+func (r *PostgresOutboxRepository) Enqueue(ctx context.Context, msg *OutboxMessage) error {
+	r.logger.Debug("Enqueuing outbox message", logging.Fields{
+		"event_id":     msg.EventID,
+		"event_type":   msg.EventType,
+		"aggregate_id": msg.AggregateID,
+	})
+
+	query := `
+		INSERT INTO order_outbox (event_id, event_type, aggregate_id, payload, status, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		msg.EventID,
+		msg.EventType,
+		msg.AggregateID,
+		msg.Payload,
+		OutboxStatusPending,
+		now,
+	).Scan(&msg.ID)
+	if err != nil {
+		r.logger.Error("Failed to enqueue outbox message", logging.Fields{
+			"event_id": msg.EventID,
+			"error":    err.Error(),
+		})
+		return err
+	}
+
+	msg.Status = OutboxStatusPending
+	msg.CreatedAt = now
+	msg.NextAttemptAt = now
+	return nil
+}
+
+// ClaimPending locks deliverable messages with FOR UPDATE SKIP LOCKED so
+// several relays can drain the outbox concurrently.
+func (r *PostgresOutboxRepository) ClaimPending(ctx context.Context, limit int) ([]*OutboxMessage, error) {
+	query := `
+		SELECT o.id, o.event_id, o.event_type, o.aggregate_id, o.payload, o.status,
+		       o.attempts, o.last_error, o.created_at, o.next_attempt_at
+		FROM order_outbox o
+		WHERE o.status = $1
+		  AND o.next_attempt_at <= $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM order_outbox earlier
+		      WHERE earlier.aggregate_id = o.aggregate_id
+		        AND earlier.status = $1
+		        AND earlier.id < o.id
+		  )
+		ORDER BY o.id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, OutboxStatusPending, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]*OutboxMessage, 0)
+	for rows.Next() {
+		var msg OutboxMessage
+		var lastError sql.NullString
+		if err := rows.Scan(
+			&msg.ID,
+			&msg.EventID,
+			&msg.EventType,
+			&msg.AggregateID,
+			&msg.Payload,
+			&msg.Status,
+			&msg.Attempts,
+			&lastError,
+			&msg.CreatedAt,
+			&msg.NextAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+		if lastError.Valid {
+			msg.LastError = lastError.String
+		}
+		messages = append(messages, &msg)
+	}
+
+	return messages, rows.Err()
+}
+
+// MarkSent records a successful delivery.
+func (r *PostgresOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	query := `
+		UPDATE order_outbox
+		SET status = $2, sent_at = $3, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, OutboxStatusSent, time.Now())
+	return err
+}
+
+// MarkRetry records a failed delivery and schedules the next attempt.
+func (r *PostgresOutboxRepository) MarkRetry(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE order_outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, lastError, nextAttemptAt)
+	return err
+}
+
+// MarkFailed gives up on a message after too many attempts. Failed messages
+// stay in the table for inspection and can be requeued by resetting status.
+func (r *PostgresOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	query := `
+		UPDATE order_outbox
+		SET status = $2, attempts = attempts + 1, last_error = $3
+		WHERE id = $1
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, OutboxStatusFailed, lastError)
+	return err
+}
+
+// Stats returns counts of undelivered messages and the age of the oldest one.
+func (r *PostgresOutboxRepository) Stats(ctx context.Context) (*OutboxStats, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE status = $1),
+		       COUNT(*) FILTER (WHERE status = $2),
+		       MIN(created_at) FILTER (WHERE status = $1)
+		FROM order_outbox
+		WHERE status IN ($1, $2)
+	`
+
+	var stats OutboxStats
+	var oldest sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, OutboxStatusPending, OutboxStatusFailed).Scan(
+		&stats.Pending,
+		&stats.Failed,
+		&oldest,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldest.Valid {
+		stats.OldestPendingAt = &oldest.Time
+	}
+
+	return &stats, nil
+}
+
+// DeleteSentBefore deletes the oldest delivered messages in bounded batches
+// so pruning never holds locks on a large part of the table.
+func (r *PostgresOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM order_outbox
+		WHERE id IN (
+			SELECT id FROM order_outbox
+			WHERE status = $1 AND sent_at < $2
+			ORDER BY sent_at
+			LIMIT $3
+		)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, OutboxStatusSent, before, limit)
+	if err != nil {
+		r.logger.Error("Failed to prune outbox messages", logging.Fields{
+			"before": before,
+			"error":  err.Error(),
+		})
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
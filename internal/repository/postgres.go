@@ -27,23 +27,35 @@ func NewPostgresOrderRepository(db *sql.DB, logger *logging.LoggerV2) *PostgresO
 
 // GetByID retrieves an order by its unique identifier.
 func (r *PostgresOrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	versioned, err := r.GetVersioned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return versioned.Order, nil
+}
+
+// GetVersioned retrieves an order and its current version.
+func (r *PostgresOrderRepository) GetVersioned(ctx context.Context, id string) (*VersionedOrder, error) {
 	r.logger.Debug("Fetching order by ID", logging.Fields{"order_id": id})
 
 	query := `
 		SELECT id, user_id, status, items, shipping_address, billing_address,
 		       subtotal_amount, subtotal_currency, tax_amount, tax_currency,
 		       shipping_amount, shipping_currency, total_amount, total_currency,
-		       payment_id, notes, created_at, updated_at, shipped_at, delivered_at
+		       payment_id, notes, created_at, updated_at, shipped_at, delivered_at,
+		       version, payment_status
 		FROM orders
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var order models.Order
+	var version int64
+	var paymentStatus models.PaymentStatus
 	var itemsJSON, shippingJSON, billingJSON []byte
 	var shippedAt, deliveredAt sql.NullTime
 	var paymentID, notes sql.NullString
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&order.ID,
 		&order.UserID,
 		&order.Status,
@@ -64,6 +76,8 @@ func (r *PostgresOrderRepository) GetByID(ctx context.Context, id string) (*mode
 		&order.UpdatedAt,
 		&shippedAt,
 		&deliveredAt,
+		&version,
+		&paymentStatus,
 	)
 
 	if err == sql.ErrNoRows {
@@ -103,43 +117,60 @@ func (r *PostgresOrderRepository) GetByID(ctx context.Context, id string) (*mode
 	r.logger.Info("Order fetched successfully", logging.Fields{
 		"order_id": order.ID,
 		"status":   order.Status,
+		"version":  version,
 	})
 
-	return &order, nil
+	return &VersionedOrder{Order: &order, Version: version, PaymentStatus: paymentStatus}, nil
 }
 
-// Create creates a new order.
+// Create creates a new order, pricing it from the item totals in req.
 func (r *PostgresOrderRepository) Create(ctx context.Context, req *models.CreateOrderRequest) (*models.Order, error) {
-	r.logger.Debug("Creating new order", logging.Fields{"user_id": req.UserID})
-
-	// TODO(TEAM-API): Add idempotency key support
 	order := &models.Order{
-		ID:              generateOrderID(),
 		UserID:          req.UserID,
 		Status:          models.OrderStatusPending,
 		Items:           req.Items,
 		ShippingAddress: req.ShippingAddress,
 		BillingAddress:  req.BillingAddress,
 		Notes:           req.Notes,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
 	}
 
 	order.CalculateTotal()
 
+	if err := r.Insert(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Insert stores a fully priced order. ID, status and timestamps are filled
+// in when empty.
+func (r *PostgresOrderRepository) Insert(ctx context.Context, order *models.Order) error {
+	r.logger.Debug("Creating new order", logging.Fields{"user_id": order.UserID})
+
+	if order.ID == "" {
+		order.ID = generateOrderID()
+	}
+	if order.Status == "" {
+		order.Status = models.OrderStatusPending
+	}
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = time.Now()
+	}
+	order.UpdatedAt = order.CreatedAt
+
 	itemsJSON, err := json.Marshal(order.Items)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	shippingJSON, err := json.Marshal(order.ShippingAddress)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	billingJSON, err := json.Marshal(order.BillingAddress)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	query := `
@@ -147,13 +178,13 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, req *models.Create
 			id, user_id, status, items, shipping_address, billing_address,
 			subtotal_amount, subtotal_currency, tax_amount, tax_currency,
 			shipping_amount, shipping_currency, total_amount, total_currency,
-			notes, created_at, updated_at
+			notes, created_at, updated_at, version
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
 		)
 	`
 
-	_, err = r.db.ExecContext(ctx, query,
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
 		order.ID,
 		order.UserID,
 		order.Status,
@@ -171,14 +202,15 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, req *models.Create
 		order.Notes,
 		order.CreatedAt,
 		order.UpdatedAt,
+		InitialVersion,
 	)
 
 	if err != nil {
 		r.logger.Error("Failed to create order", logging.Fields{
-			"user_id": req.UserID,
+			"user_id": order.UserID,
 			"error":   err.Error(),
 		})
-		return nil, err
+		return err
 	}
 
 	r.logger.Info("Order created successfully", logging.Fields{
@@ -187,14 +219,24 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, req *models.Create
 		"total":    order.Total.Amount,
 	})
 
-	return order, nil
+	return nil
 }
 
 // UpdateStatus updates the status of an order.
 func (r *PostgresOrderRepository) UpdateStatus(ctx context.Context, id string, req *models.UpdateOrderStatusRequest) (*models.Order, error) {
+	versioned, err := r.UpdateStatusAt(ctx, id, AnyVersion, req)
+	if err != nil {
+		return nil, err
+	}
+	return versioned.Order, nil
+}
+
+// UpdateStatusAt updates the status of an order that is still at version.
+func (r *PostgresOrderRepository) UpdateStatusAt(ctx context.Context, id string, version int64, req *models.UpdateOrderStatusRequest) (*VersionedOrder, error) {
 	r.logger.Debug("Updating order status", logging.Fields{
 		"order_id":   id,
 		"new_status": req.Status,
+		"version":    version,
 	})
 
 	now := time.Now()
@@ -210,15 +252,16 @@ func (r *PostgresOrderRepository) UpdateStatus(ctx context.Context, id string, r
 		UPDATE orders
 		SET status = $2, notes = COALESCE($3, notes), updated_at = $4,
 		    shipped_at = COALESCE($5, shipped_at),
-		    delivered_at = COALESCE($6, delivered_at)
-		WHERE id = $1 AND deleted_at IS NULL
+		    delivered_at = COALESCE($6, delivered_at),
+		    version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($7 = 0 OR version = $7)
 		RETURNING id
 	`
 
 	var returnedID string
-	err := r.db.QueryRowContext(ctx, query, id, req.Status, req.Notes, now, shippedAt, deliveredAt).Scan(&returnedID)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, req.Status, req.Notes, now, shippedAt, deliveredAt, version).Scan(&returnedID)
 	if err == sql.ErrNoRows {
-		return nil, errors.ErrNotFound
+		return nil, r.missedWrite(ctx, id, version)
 	}
 	if err != nil {
 		r.logger.Error("Failed to update order status", logging.Fields{
@@ -233,9 +276,17 @@ func (r *PostgresOrderRepository) UpdateStatus(ctx context.Context, id string, r
 		"new_status": req.Status,
 	})
 
-	return r.GetByID(ctx, id)
+	return r.GetVersioned(ctx, id)
 }
 
+// orderListColumns are the columns read by scanOrder.
+const orderListColumns = `
+		SELECT id, user_id, status, items, shipping_address, billing_address,
+		       subtotal_amount, subtotal_currency, tax_amount, tax_currency,
+		       shipping_amount, shipping_currency, total_amount, total_currency,
+		       payment_id, notes, created_at, updated_at, shipped_at, delivered_at
+		FROM orders`
+
 // List retrieves orders based on filter criteria.
 func (r *PostgresOrderRepository) List(ctx context.Context, filter *models.OrderListFilter) ([]*models.Order, int, error) {
 	r.logger.Debug("Listing orders", logging.Fields{
@@ -245,64 +296,114 @@ func (r *PostgresOrderRepository) List(ctx context.Context, filter *models.Order
 		"offset":  filter.Offset,
 	})
 
-	// TODO(TEAM-API): Add proper query builder
-	baseQuery := `
-		FROM orders
-		WHERE deleted_at IS NULL
-	`
-	args := make([]interface{}, 0)
-	argIdx := 1
-
-	if filter.UserID != "" {
-		baseQuery += " AND user_id = $" + string(rune('0'+argIdx))
-		args = append(args, filter.UserID)
-		argIdx++
+	q := &OrderQuery{
+		UserID:      filter.UserID,
+		CreatedFrom: filter.StartDate,
+		CreatedTo:   filter.EndDate,
 	}
-
 	if filter.Status != nil {
-		baseQuery += " AND status = $" + string(rune('0'+argIdx))
-		args = append(args, *filter.Status)
-		argIdx++
+		q.Statuses = []models.OrderStatus{*filter.Status}
 	}
 
+	b := newQueryBuilder()
+	b.filterOrders(q)
+
 	// Get total count
 	var total int
-	countQuery := "SELECT COUNT(*) " + baseQuery
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	countQuery := "SELECT COUNT(*) FROM orders" + b.whereClause()
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, b.args...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 
 	// Get orders
-	selectQuery := `
-		SELECT id, user_id, status, items, shipping_address, billing_address,
-		       subtotal_amount, subtotal_currency, tax_amount, tax_currency,
-		       shipping_amount, shipping_currency, total_amount, total_currency,
-		       payment_id, notes, created_at, updated_at, shipped_at, delivered_at
-	` + baseQuery + " ORDER BY created_at DESC LIMIT $" + string(rune('0'+argIdx)) + " OFFSET $" + string(rune('0'+argIdx+1))
-
-	args = append(args, filter.Limit, filter.Offset)
+	selectQuery := orderListColumns + b.whereClause() + orderByClause(orderSorts[DefaultOrderSort]) +
+		" LIMIT " + b.arg(filter.Limit) + " OFFSET " + b.arg(filter.Offset)
 
-	rows, err := r.db.QueryContext(ctx, selectQuery, args...)
+	orders, err := r.queryOrders(ctx, selectQuery, b.args)
 	if err != nil {
 		return nil, 0, err
 	}
+
+	r.logger.Info("Orders listed", logging.Fields{
+		"count": len(orders),
+		"total": total,
+	})
+
+	return orders, total, nil
+}
+
+// ListPage retrieves one page of orders matching q using keyset pagination.
+func (r *PostgresOrderRepository) ListPage(ctx context.Context, q *OrderQuery) (*OrderPage, error) {
+	sort := q.Sort
+	if sort == "" {
+		sort = DefaultOrderSort
+	}
+	spec, ok := orderSorts[sort]
+	if !ok {
+		return nil, errors.NewValidationError("sort", "unsupported sort order")
+	}
+
+	b := newQueryBuilder()
+	b.filterOrders(q)
+
+	// The total ignores the cursor so it stays the same on every page.
+	var total int
+	countQuery := "SELECT COUNT(*) FROM orders" + b.whereClause()
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, b.args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	if q.Cursor != "" {
+		key, id, err := decodeCursor(q.Cursor, sort)
+		if err != nil {
+			return nil, err
+		}
+		b.after(spec, key, id)
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	query := orderListColumns + b.whereClause() + orderByClause(spec) + " LIMIT " + b.arg(q.Limit+1)
+	if q.Offset > 0 {
+		query += " OFFSET " + b.arg(q.Offset)
+	}
+
+	orders, err := r.queryOrders(ctx, query, b.args)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &OrderPage{Orders: orders, Total: total}
+	if len(orders) > q.Limit {
+		page.Orders = orders[:q.Limit]
+		page.NextCursor = encodeCursor(sort, page.Orders[q.Limit-1])
+	}
+
+	r.logger.Debug("Order page listed", logging.Fields{
+		"count":    len(page.Orders),
+		"sort":     sort,
+		"has_more": page.NextCursor != "",
+	})
+
+	return page, nil
+}
+
+func (r *PostgresOrderRepository) queryOrders(ctx context.Context, query string, args []interface{}) ([]*models.Order, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	orders := make([]*models.Order, 0)
 	for rows.Next() {
 		order, err := r.scanOrder(rows)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		orders = append(orders, order)
 	}
 
-	r.logger.Info("Orders listed", logging.Fields{
-		"count": len(orders),
-		"total": total,
-	})
-
-	return orders, total, nil
+	return orders, rows.Err()
 }
 
 // GetByUserID retrieves all orders for a specific user.
@@ -321,15 +422,20 @@ func (r *PostgresOrderRepository) GetByUserID(ctx context.Context, userID string
 
 // Delete soft-deletes an order.
 func (r *PostgresOrderRepository) Delete(ctx context.Context, id string) error {
-	r.logger.Debug("Deleting order", logging.Fields{"order_id": id})
+	return r.DeleteAt(ctx, id, AnyVersion)
+}
+
+// DeleteAt soft-deletes an order that is still at version.
+func (r *PostgresOrderRepository) DeleteAt(ctx context.Context, id string, version int64) error {
+	r.logger.Debug("Deleting order", logging.Fields{"order_id": id, "version": version})
 
 	query := `
 		UPDATE orders
-		SET deleted_at = $2, status = $3, updated_at = $2
-		WHERE id = $1 AND deleted_at IS NULL
+		SET deleted_at = $2, status = $3, updated_at = $2, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($4 = 0 OR version = $4)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, id, time.Now(), models.OrderStatusCancelled)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, time.Now(), models.OrderStatusCancelled, version)
 	if err != nil {
 		r.logger.Error("Failed to delete order", logging.Fields{
 			"order_id": id,
@@ -340,7 +446,7 @@ func (r *PostgresOrderRepository) Delete(ctx context.Context, id string) error {
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.ErrNotFound
+		return r.missedWrite(ctx, id, version)
 	}
 
 	r.logger.Info("Order deleted", logging.Fields{"order_id": id})
@@ -349,25 +455,31 @@ func (r *PostgresOrderRepository) Delete(ctx context.Context, id string) error {
 
 // SetPaymentID associates a payment with an order.
 func (r *PostgresOrderRepository) SetPaymentID(ctx context.Context, orderID, paymentID string) error {
+	return r.SetPaymentIDAt(ctx, orderID, paymentID, AnyVersion)
+}
+
+// SetPaymentIDAt associates a payment with an order that is still at version.
+func (r *PostgresOrderRepository) SetPaymentIDAt(ctx context.Context, orderID, paymentID string, version int64) error {
 	r.logger.Debug("Setting payment ID", logging.Fields{
 		"order_id":   orderID,
 		"payment_id": paymentID,
+		"version":    version,
 	})
 
 	query := `
 		UPDATE orders
-		SET payment_id = $2, updated_at = $3
-		WHERE id = $1 AND deleted_at IS NULL
+		SET payment_id = $2, updated_at = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($4 = 0 OR version = $4)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, orderID, paymentID, time.Now())
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, orderID, paymentID, time.Now(), version)
 	if err != nil {
 		return err
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.ErrNotFound
+		return r.missedWrite(ctx, orderID, version)
 	}
 
 	r.logger.Info("Payment ID set", logging.Fields{
@@ -378,6 +490,70 @@ func (r *PostgresOrderRepository) SetPaymentID(ctx context.Context, orderID, pay
 	return nil
 }
 
+// SetPaymentStatus records the status of the order's payment.
+func (r *PostgresOrderRepository) SetPaymentStatus(ctx context.Context, orderID string, status models.PaymentStatus) error {
+	query := `
+		UPDATE orders
+		SET payment_status = $2, updated_at = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, orderID, status, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	r.logger.Info("Payment status set", logging.Fields{
+		"order_id": orderID,
+		"status":   status,
+	})
+
+	return nil
+}
+
+// TouchAt increments the version of an order still at version.
+func (r *PostgresOrderRepository) TouchAt(ctx context.Context, id string, version int64) error {
+	query := `
+		UPDATE orders
+		SET updated_at = $2, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($3 = 0 OR version = $3)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, time.Now(), version)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return r.missedWrite(ctx, id, version)
+	}
+	return nil
+}
+
+// missedWrite explains a conditional write that matched no rows: either the
+// order does not exist or it has moved past version.
+func (r *PostgresOrderRepository) missedWrite(ctx context.Context, id string, version int64) error {
+	if version == AnyVersion {
+		return errors.ErrNotFound
+	}
+
+	if _, err := r.GetVersioned(ctx, id); err != nil {
+		return err
+	}
+
+	r.logger.Info("Order version conflict", logging.Fields{
+		"order_id": id,
+		"version":  version,
+	})
+	return ErrVersionConflict
+}
+
 func (r *PostgresOrderRepository) scanOrder(rows *sql.Rows) (*models.Order, error) {
 	var order models.Order
 	var itemsJSON, shippingJSON, billingJSON []byte
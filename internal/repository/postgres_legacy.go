@@ -38,7 +38,7 @@ func (r *PostgresOrderRepositoryV1) GetOrderByID(ctx context.Context, id int64)
 	`
 
 	var order LegacyOrder
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := instrument(r.db).QueryRowContext(ctx, query, id).Scan(
 		&order.ID,
 		&order.UserID,
 		&order.Status,
@@ -79,7 +79,7 @@ func (r *PostgresOrderRepositoryV1) CreateOrder(ctx context.Context, req *Legacy
 	now := time.Now().Format(time.RFC3339)
 	var id int64
 
-	err = r.db.QueryRowContext(ctx, query,
+	err = instrument(r.db).QueryRowContext(ctx, query,
 		req.UserID,
 		"pending",
 		itemsJSON,
@@ -117,7 +117,7 @@ func (r *PostgresOrderRepositoryV1) UpdateOrderStatus(ctx context.Context, id in
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query, id, status, time.Now().Format(time.RFC3339))
+	result, err := instrument(r.db).ExecContext(ctx, query, id, status, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return err
 	}
@@ -144,7 +144,7 @@ func (r *PostgresOrderRepositoryV1) GetOrdersByUserID(ctx context.Context, userI
 		LIMIT 100
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := instrument(r.db).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// ProcessedEventStore records which external events have been applied.
+type ProcessedEventStore interface {
+	// MarkProcessed records eventID for consumer and reports whether this is
+	// the first time it has been seen. Call it with a transactional context so
+	// the record commits together with the change the event caused.
+	MarkProcessed(ctx context.Context, consumer, eventID string) (bool, error)
+	// DeleteProcessedBefore removes up to limit records processed before the
+	// given time and returns how many were removed.
+	DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// PostgresProcessedEventStore implements ProcessedEventStore using PostgreSQL.
+type PostgresProcessedEventStore struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresProcessedEventStore creates a new PostgreSQL processed event store.
+func NewPostgresProcessedEventStore(db *sql.DB, logger *logging.LoggerV2) *PostgresProcessedEventStore {
+	return &PostgresProcessedEventStore{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// MarkProcessed inserts the event, ignoring it if it is already recorded.
+func (s *PostgresProcessedEventStore) MarkProcessed(ctx context.Context, consumer, eventID string) (bool, error) {
+	query := `
+		INSERT INTO processed_events (event_id, consumer, processed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, consumer) DO NOTHING
+	`
+
+	result, err := conn(ctx, s.db).ExecContext(ctx, query, eventID, consumer, time.Now())
+	if err != nil {
+		s.logger.Error("Failed to record processed event", logging.Fields{
+			"event_id": eventID,
+			"consumer": consumer,
+			"error":    err.Error(),
+		})
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// DeleteProcessedBefore deletes the oldest records in bounded batches so
+// pruning never holds locks on a large part of the table.
+func (s *PostgresProcessedEventStore) DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM processed_events
+		WHERE (event_id, consumer) IN (
+			SELECT event_id, consumer FROM processed_events
+			WHERE processed_at < $1
+			ORDER BY processed_at
+			LIMIT $2
+		)
+	`
+
+	result, err := conn(ctx, s.db).ExecContext(ctx, query, before, limit)
+	if err != nil {
+		s.logger.Error("Failed to prune processed events", logging.Fields{
+			"before": before,
+			"error":  err.Error(),
+		})
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// RefundItem is a quantity of one order line included in a refund, with
+// its prorated share of the line total and tax.
+type RefundItem struct {
+	ItemID    string       `json:"item_id"`
+	ProductID string       `json:"product_id"`
+	Quantity  int          `json:"quantity"`
+	Amount    models.Money `json:"amount"`
+	Tax       models.Money `json:"tax"`
+}
+
+// OrderRefund is one refund against an order's captured payment. Status is
+// pending while the payment service processes it, then refunded or failed.
+type OrderRefund struct {
+	ID        string `json:"id"`
+	OrderID   string `json:"order_id"`
+	PaymentID string `json:"payment_id"`
+	// RefundID is the payment service's reference, set once it accepts the
+	// refund.
+	RefundID  string               `json:"refund_id,omitempty"`
+	Status    models.PaymentStatus `json:"status"`
+	Reason    string               `json:"reason"`
+	Items     []RefundItem         `json:"items"`
+	Subtotal  models.Money         `json:"subtotal"`
+	Tax       models.Money         `json:"tax"`
+	Shipping  models.Money         `json:"shipping"`
+	Amount    models.Money         `json:"amount"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// RefundRepository stores order refunds.
+type RefundRepository interface {
+	// Create stores a new refund, filling in its ID and timestamps.
+	Create(ctx context.Context, refund *OrderRefund) error
+	// ListByOrderID returns an order's refunds, oldest first.
+	ListByOrderID(ctx context.Context, orderID string) ([]*OrderRefund, error)
+	// GetByRefundID returns the refund with the payment service's refund
+	// ID, or nil if there is none.
+	GetByRefundID(ctx context.Context, refundID string) (*OrderRefund, error)
+	// UpdateStatus stores the status and refund ID of a pending refund,
+	// returning ErrVersionConflict if it is no longer pending.
+	UpdateStatus(ctx context.Context, refund *OrderRefund) error
+}
+
+// PostgresRefundRepository implements RefundRepository using PostgreSQL.
+type PostgresRefundRepository struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresRefundRepository creates a new PostgreSQL refund repository.
+func NewPostgresRefundRepository(db *sql.DB, logger *logging.LoggerV2) *PostgresRefundRepository {
+	return &PostgresRefundRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new refund.
+func (r *PostgresRefundRepository) Create(ctx context.Context, refund *OrderRefund) error {
+	if refund.ID == "" {
+		refund.ID = generateRefundID()
+	}
+	now := time.Now()
+	refund.CreatedAt = now
+	refund.UpdatedAt = now
+
+	itemsJSON, err := json.Marshal(refund.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO order_refunds (
+			id, order_id, payment_id, refund_id, status, reason, items,
+			subtotal_amount, tax_amount, shipping_amount, amount, currency,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		refund.ID,
+		refund.OrderID,
+		refund.PaymentID,
+		refund.RefundID,
+		refund.Status,
+		refund.Reason,
+		itemsJSON,
+		refund.Subtotal.Amount,
+		refund.Tax.Amount,
+		refund.Shipping.Amount,
+		refund.Amount.Amount,
+		refund.Amount.Currency,
+		refund.CreatedAt,
+		refund.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create refund", logging.Fields{
+			"order_id": refund.OrderID,
+			"error":    err.Error(),
+		})
+		return err
+	}
+
+	r.logger.Info("Refund created", logging.Fields{
+		"order_id":  refund.OrderID,
+		"refund_id": refund.ID,
+		"amount":    refund.Amount.Amount,
+	})
+	return nil
+}
+
+// refundColumns are the order_refunds columns read by scanRefund.
+const refundColumns = `
+	id, order_id, payment_id, refund_id, status, reason, items,
+	subtotal_amount, tax_amount, shipping_amount, amount, currency,
+	created_at, updated_at`
+
+// ListByOrderID returns an order's refunds, oldest first.
+func (r *PostgresRefundRepository) ListByOrderID(ctx context.Context, orderID string) ([]*OrderRefund, error) {
+	query := `
+		SELECT ` + refundColumns + `
+		FROM order_refunds
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := make([]*OrderRefund, 0)
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, rows.Err()
+}
+
+// GetByRefundID returns the refund with the payment service's refund ID.
+func (r *PostgresRefundRepository) GetByRefundID(ctx context.Context, refundID string) (*OrderRefund, error) {
+	query := `
+		SELECT ` + refundColumns + `
+		FROM order_refunds
+		WHERE refund_id = $1
+	`
+
+	refund, err := scanRefund(conn(ctx, r.db).QueryRowContext(ctx, query, refundID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return refund, err
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRefund reads a row selected with refundColumns.
+func scanRefund(row rowScanner) (*OrderRefund, error) {
+	var refund OrderRefund
+	var itemsJSON []byte
+	var currency string
+
+	err := row.Scan(
+		&refund.ID,
+		&refund.OrderID,
+		&refund.PaymentID,
+		&refund.RefundID,
+		&refund.Status,
+		&refund.Reason,
+		&itemsJSON,
+		&refund.Subtotal.Amount,
+		&refund.Tax.Amount,
+		&refund.Shipping.Amount,
+		&refund.Amount.Amount,
+		&currency,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(itemsJSON, &refund.Items); err != nil {
+		return nil, err
+	}
+	refund.Subtotal.Currency = currency
+	refund.Tax.Currency = currency
+	refund.Shipping.Currency = currency
+	refund.Amount.Currency = currency
+	return &refund, nil
+}
+
+// UpdateStatus stores the outcome of a pending refund.
+func (r *PostgresRefundRepository) UpdateStatus(ctx context.Context, refund *OrderRefund) error {
+	refund.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE order_refunds
+		SET status = $3, refund_id = $4, updated_at = $5
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		refund.ID,
+		models.PaymentStatusPending,
+		refund.Status,
+		refund.RefundID,
+		refund.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update refund status", logging.Fields{
+			"refund_id": refund.ID,
+			"error":     err.Error(),
+		})
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	r.logger.Info("Refund status updated", logging.Fields{
+		"refund_id": refund.ID,
+		"status":    refund.Status,
+	})
+	return nil
+}
+
+func generateRefundID() string {
+	// TODO(TEAM-API): Use proper UUID or ULID generation
+	return "ref_" + time.Now().Format("20060102150405.000000")
+}
@@ -2,18 +2,76 @@ package repository
 
 import (
 	"context"
+	stderrors "errors"
 
 	"github.com/tm-acme-shop/acme-shop-shared-go/interfaces"
 	"github.com/tm-acme-shop/acme-shop-shared-go/models"
 )
 
-// Ensure PostgresOrderRepository implements interfaces.OrderRepository
-var _ interfaces.OrderRepository = (*PostgresOrderRepository)(nil)
+// Ensure PostgresOrderRepository implements OrderStore
+var _ OrderStore = (*PostgresOrderRepository)(nil)
+
+const (
+	// InitialVersion is the version of a newly inserted order.
+	InitialVersion int64 = 1
+	// AnyVersion disables the version check of a conditional write.
+	AnyVersion int64 = 0
+)
+
+// ErrVersionConflict is returned by conditional writes when the order was
+// modified after the caller read it.
+var ErrVersionConflict = stderrors.New("order was modified concurrently")
+
+// VersionedOrder is an order together with its optimistic concurrency
+// version. Every write to an order increments the version.
+type VersionedOrder struct {
+	*models.Order
+	Version int64 `json:"version"`
+	// PaymentStatus is the last payment status recorded for the order;
+	// empty until a payment outcome is known.
+	PaymentStatus models.PaymentStatus `json:"payment_status,omitempty"`
+}
+
+// OrderStore extends interfaces.OrderRepository with the operations this
+// service needs beyond the shared contract.
+type OrderStore interface {
+	interfaces.OrderRepository
+
+	// Insert stores an order that has already been priced by the caller.
+	Insert(ctx context.Context, order *models.Order) error
+
+	// ListPage retrieves one page of orders matching q using keyset
+	// pagination.
+	ListPage(ctx context.Context, q *OrderQuery) (*OrderPage, error)
+
+	// GetVersioned retrieves an order and its current version.
+	GetVersioned(ctx context.Context, id string) (*VersionedOrder, error)
+
+	// UpdateStatusAt, SetPaymentIDAt and DeleteAt apply only while the order
+	// is still at version and return ErrVersionConflict otherwise. AnyVersion
+	// skips the check.
+	UpdateStatusAt(ctx context.Context, id string, version int64, req *models.UpdateOrderStatusRequest) (*VersionedOrder, error)
+	SetPaymentIDAt(ctx context.Context, orderID, paymentID string, version int64) error
+	DeleteAt(ctx context.Context, id string, version int64) error
+
+	// SetPaymentStatus records the status the payment service reported for
+	// the order's payment.
+	SetPaymentStatus(ctx context.Context, orderID string, status models.PaymentStatus) error
+
+	// TouchAt increments the version of an order still at version without
+	// changing it. Writes to an order's child records (such as shipments)
+	// call it to serialise against other writes to the same order.
+	TouchAt(ctx context.Context, id string, version int64) error
+}
 
 // OrderCache defines caching operations for orders.
 type OrderCache interface {
 	Get(ctx context.Context, id string) (*models.Order, error)
 	Set(ctx context.Context, order *models.Order) error
+	// GetVersioned and SetVersioned cache an order with its version.
+	// Every versioned write deletes the entry once it commits.
+	GetVersioned(ctx context.Context, id string) (*VersionedOrder, error)
+	SetVersioned(ctx context.Context, order *VersionedOrder) error
 	Delete(ctx context.Context, id string) error
 	GetByUserID(ctx context.Context, userID string) ([]*models.Order, error)
 	SetByUserID(ctx context.Context, userID string, orders []*models.Order) error
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// ReturnStatus is the stage of a return in the RMA workflow.
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusReceived  ReturnStatus = "received"
+	ReturnStatusInspected ReturnStatus = "inspected"
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+)
+
+// Closed reports whether the return has finished, either way.
+func (s ReturnStatus) Closed() bool {
+	return s == ReturnStatusRefunded || s == ReturnStatusRejected
+}
+
+// ReturnItem is a quantity of one order line the customer is sending back.
+// ReceivedQuantity is filled in when the parcel arrives and
+// AcceptedQuantity after inspection; only accepted units are refunded.
+type ReturnItem struct {
+	ItemID           string `json:"item_id"`
+	ProductID        string `json:"product_id"`
+	Quantity         int    `json:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity"`
+	AcceptedQuantity int    `json:"accepted_quantity"`
+}
+
+// Return is a return merchandise authorisation for part of an order.
+type Return struct {
+	ID      string       `json:"id"`
+	OrderID string       `json:"order_id"`
+	UserID  string       `json:"user_id"`
+	Status  ReturnStatus `json:"status"`
+	Reason  string       `json:"reason"`
+	// Notes holds inspection remarks or the reason for a rejection.
+	Notes string       `json:"notes,omitempty"`
+	Items []ReturnItem `json:"items"`
+	// RefundID is the OrderRefund that paid out the accepted items.
+	RefundID    string     `json:"refund_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ApprovedAt  *time.Time `json:"approved_at,omitempty"`
+	ReceivedAt  *time.Time `json:"received_at,omitempty"`
+	InspectedAt *time.Time `json:"inspected_at,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+}
+
+// ReturnRepository stores returns.
+type ReturnRepository interface {
+	// Create stores a new return, filling in its ID and timestamps.
+	Create(ctx context.Context, ret *Return) error
+	// GetByID returns a return of orderID, or errors.ErrNotFound.
+	GetByID(ctx context.Context, orderID, id string) (*Return, error)
+	// ListByOrderID returns an order's returns, oldest first.
+	ListByOrderID(ctx context.Context, orderID string) ([]*Return, error)
+	// Update stores the mutable fields of ret if it is still in from,
+	// returning ErrVersionConflict otherwise.
+	Update(ctx context.Context, ret *Return, from ReturnStatus) error
+}
+
+// PostgresReturnRepository implements ReturnRepository using PostgreSQL.
+type PostgresReturnRepository struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresReturnRepository creates a new PostgreSQL return repository.
+func NewPostgresReturnRepository(db *sql.DB, logger *logging.LoggerV2) *PostgresReturnRepository {
+	return &PostgresReturnRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new return.
+func (r *PostgresReturnRepository) Create(ctx context.Context, ret *Return) error {
+	if ret.ID == "" {
+		ret.ID = generateReturnID()
+	}
+	now := time.Now()
+	ret.CreatedAt = now
+	ret.UpdatedAt = now
+
+	itemsJSON, err := json.Marshal(ret.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO returns (
+			id, order_id, user_id, status, reason, notes, items, refund_id,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		ret.ID,
+		ret.OrderID,
+		ret.UserID,
+		ret.Status,
+		ret.Reason,
+		ret.Notes,
+		itemsJSON,
+		ret.RefundID,
+		ret.CreatedAt,
+		ret.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create return", logging.Fields{
+			"order_id": ret.OrderID,
+			"error":    err.Error(),
+		})
+		return err
+	}
+
+	r.logger.Info("Return created", logging.Fields{
+		"order_id":  ret.OrderID,
+		"return_id": ret.ID,
+	})
+	return nil
+}
+
+const returnColumns = `
+		SELECT id, order_id, user_id, status, reason, notes, items, refund_id,
+		       created_at, updated_at, approved_at, received_at, inspected_at, closed_at
+		FROM returns`
+
+// GetByID returns a return of orderID.
+func (r *PostgresReturnRepository) GetByID(ctx context.Context, orderID, id string) (*Return, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, returnColumns+" WHERE order_id = $1 AND id = $2", orderID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrNotFound
+	}
+	return scanReturn(rows)
+}
+
+// ListByOrderID returns an order's returns, oldest first.
+func (r *PostgresReturnRepository) ListByOrderID(ctx context.Context, orderID string) ([]*Return, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, returnColumns+" WHERE order_id = $1 ORDER BY created_at, id", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returns := make([]*Return, 0)
+	for rows.Next() {
+		ret, err := scanReturn(rows)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+	return returns, rows.Err()
+}
+
+// Update stores ret's status, items, notes, refund and timestamps if it is
+// still in from.
+func (r *PostgresReturnRepository) Update(ctx context.Context, ret *Return, from ReturnStatus) error {
+	ret.UpdatedAt = time.Now()
+
+	itemsJSON, err := json.Marshal(ret.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE returns
+		SET status = $3, notes = $4, items = $5, refund_id = $6, updated_at = $7,
+		    approved_at = $8, received_at = $9, inspected_at = $10, closed_at = $11
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		ret.ID,
+		from,
+		ret.Status,
+		ret.Notes,
+		itemsJSON,
+		ret.RefundID,
+		ret.UpdatedAt,
+		ret.ApprovedAt,
+		ret.ReceivedAt,
+		ret.InspectedAt,
+		ret.ClosedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update return", logging.Fields{
+			"return_id": ret.ID,
+			"error":     err.Error(),
+		})
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	r.logger.Info("Return updated", logging.Fields{
+		"return_id": ret.ID,
+		"from":      from,
+		"to":        ret.Status,
+	})
+	return nil
+}
+
+func scanReturn(rows *sql.Rows) (*Return, error) {
+	var ret Return
+	var itemsJSON []byte
+	var approvedAt, receivedAt, inspectedAt, closedAt sql.NullTime
+
+	err := rows.Scan(
+		&ret.ID,
+		&ret.OrderID,
+		&ret.UserID,
+		&ret.Status,
+		&ret.Reason,
+		&ret.Notes,
+		&itemsJSON,
+		&ret.RefundID,
+		&ret.CreatedAt,
+		&ret.UpdatedAt,
+		&approvedAt,
+		&receivedAt,
+		&inspectedAt,
+		&closedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(itemsJSON, &ret.Items); err != nil {
+		return nil, err
+	}
+	ret.ApprovedAt = nullTime(approvedAt)
+	ret.ReceivedAt = nullTime(receivedAt)
+	ret.InspectedAt = nullTime(inspectedAt)
+	ret.ClosedAt = nullTime(closedAt)
+
+	return &ret, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func generateReturnID() string {
+	// TODO(TEAM-API): Use proper UUID or ULID generation
+	return "rma_" + time.Now().Format("20060102150405.000000")
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// ShipmentStatus is the delivery state of a shipment.
+type ShipmentStatus string
+
+const (
+	ShipmentStatusPending   ShipmentStatus = "pending"
+	ShipmentStatusShipped   ShipmentStatus = "shipped"
+	ShipmentStatusDelivered ShipmentStatus = "delivered"
+	ShipmentStatusCancelled ShipmentStatus = "cancelled"
+)
+
+// ShipmentItem is a quantity of one order line included in a shipment.
+type ShipmentItem struct {
+	ItemID    string `json:"item_id"`
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// Shipment is a parcel fulfilling part or all of an order.
+type Shipment struct {
+	ID             string         `json:"id"`
+	OrderID        string         `json:"order_id"`
+	Warehouse      string         `json:"warehouse"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number,omitempty"`
+	Status         ShipmentStatus `json:"status"`
+	Items          []ShipmentItem `json:"items"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	ShippedAt      *time.Time     `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+}
+
+// ShipmentRepository stores order shipments.
+type ShipmentRepository interface {
+	// Create stores a new shipment, filling in its ID and timestamps.
+	Create(ctx context.Context, shipment *Shipment) error
+	// GetByID returns a shipment of orderID, or errors.ErrNotFound.
+	GetByID(ctx context.Context, orderID, id string) (*Shipment, error)
+	// ListByOrderID returns an order's shipments, oldest first.
+	ListByOrderID(ctx context.Context, orderID string) ([]*Shipment, error)
+	// UpdateStatus moves a shipment from one status to another, returning
+	// ErrVersionConflict if it is no longer in from.
+	UpdateStatus(ctx context.Context, shipment *Shipment, from ShipmentStatus) error
+}
+
+// PostgresShipmentRepository implements ShipmentRepository using PostgreSQL.
+type PostgresShipmentRepository struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresShipmentRepository creates a new PostgreSQL shipment repository.
+func NewPostgresShipmentRepository(db *sql.DB, logger *logging.LoggerV2) *PostgresShipmentRepository {
+	return &PostgresShipmentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new shipment.
+func (r *PostgresShipmentRepository) Create(ctx context.Context, shipment *Shipment) error {
+	if shipment.ID == "" {
+		shipment.ID = generateShipmentID()
+	}
+	now := time.Now()
+	shipment.CreatedAt = now
+	shipment.UpdatedAt = now
+	if shipment.Status == ShipmentStatusShipped && shipment.ShippedAt == nil {
+		shipment.ShippedAt = &now
+	}
+
+	itemsJSON, err := json.Marshal(shipment.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO shipments (
+			id, order_id, warehouse, carrier, tracking_number, status, items,
+			created_at, updated_at, shipped_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		shipment.ID,
+		shipment.OrderID,
+		shipment.Warehouse,
+		shipment.Carrier,
+		shipment.TrackingNumber,
+		shipment.Status,
+		itemsJSON,
+		shipment.CreatedAt,
+		shipment.UpdatedAt,
+		shipment.ShippedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create shipment", logging.Fields{
+			"order_id": shipment.OrderID,
+			"error":    err.Error(),
+		})
+		return err
+	}
+
+	r.logger.Info("Shipment created", logging.Fields{
+		"order_id":    shipment.OrderID,
+		"shipment_id": shipment.ID,
+		"status":      shipment.Status,
+	})
+	return nil
+}
+
+const shipmentColumns = `
+		SELECT id, order_id, warehouse, carrier, tracking_number, status, items,
+		       created_at, updated_at, shipped_at, delivered_at
+		FROM shipments`
+
+// GetByID returns a shipment of orderID.
+func (r *PostgresShipmentRepository) GetByID(ctx context.Context, orderID, id string) (*Shipment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, shipmentColumns+" WHERE order_id = $1 AND id = $2", orderID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrNotFound
+	}
+	return scanShipment(rows)
+}
+
+// ListByOrderID returns an order's shipments, oldest first.
+func (r *PostgresShipmentRepository) ListByOrderID(ctx context.Context, orderID string) ([]*Shipment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, shipmentColumns+" WHERE order_id = $1 ORDER BY created_at, id", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shipments := make([]*Shipment, 0)
+	for rows.Next() {
+		shipment, err := scanShipment(rows)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, shipment)
+	}
+	return shipments, rows.Err()
+}
+
+// UpdateStatus stores shipment's status, tracking number and timestamps if
+// it is still in from.
+func (r *PostgresShipmentRepository) UpdateStatus(ctx context.Context, shipment *Shipment, from ShipmentStatus) error {
+	now := time.Now()
+	switch shipment.Status {
+	case ShipmentStatusShipped:
+		shipment.ShippedAt = &now
+	case ShipmentStatusDelivered:
+		shipment.DeliveredAt = &now
+	}
+	shipment.UpdatedAt = now
+
+	query := `
+		UPDATE shipments
+		SET status = $3, tracking_number = $4, updated_at = $5,
+		    shipped_at = COALESCE(shipped_at, $6),
+		    delivered_at = COALESCE(delivered_at, $7)
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		shipment.ID,
+		from,
+		shipment.Status,
+		shipment.TrackingNumber,
+		now,
+		shipment.ShippedAt,
+		shipment.DeliveredAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update shipment status", logging.Fields{
+			"shipment_id": shipment.ID,
+			"error":       err.Error(),
+		})
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	r.logger.Info("Shipment status updated", logging.Fields{
+		"shipment_id": shipment.ID,
+		"from":        from,
+		"to":          shipment.Status,
+	})
+	return nil
+}
+
+func scanShipment(rows *sql.Rows) (*Shipment, error) {
+	var shipment Shipment
+	var itemsJSON []byte
+	var shippedAt, deliveredAt sql.NullTime
+
+	err := rows.Scan(
+		&shipment.ID,
+		&shipment.OrderID,
+		&shipment.Warehouse,
+		&shipment.Carrier,
+		&shipment.TrackingNumber,
+		&shipment.Status,
+		&itemsJSON,
+		&shipment.CreatedAt,
+		&shipment.UpdatedAt,
+		&shippedAt,
+		&deliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(itemsJSON, &shipment.Items); err != nil {
+		return nil, err
+	}
+	if shippedAt.Valid {
+		shipment.ShippedAt = &shippedAt.Time
+	}
+	if deliveredAt.Valid {
+		shipment.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &shipment, nil
+}
+
+func generateShipmentID() string {
+	// TODO(TEAM-API): Use proper UUID or ULID generation
+	return "shp_" + time.Now().Format("20060102150405.000000")
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// StatusHistoryEntry records one order status transition.
+type StatusHistoryEntry struct {
+	ID        int64              `json:"id"`
+	OrderID   string             `json:"order_id"`
+	From      models.OrderStatus `json:"from_status,omitempty"`
+	To        models.OrderStatus `json:"to_status"`
+	Actor     string             `json:"actor"`
+	Reason    string             `json:"reason,omitempty"`
+	Source    string             `json:"source"`
+	RequestID string             `json:"request_id,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// StatusHistoryRepository stores the order status audit trail.
+type StatusHistoryRepository interface {
+	// Record appends an entry. Call it with the transactional context of
+	// the status change so the two commit together.
+	Record(ctx context.Context, entry *StatusHistoryEntry) error
+	// ListByOrderID returns an order's history, oldest first.
+	ListByOrderID(ctx context.Context, orderID string) ([]*StatusHistoryEntry, error)
+}
+
+// PostgresStatusHistoryRepository implements StatusHistoryRepository using PostgreSQL.
+type PostgresStatusHistoryRepository struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresStatusHistoryRepository creates a new PostgreSQL status history repository.
+func NewPostgresStatusHistoryRepository(db *sql.DB, logger *logging.LoggerV2) *PostgresStatusHistoryRepository {
+	return &PostgresStatusHistoryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Record inserts a history entry.
+func (r *PostgresStatusHistoryRepository) Record(ctx context.Context, entry *StatusHistoryEntry) error {
+	query := `
+		INSERT INTO order_status_history (
+			order_id, from_status, to_status, actor, reason, source, request_id, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var from sql.NullString
+	if entry.From != "" {
+		from = sql.NullString{String: string(entry.From), Valid: true}
+	}
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		entry.OrderID,
+		from,
+		entry.To,
+		entry.Actor,
+		entry.Reason,
+		entry.Source,
+		entry.RequestID,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		r.logger.Error("Failed to record status history", logging.Fields{
+			"order_id": entry.OrderID,
+			"error":    err.Error(),
+		})
+		return err
+	}
+
+	return nil
+}
+
+// ListByOrderID returns an order's history, oldest first.
+func (r *PostgresStatusHistoryRepository) ListByOrderID(ctx context.Context, orderID string) ([]*StatusHistoryEntry, error) {
+	query := `
+		SELECT id, order_id, from_status, to_status, actor, reason, source, request_id, created_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*StatusHistoryEntry, 0)
+	for rows.Next() {
+		var entry StatusHistoryEntry
+		var from sql.NullString
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.OrderID,
+			&from,
+			&entry.To,
+			&entry.Actor,
+			&entry.Reason,
+			&entry.Source,
+			&entry.RequestID,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if from.Valid {
+			entry.From = models.OrderStatus(from.String)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// OrderTaxLine is one stored row of an order's tax breakdown.
+type OrderTaxLine struct {
+	ID           int64        `json:"-"`
+	OrderID      string       `json:"order_id"`
+	Jurisdiction string       `json:"jurisdiction"`
+	Name         string       `json:"name"`
+	Category     string       `json:"category"`
+	RatePPM      int64        `json:"rate_ppm"`
+	Inclusive    bool         `json:"inclusive"`
+	Taxable      models.Money `json:"taxable_amount"`
+	Tax          models.Money `json:"tax_amount"`
+	RulesVersion string       `json:"rules_version"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// TaxLineRepository stores the tax breakdown applied to orders.
+type TaxLineRepository interface {
+	// Insert stores lines for an order. Call it with the transactional
+	// context that creates the order.
+	Insert(ctx context.Context, lines []*OrderTaxLine) error
+	ListByOrderID(ctx context.Context, orderID string) ([]*OrderTaxLine, error)
+}
+
+// PostgresTaxLineRepository implements TaxLineRepository using PostgreSQL.
+type PostgresTaxLineRepository struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewPostgresTaxLineRepository creates a new PostgreSQL tax line repository.
+func NewPostgresTaxLineRepository(db *sql.DB, logger *logging.LoggerV2) *PostgresTaxLineRepository {
+	return &PostgresTaxLineRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Insert stores tax lines.
+func (r *PostgresTaxLineRepository) Insert(ctx context.Context, lines []*OrderTaxLine) error {
+	query := `
+		INSERT INTO order_tax_lines (
+			order_id, jurisdiction, name, category, rate_ppm, inclusive,
+			taxable_amount, tax_amount, currency, rules_version, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	now := time.Now()
+	for _, line := range lines {
+		err := conn(ctx, r.db).QueryRowContext(ctx, query,
+			line.OrderID,
+			line.Jurisdiction,
+			line.Name,
+			line.Category,
+			line.RatePPM,
+			line.Inclusive,
+			line.Taxable.Amount,
+			line.Tax.Amount,
+			line.Tax.Currency,
+			line.RulesVersion,
+			now,
+		).Scan(&line.ID)
+		if err != nil {
+			r.logger.Error("Failed to insert tax line", logging.Fields{
+				"order_id": line.OrderID,
+				"error":    err.Error(),
+			})
+			return err
+		}
+		line.CreatedAt = now
+	}
+
+	return nil
+}
+
+// ListByOrderID returns the tax lines of an order in insertion order.
+func (r *PostgresTaxLineRepository) ListByOrderID(ctx context.Context, orderID string) ([]*OrderTaxLine, error) {
+	query := `
+		SELECT id, order_id, jurisdiction, name, category, rate_ppm, inclusive,
+		       taxable_amount, tax_amount, currency, rules_version, created_at
+		FROM order_tax_lines
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]*OrderTaxLine, 0)
+	for rows.Next() {
+		var line OrderTaxLine
+		var currency string
+		if err := rows.Scan(
+			&line.ID,
+			&line.OrderID,
+			&line.Jurisdiction,
+			&line.Name,
+			&line.Category,
+			&line.RatePPM,
+			&line.Inclusive,
+			&line.Taxable.Amount,
+			&line.Tax.Amount,
+			&currency,
+			&line.RulesVersion,
+			&line.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		line.Taxable.Currency = currency
+		line.Tax.Currency = currency
+		lines = append(lines, &line)
+	}
+
+	return lines, rows.Err()
+}
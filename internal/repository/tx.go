@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+type txKey struct{}
+
+// txState is the transaction carried by a context, with the functions to
+// run once it commits.
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func(ctx context.Context)
+}
+
+// querier is the subset of *sql.DB and *sql.Tx used by the repositories.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Transactor runs a function inside a database transaction. Repository calls
+// made with the context passed to fn join that transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// TxManager implements Transactor using PostgreSQL.
+type TxManager struct {
+	db     *sql.DB
+	logger *logging.LoggerV2
+}
+
+// NewTxManager creates a new transaction manager.
+func NewTxManager(db *sql.DB, logger *logging.LoggerV2) *TxManager {
+	return &TxManager{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithinTx begins a transaction, runs fn and commits if fn succeeds.
+// Nested calls reuse the outer transaction. Functions queued with
+// AfterCommit run, with ctx, once the outermost transaction commits.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			m.logger.Error("Failed to roll back transaction", logging.Fields{"error": rbErr.Error()})
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, fn := range state.afterCommit {
+		fn(ctx)
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction carried by ctx commits, or
+// straight away when ctx carries none. Side effects that must not be seen
+// before the data they describe, such as cache invalidation and customer
+// notifications, and slow calls to other services go here rather than
+// inside the transaction. Queued functions are dropped on rollback.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// conn returns the transaction carried by ctx, or db if there is none.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return instrument(state.tx)
+	}
+	return instrument(db)
+}
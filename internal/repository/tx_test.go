@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// txLog is a database/sql driver that only records transaction outcomes.
+type txLog struct {
+	events *[]string
+}
+
+func (d txLog) Open(name string) (driver.Conn, error) { return txLogConn(d), nil }
+
+type txLogConn txLog
+
+func (c txLogConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c txLogConn) Close() error              { return nil }
+func (c txLogConn) Begin() (driver.Tx, error) { return txLogTx(c), nil }
+
+type txLogTx txLogConn
+
+func (t txLogTx) Commit() error {
+	*t.events = append(*t.events, "commit")
+	return nil
+}
+
+func (t txLogTx) Rollback() error {
+	*t.events = append(*t.events, "rollback")
+	return nil
+}
+
+type connector struct{ d txLog }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c connector) Driver() driver.Driver                        { return c.d }
+
+func TestAfterCommit(t *testing.T) {
+	var events []string
+	db := sql.OpenDB(connector{txLog{&events}})
+	defer db.Close()
+	m := NewTxManager(db, logging.NewLoggerV2("test"))
+	ctx := context.Background()
+
+	err := m.WithinTx(ctx, func(ctx context.Context) error {
+		return m.WithinTx(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func(ctx context.Context) {
+				if _, ok := ctx.Value(txKey{}).(*txState); ok {
+					t.Error("expected after-commit functions to run outside the transaction")
+				}
+				events = append(events, "hook")
+			})
+			events = append(events, "inner done")
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithinTx: %v", err)
+	}
+	assertEvents(t, events, "inner done", "commit", "hook")
+
+	events = nil
+	err = m.WithinTx(ctx, func(ctx context.Context) error {
+		AfterCommit(ctx, func(ctx context.Context) { events = append(events, "hook") })
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected the error to be returned")
+	}
+	assertEvents(t, events, "rollback")
+
+	events = nil
+	AfterCommit(ctx, func(ctx context.Context) { events = append(events, "hook") })
+	assertEvents(t, events, "hook")
+}
+
+func assertEvents(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
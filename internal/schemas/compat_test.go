@@ -0,0 +1,59 @@
+package schemas
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The compatibility suite keeps the schemas from breaking the events
+// already out there. testdata/<event type>/v<N>/ holds sample payloads
+// written with version N of a schema. Every sample must validate against
+// its own version and every later one, so a new schema version can always
+// read what older producers wrote. Add samples whenever a schema changes.
+
+func TestEveryEventTypeHasSamples(t *testing.T) {
+	for _, eventType := range Default().EventTypes() {
+		for _, entry := range Default().Versions(eventType) {
+			samples, _ := filepath.Glob(filepath.Join("testdata", eventType, versionDir(entry.Version), "*.json"))
+			if len(samples) == 0 {
+				t.Errorf("%s has no sample payloads in testdata", entry.ID)
+			}
+		}
+	}
+}
+
+func TestSamplesValidateAgainstCurrentAndLaterVersions(t *testing.T) {
+	for _, eventType := range Default().EventTypes() {
+		versions := Default().Versions(eventType)
+		for i, written := range versions {
+			samples, _ := filepath.Glob(filepath.Join("testdata", eventType, versionDir(written.Version), "*.json"))
+			for _, sample := range samples {
+				data, err := os.ReadFile(sample)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, reader := range versions[i:] {
+					if err := Default().Validate(reader.ID, data); err != nil {
+						t.Errorf("%s written with v%d: %v", sample, written.Version, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestVersionsAreContiguous(t *testing.T) {
+	for _, eventType := range Default().EventTypes() {
+		for i, entry := range Default().Versions(eventType) {
+			if entry.Version != i+1 {
+				t.Errorf("%s: expected version %d, got %d", eventType, i+1, entry.Version)
+			}
+		}
+	}
+}
+
+func versionDir(version int) string {
+	return fmt.Sprintf("v%d", version)
+}
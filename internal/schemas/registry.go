@@ -0,0 +1,149 @@
+package schemas
+
+import (
+	"embed"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+//go:embed events/*.json
+var eventSchemas embed.FS
+
+// ErrUnknownSchema is returned when no schema is registered for an ID or
+// event type.
+var ErrUnknownSchema = stderrors.New("unknown schema")
+
+// fileName matches schema files named <event type>.v<version>.json.
+var fileName = regexp.MustCompile(`^([a-z_.]+)\.v([1-9][0-9]*)\.json$`)
+
+// Registry holds every version of each event type's schema. A schema is
+// identified by its $id, which events carry as their CloudEvents
+// dataschema, so a consumer validates each payload against the version it
+// was written with.
+type Registry struct {
+	byID   map[string]*Entry
+	byType map[string][]*Entry
+}
+
+// Entry is one version of an event type's schema.
+type Entry struct {
+	ID        string
+	EventType string
+	Version   int
+	Schema    *Schema
+}
+
+var defaultRegistry = mustLoadDefault()
+
+func mustLoadDefault() *Registry {
+	registry, err := Load(eventSchemas, "events")
+	if err != nil {
+		panic("schemas: " + err.Error())
+	}
+	return registry
+}
+
+// Default returns the registry of the schemas checked in with the service.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// SchemaID returns the $id of an event type's schema version.
+func SchemaID(eventType string, version int) string {
+	return fmt.Sprintf("urn:acme-shop:schema:%s:v%d", eventType, version)
+}
+
+// Load compiles the schema files in dir. Each must be named
+// <event type>.v<version>.json and declare the matching SchemaID as $id.
+func Load(fsys fs.FS, dir string) (*Registry, error) {
+	files, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Registry{
+		byID:   make(map[string]*Entry),
+		byType: make(map[string][]*Entry),
+	}
+	for _, file := range files {
+		match := fileName.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%s: expected a name of the form <event type>.v<version>.json", file.Name())
+		}
+		version, _ := strconv.Atoi(match[2])
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var header struct {
+			ID string `json:"$id"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		if want := SchemaID(match[1], version); header.ID != want {
+			return nil, fmt.Errorf("%s: expected $id %q, got %q", file.Name(), want, header.ID)
+		}
+
+		schema, err := Compile(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+
+		entry := &Entry{ID: header.ID, EventType: match[1], Version: version, Schema: schema}
+		r.byID[entry.ID] = entry
+		r.byType[entry.EventType] = append(r.byType[entry.EventType], entry)
+	}
+
+	for _, entries := range r.byType {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Version < entries[j].Version })
+	}
+	return r, nil
+}
+
+// Latest returns the newest schema of an event type, the one new events of
+// that type are written with.
+func (r *Registry) Latest(eventType string) (*Entry, error) {
+	entries := r.byType[eventType]
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w for event type %q", ErrUnknownSchema, eventType)
+	}
+	return entries[len(entries)-1], nil
+}
+
+// Versions returns every schema of an event type, oldest first.
+func (r *Registry) Versions(eventType string) []*Entry {
+	return r.byType[eventType]
+}
+
+// EventTypes returns the event types with a registered schema.
+func (r *Registry) EventTypes() []string {
+	types := make([]string, 0, len(r.byType))
+	for eventType := range r.byType {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Validate checks data against the schema with the given $id.
+func (r *Registry) Validate(id string, data []byte) error {
+	entry, ok := r.byID[id]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownSchema, id)
+	}
+
+	err := entry.Schema.Validate(data)
+	var invalid *ValidationError
+	if stderrors.As(err, &invalid) {
+		invalid.Schema = id
+	}
+	return err
+}
@@ -0,0 +1,358 @@
+// Package schemas holds the JSON Schemas of the events the orders service
+// publishes and consumes, and validates event payloads against them.
+package schemas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schema is a compiled JSON Schema. Only the subset of draft 2020-12 used
+// by the event schemas is supported; Compile rejects any other keyword so
+// a schema never silently validates less than it appears to.
+type Schema struct {
+	types                []string
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	items                *Schema
+	enum                 []interface{}
+	minLength            *int
+	minimum              *float64
+	format               string
+	ref                  string
+
+	// defs is shared by a schema and all its subschemas to resolve $ref.
+	defs map[string]*Schema
+}
+
+// annotations are keywords that carry no validation.
+var annotations = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+	"title":       true,
+	"description": true,
+	"examples":    true,
+	"default":     true,
+}
+
+// ValidationError lists every way a document fails its schema.
+type ValidationError struct {
+	Schema string
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("payload does not match schema %s: %s", e.Schema, strings.Join(e.Errors, "; "))
+}
+
+// Compile parses a JSON Schema document.
+func Compile(data []byte) (*Schema, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	defs := make(map[string]*Schema)
+	root, err := compile(raw, defs, "#")
+	if err != nil {
+		return nil, err
+	}
+
+	if rawDefs, ok := raw["$defs"].(map[string]interface{}); ok {
+		for name, rawDef := range rawDefs {
+			def, ok := rawDef.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("#/$defs/%s: must be an object", name)
+			}
+			compiled, err := compile(def, defs, "#/$defs/"+name)
+			if err != nil {
+				return nil, err
+			}
+			defs["#/$defs/"+name] = compiled
+		}
+	}
+
+	// Every reference must resolve now rather than at validation time.
+	for _, s := range root.walk(nil) {
+		if s.ref != "" && defs[s.ref] == nil {
+			return nil, fmt.Errorf("unresolved $ref %q", s.ref)
+		}
+	}
+	for _, def := range defs {
+		for _, s := range def.walk(nil) {
+			if s.ref != "" && defs[s.ref] == nil {
+				return nil, fmt.Errorf("unresolved $ref %q", s.ref)
+			}
+		}
+	}
+	return root, nil
+}
+
+func compile(raw map[string]interface{}, defs map[string]*Schema, path string) (*Schema, error) {
+	s := &Schema{defs: defs}
+
+	for keyword, value := range raw {
+		var err error
+		switch keyword {
+		case "$defs":
+			// Compiled by Compile; nested $defs are not supported.
+			if path != "#" {
+				err = fmt.Errorf("nested $defs are not supported")
+			}
+		case "type":
+			s.types, err = stringList(value)
+		case "properties":
+			props, ok := value.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("must be an object")
+				break
+			}
+			s.properties = make(map[string]*Schema, len(props))
+			for name, rawProp := range props {
+				prop, ok := rawProp.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s/properties/%s: must be an object", path, name)
+				}
+				if s.properties[name], err = compile(prop, defs, path+"/properties/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.required, err = stringList(value)
+		case "additionalProperties":
+			switch v := value.(type) {
+			case bool:
+				s.noAdditional = !v
+			case map[string]interface{}:
+				s.additionalProperties, err = compile(v, defs, path+"/additionalProperties")
+			default:
+				err = fmt.Errorf("must be a boolean or an object")
+			}
+		case "items":
+			items, ok := value.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("must be an object")
+				break
+			}
+			s.items, err = compile(items, defs, path+"/items")
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok {
+				err = fmt.Errorf("must be an array")
+				break
+			}
+			s.enum = values
+		case "const":
+			s.enum = []interface{}{value}
+		case "minLength":
+			n, ok := value.(float64)
+			if !ok {
+				err = fmt.Errorf("must be a number")
+				break
+			}
+			length := int(n)
+			s.minLength = &length
+		case "minimum":
+			n, ok := value.(float64)
+			if !ok {
+				err = fmt.Errorf("must be a number")
+				break
+			}
+			s.minimum = &n
+		case "format":
+			s.format, _ = value.(string)
+			if s.format != "date-time" {
+				err = fmt.Errorf("unsupported format %v", value)
+			}
+		case "$ref":
+			s.ref, _ = value.(string)
+			if !strings.HasPrefix(s.ref, "#/$defs/") {
+				err = fmt.Errorf("only local #/$defs references are supported")
+			}
+		default:
+			if !annotations[keyword] {
+				err = fmt.Errorf("unsupported keyword")
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", path, keyword, err)
+		}
+	}
+	return s, nil
+}
+
+// walk returns s and its subschemas, not following references.
+func (s *Schema) walk(out []*Schema) []*Schema {
+	out = append(out, s)
+	for _, prop := range s.properties {
+		out = prop.walk(out)
+	}
+	if s.additionalProperties != nil {
+		out = s.additionalProperties.walk(out)
+	}
+	if s.items != nil {
+		out = s.items.walk(out)
+	}
+	return out
+}
+
+// Validate checks a JSON document against the schema and returns a
+// *ValidationError listing every violation.
+func (s *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return &ValidationError{Errors: []string{"invalid JSON: " + err.Error()}}
+	}
+
+	var errs []string
+	s.validate(doc, "", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (s *Schema) validate(value interface{}, path string, errs *[]string) {
+	fail := func(format string, args ...interface{}) {
+		location := path
+		if location == "" {
+			location = "/"
+		}
+		*errs = append(*errs, location+": "+fmt.Sprintf(format, args...))
+	}
+
+	if s.ref != "" {
+		s.defs[s.ref].validate(value, path, errs)
+	}
+
+	if len(s.types) > 0 && !matchesType(value, s.types) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), typeOf(value))
+		return
+	}
+
+	if len(s.enum) > 0 && !inEnum(value, s.enum) {
+		fail("%v is not one of %v", value, s.enum)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := s.properties[name]; ok {
+				prop.validate(v[name], path+"/"+name, errs)
+			} else if s.noAdditional {
+				fail("unexpected property %q", name)
+			} else if s.additionalProperties != nil {
+				s.additionalProperties.validate(v[name], path+"/"+name, errs)
+			}
+		}
+	case []interface{}:
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(item, fmt.Sprintf("%s/%d", path, i), errs)
+			}
+		}
+	case string:
+		if s.minLength != nil && len([]rune(v)) < *s.minLength {
+			fail("shorter than %d characters", *s.minLength)
+		}
+		if s.format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				fail("%q is not an RFC 3339 date-time", v)
+			}
+		}
+	case json.Number:
+		if s.minimum != nil {
+			if n, err := v.Float64(); err == nil && n < *s.minimum {
+				fail("%s is less than %v", v, *s.minimum)
+			}
+		}
+	}
+}
+
+func matchesType(value interface{}, types []string) bool {
+	actual := typeOf(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if n, err := v.Float64(); err == nil && n == math.Trunc(n) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return false
+		}
+		value = f
+	}
+	for _, allowed := range enum {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+func stringList(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("must contain only strings")
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("must be a string or an array of strings")
+	}
+}
@@ -0,0 +1,81 @@
+package schemas
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+  "$id": "urn:test",
+  "type": "object",
+  "required": ["id", "total"],
+  "properties": {
+    "id": {"type": "string", "minLength": 1},
+    "status": {"enum": ["pending", "shipped"]},
+    "total": {"$ref": "#/$defs/money"},
+    "items": {"type": ["array", "null"], "items": {"$ref": "#/$defs/money"}},
+    "at": {"type": "string", "format": "date-time"}
+  },
+  "$defs": {
+    "money": {
+      "type": "object",
+      "required": ["amount"],
+      "additionalProperties": false,
+      "properties": {"amount": {"type": "integer", "minimum": 0}, "currency": {"type": "string"}}
+    }
+  }
+}`
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := Compile([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"valid", `{"id":"ord_1","total":{"amount":100,"currency":"USD"},"items":null,"at":"2024-05-01T12:00:00Z"}`, ""},
+		{"missing required", `{"id":"ord_1"}`, `/: missing required property "total"`},
+		{"wrong type", `{"id":1,"total":{"amount":1}}`, "/id: expected string, got integer"},
+		{"not an integer", `{"id":"a","total":{"amount":1.5}}`, "/total/amount: expected integer, got number"},
+		{"below minimum", `{"id":"a","total":{"amount":-1}}`, "/total/amount: -1 is less than 0"},
+		{"enum", `{"id":"a","total":{"amount":1},"status":"lost"}`, "/status: lost is not one of"},
+		{"additional property", `{"id":"a","total":{"amount":1,"cents":true}}`, `/total: unexpected property "cents"`},
+		{"array items", `{"id":"a","total":{"amount":1},"items":[{"amount":"1"}]}`, "/items/0/amount: expected integer"},
+		{"date-time", `{"id":"a","total":{"amount":1},"at":"yesterday"}`, "/at: \"yesterday\" is not an RFC 3339 date-time"},
+		{"empty string", `{"id":"","total":{"amount":1}}`, "/id: shorter than 1 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.doc))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCompileRejectsUnsupportedKeywords(t *testing.T) {
+	for _, schema := range []string{
+		`{"type": "object", "oneOf": []}`,
+		`{"properties": {"id": {"pattern": "^ord_"}}}`,
+		`{"$ref": "other.json#/order"}`,
+		`{"$ref": "#/$defs/missing"}`,
+	} {
+		if _, err := Compile([]byte(schema)); err == nil {
+			t.Errorf("expected %s to be rejected", schema)
+		}
+	}
+}
@@ -7,10 +7,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/auth"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/handlers"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/ratelimit"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/service"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/tracing"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 	"github.com/tm-acme-shop/acme-shop-shared-go/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // Server represents the HTTP server.
@@ -50,12 +55,11 @@ func New(h *handlers.Handlers, cfg *config.Config) *Server {
 
 func (s *Server) setupMiddleware() {
 	s.router.Use(gin.Recovery())
+	s.router.Use(otelgin.Middleware(tracing.ServiceName))
 	s.router.Use(middleware.RequestIDMiddleware())
 	s.router.Use(middleware.LoggingMiddleware())
 	s.router.Use(middleware.CORSMiddleware())
-
-	// TODO(TEAM-PLATFORM): Add rate limiting middleware
-	// TODO(TEAM-SEC): Add authentication middleware for protected routes
+	s.router.Use(s.handlers.Instrument())
 }
 
 func (s *Server) setupRoutes() {
@@ -64,74 +68,117 @@ func (s *Server) setupRoutes() {
 	s.router.GET("/ready", s.handlers.Ready)
 	s.router.GET("/live", s.handlers.Live)
 	s.router.GET("/version", s.handlers.Version)
+	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	s.router.GET("/metrics/prometheus", gin.WrapH(promhttp.Handler()))
-	s.router.GET("/metrics", s.handlers.Metrics)
+	// Deprecated JSON stats formerly served at /metrics
+	// TODO(TEAM-PLATFORM): Remove once dashboards scrape /metrics
+	s.router.GET("/metrics/legacy", s.handlers.MetricsLegacy)
 
 	// Debug endpoints (disable in production)
-	// TODO(TEAM-SEC): Add authentication for debug endpoints
-	s.router.GET("/debug", s.handlers.Debug)
+	s.router.GET("/debug", s.handlers.Authenticate(), s.handlers.RequireRole(auth.RoleAdmin), s.handlers.Debug)
 
 	// V1 API routes (deprecated)
 	// TODO(TEAM-API): Remove after v1 API migration complete
 	if s.config.Features.EnableV1API {
-		v1 := s.router.Group("/api/v1")
-		s.setupV1Routes(v1)
+		s.setupV1Routes(s.router.Group("/api/v1"))
 	}
 
-	// V2 API routes
-	v2 := s.router.Group("/api/v2")
+	// V2 API routes. Callers are authenticated before Audit so changes are
+	// attributed to the verified caller, and before rate limiting so quotas
+	// apply per caller rather than per IP.
+	v2 := s.router.Group("/api/v2", s.handlers.Authenticate(), s.rateLimit(), s.handlers.Audit(service.ChangeSourceAPI))
 	s.setupV2Routes(v2)
 
 	// Webhook routes
-	webhooks := s.router.Group("/api/webhooks")
+	webhooks := s.router.Group("/api/webhooks", s.handlers.Audit(service.ChangeSourceWebhook))
 	s.setupWebhookRoutes(webhooks)
 }
 
+// rateLimit returns the read and write quotas applied to API route groups.
+func (s *Server) rateLimit() gin.HandlerFunc {
+	return s.handlers.RateLimitReadWrite(
+		ratelimit.NewPolicy("read", s.config.RateLimit.Read),
+		ratelimit.NewPolicy("write", s.config.RateLimit.Write),
+	)
+}
+
 func (s *Server) setupV1Routes(rg *gin.RouterGroup) {
 	// TODO(TEAM-API): Remove all v1 routes after migration complete
 	logging.Infof("Setting up deprecated v1 API routes")
 
+	// The v1 API is authenticated and authorised like v2. Handlers reached
+	// by customers check that the user or order is theirs.
+	api := rg.Group("", s.handlers.Authenticate(), s.rateLimit(), s.handlers.Audit(service.ChangeSourceAPI))
+	staff := s.handlers.RequireRole(auth.RoleSupport, auth.RoleAdmin, auth.RoleService)
+	admin := s.handlers.RequireRole(auth.RoleAdmin, auth.RoleService)
+	ownOrder := s.handlers.RequireOrderAccess()
+
 	// Order routes (legacy)
-	orders := rg.Group("/orders")
+	orders := api.Group("/orders")
 	{
 		orders.POST("", s.handlers.CreateOrderV1)
 		orders.GET("", s.handlers.ListOrdersV1)
 		orders.GET("/:id", s.handlers.GetOrderV1)
-		orders.POST("/:id/status", s.handlers.UpdateOrderStatusV1)
-		orders.POST("/:id/pay", s.handlers.ProcessOrderPaymentV1)
+		orders.POST("/:id/status", admin, s.handlers.UpdateOrderStatusV1)
+		orders.POST("/:id/pay", ownOrder, s.handlers.ProcessOrderPaymentV1)
 	}
 
 	// User order routes (legacy)
-	users := rg.Group("/users")
+	users := api.Group("/users")
 	{
 		users.GET("/:user_id/orders", s.handlers.GetUserOrdersV1)
 	}
 
 	// Payment routes (legacy)
-	payments := rg.Group("/payments")
+	payments := api.Group("/payments", staff)
 	{
 		payments.GET("/status", s.handlers.GetPaymentStatusV1)
 		payments.POST("/:id/refund", s.handlers.ProcessRefundV1)
 	}
 
-	// Legacy webhook endpoint
-	rg.POST("/webhooks/payment", s.handlers.PaymentWebhookV1)
+	// Legacy webhook endpoint, verified by its signature instead
+	rg.POST("/webhooks/payment", s.handlers.Audit(service.ChangeSourceWebhook), s.handlers.PaymentWebhookV1)
 }
 
 func (s *Server) setupV2Routes(rg *gin.RouterGroup) {
 	s.logger.Info("Setting up v2 API routes")
 
+	// Customers reach only their own orders: GetOrder, ListOrders and
+	// GetUserOrders check ownership themselves, other order routes through
+	// ownOrder. Operational changes are reserved for staff and services.
+	staff := s.handlers.RequireRole(auth.RoleSupport, auth.RoleAdmin, auth.RoleService)
+	admin := s.handlers.RequireRole(auth.RoleAdmin, auth.RoleService)
+	ownOrder := s.handlers.RequireOrderAccess()
+	// Order creation is charged to its own, stricter quota on top of the
+	// write quota.
+	createOrderLimit := s.handlers.RateLimit(ratelimit.NewPolicy("create_order", s.config.RateLimit.CreateOrder))
+
 	// Order routes
 	orders := rg.Group("/orders")
 	{
-		orders.POST("", s.handlers.CreateOrder)
+		orders.POST("", createOrderLimit, s.handlers.Idempotency(), s.handlers.CreateOrder)
 		orders.GET("", s.handlers.ListOrders)
+		orders.GET("/lifecycle", s.handlers.GetOrderLifecycle)
 		orders.GET("/:id", s.handlers.GetOrder)
-		orders.PATCH("/:id/status", s.handlers.UpdateOrderStatus)
-		orders.POST("/:id/cancel", s.handlers.CancelOrder)
-		orders.POST("/:id/payment", s.handlers.ProcessOrderPayment)
-		orders.GET("/:id/payment", s.handlers.GetOrderPayment)
-		orders.POST("/:id/refund", s.handlers.RefundOrder)
+		orders.GET("/:id/tax", ownOrder, s.handlers.GetOrderTaxLines)
+		orders.GET("/:id/history", ownOrder, s.handlers.GetOrderHistory)
+		orders.POST("/:id/shipments", staff, s.handlers.Idempotency(), s.handlers.CreateShipment)
+		orders.GET("/:id/shipments", ownOrder, s.handlers.ListShipments)
+		orders.PATCH("/:id/shipments/:shipment_id", staff, s.handlers.UpdateShipmentStatus)
+		orders.PATCH("/:id/status", admin, s.handlers.UpdateOrderStatus)
+		orders.POST("/:id/cancel", ownOrder, s.handlers.CancelOrder)
+		orders.POST("/:id/payment", ownOrder, s.handlers.Idempotency(), s.handlers.ProcessOrderPayment)
+		orders.GET("/:id/payment", ownOrder, s.handlers.GetOrderPayment)
+		orders.POST("/:id/refund", staff, s.handlers.Idempotency(), s.handlers.RefundOrder)
+		orders.GET("/:id/refunds", ownOrder, s.handlers.ListRefunds)
+		orders.POST("/:id/returns", ownOrder, s.handlers.Idempotency(), s.handlers.CreateReturn)
+		orders.GET("/:id/returns", ownOrder, s.handlers.ListReturns)
+		orders.GET("/:id/returns/:return_id", ownOrder, s.handlers.GetReturn)
+		orders.POST("/:id/returns/:return_id/approve", staff, s.handlers.ApproveReturn)
+		orders.POST("/:id/returns/:return_id/receive", staff, s.handlers.ReceiveReturn)
+		orders.POST("/:id/returns/:return_id/inspect", staff, s.handlers.InspectReturn)
+		orders.POST("/:id/returns/:return_id/refund", staff, s.handlers.Idempotency(), s.handlers.RefundReturn)
+		orders.POST("/:id/returns/:return_id/reject", staff, s.handlers.RejectReturn)
 	}
 
 	// User order routes
@@ -141,11 +188,11 @@ func (s *Server) setupV2Routes(rg *gin.RouterGroup) {
 	}
 
 	// Payment routes
-	payments := rg.Group("/payments")
+	payments := rg.Group("/payments", staff)
 	{
 		payments.GET("/:id", s.handlers.GetPaymentStatus)
 		payments.POST("/:id/cancel", s.handlers.CancelPayment)
-		payments.POST("/:id/refund", s.handlers.ProcessRefund)
+		payments.POST("/:id/refund", s.handlers.Idempotency(), s.handlers.ProcessRefund)
 	}
 }
 
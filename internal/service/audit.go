@@ -0,0 +1,41 @@
+package service
+
+import "context"
+
+// ChangeSource identifies the channel an order change came through.
+type ChangeSource string
+
+const (
+	ChangeSourceAPI     ChangeSource = "api"
+	ChangeSourceKafka   ChangeSource = "kafka_consumer"
+	ChangeSourceWebhook ChangeSource = "webhook"
+	ChangeSourceSystem  ChangeSource = "system"
+)
+
+// AuditInfo describes who made an order change and how. It is recorded in
+// the order status history.
+type AuditInfo struct {
+	Actor     string
+	Source    ChangeSource
+	RequestID string
+}
+
+type auditKey struct{}
+
+// WithAudit returns a context carrying info for the changes made with it.
+func WithAudit(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditKey{}, info)
+}
+
+// AuditFromContext returns the audit info carried by ctx. Changes without
+// one are attributed to the system.
+func AuditFromContext(ctx context.Context) AuditInfo {
+	info, _ := ctx.Value(auditKey{}).(AuditInfo)
+	if info.Source == "" {
+		info.Source = ChangeSourceSystem
+	}
+	if info.Actor == "" {
+		info.Actor = string(info.Source)
+	}
+	return info
+}
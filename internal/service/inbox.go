@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+)
+
+// ApplyOnce applies an external event at most once per consumer. The event
+// is recorded in the processed events inbox in the same transaction as the
+// changes apply makes, so either both commit or neither does and a failed
+// event can be retried. It reports whether apply ran; redelivered events
+// are acknowledged without effect.
+func (s *OrderService) ApplyOnce(ctx context.Context, consumer, eventID string, apply func(ctx context.Context) error) (bool, error) {
+	if eventID == "" {
+		return false, errors.NewValidationError("event_id", "is required")
+	}
+
+	applied := false
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		first, err := s.processedEvents.MarkProcessed(ctx, consumer, eventID)
+		if err != nil {
+			return err
+		}
+		if !first {
+			s.logger.Info("Ignoring duplicate event", logging.Fields{
+				"consumer": consumer,
+				"event_id": eventID,
+			})
+			recordDuplicateEvent(consumer)
+			return nil
+		}
+
+		if err := apply(ctx); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// inlineTx runs fn without a database; the tests only need its ordering.
+type inlineTx struct{}
+
+func (inlineTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// rollbackTx discards the inbox entries written by fn when it fails, as
+// rolling back the database transaction would.
+type rollbackTx struct {
+	inbox *memoryInbox
+}
+
+func (t rollbackTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	saved := make(map[string]bool, len(t.inbox.seen))
+	for key, seen := range t.inbox.seen {
+		saved[key] = seen
+	}
+	if err := fn(ctx); err != nil {
+		t.inbox.seen = saved
+		return err
+	}
+	return nil
+}
+
+type memoryInbox struct {
+	seen map[string]bool
+}
+
+func (m *memoryInbox) MarkProcessed(ctx context.Context, consumer, eventID string) (bool, error) {
+	key := consumer + "/" + eventID
+	if m.seen[key] {
+		return false, nil
+	}
+	m.seen[key] = true
+	return true, nil
+}
+
+func (m *memoryInbox) DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func TestApplyOnce(t *testing.T) {
+	s := &OrderService{
+		tx:              inlineTx{},
+		processedEvents: &memoryInbox{seen: make(map[string]bool)},
+		logger:          logging.NewLoggerV2("test"),
+	}
+
+	calls := 0
+	apply := func(ctx context.Context) error {
+		calls++
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		applied, err := s.ApplyOnce(context.Background(), "payment-events", "evt_1", apply)
+		if err != nil {
+			t.Fatalf("ApplyOnce: %v", err)
+		}
+		if applied != (i == 0) {
+			t.Errorf("delivery %d: expected applied=%v, got %v", i+1, i == 0, applied)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected a redelivered event to be applied once, got %d calls", calls)
+	}
+
+	// The same event ID seen by another consumer is applied again.
+	if applied, _ := s.ApplyOnce(context.Background(), "payment-webhook", "evt_1", apply); !applied {
+		t.Error("expected the inbox to be scoped by consumer")
+	}
+
+	if _, err := s.ApplyOnce(context.Background(), "payment-events", "", apply); err == nil {
+		t.Error("expected an event without an ID to be rejected")
+	}
+
+	failure := stderrors.New("order is locked")
+	_, err := s.ApplyOnce(context.Background(), "payment-events", "evt_2", func(ctx context.Context) error {
+		return failure
+	})
+	if !stderrors.Is(err, failure) {
+		t.Errorf("expected the apply error to be returned, got %v", err)
+	}
+}
+
+func TestApplyPaymentWebhook_RedeliveryAfterFailure(t *testing.T) {
+	orders := newMemoryOrderStore()
+	inbox := &memoryInbox{seen: make(map[string]bool)}
+	s := newPaymentEventsTestService(orders, rollbackTx{inbox: inbox})
+	s.processedEvents = inbox
+
+	event := &PaymentWebhookEvent{
+		ID:   "evt_1",
+		Type: PaymentWebhookCompleted,
+		Data: PaymentWebhookData{PaymentID: "pay_1", OrderID: "ord_1", Status: models.PaymentStatusCompleted},
+	}
+
+	// The completion arrives before the order it belongs to is visible.
+	if err := s.ApplyPaymentWebhook(context.Background(), event); err == nil {
+		t.Fatal("Expected the first delivery to fail")
+	}
+	if len(inbox.seen) != 0 {
+		t.Fatalf("Expected the failed delivery to leave no inbox entry, got %v", inbox.seen)
+	}
+
+	orders.orders["ord_1"] = &repository.VersionedOrder{
+		Order:   &models.Order{ID: "ord_1", Status: models.OrderStatusPending},
+		Version: repository.InitialVersion,
+	}
+	if err := s.ApplyPaymentWebhook(context.Background(), event); err != nil {
+		t.Fatalf("Expected the redelivery to be applied, got %v", err)
+	}
+	if status := orders.orders["ord_1"].Status; status != models.OrderStatusConfirmed {
+		t.Errorf("Expected order to be confirmed by the redelivery, got %s", status)
+	}
+}
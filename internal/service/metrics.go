@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+var (
+	ordersCreatedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_created_total",
+		Help: "Orders created by currency.",
+	}, []string{"currency"})
+	ordersTransitionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_status_transitions_total",
+		Help: "Committed order status transitions by source and target status.",
+	}, []string{"from", "to"})
+	ordersCancelledTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orders_cancelled_total",
+		Help: "Orders cancelled.",
+	})
+	ordersRevenueTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_revenue_minor_units_total",
+		Help: "Total of confirmed orders in minor currency units, by currency.",
+	}, []string{"currency"})
+	ordersRefundedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_refunded_minor_units_total",
+		Help: "Amount refunded to customers in minor currency units, by currency.",
+	}, []string{"currency"})
+	duplicateEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_inbox_duplicate_events_total",
+		Help: "Redelivered external events skipped by the processed events inbox, by consumer.",
+	}, []string{"consumer"})
+)
+
+func recordOrderCreated(order *models.Order) {
+	ordersCreatedTotal.WithLabelValues(order.Total.Currency).Inc()
+}
+
+// recordTransition counts a committed status change. Revenue is booked when
+// payment confirms the order.
+func recordTransition(order *models.Order, from, to models.OrderStatus) {
+	ordersTransitionsTotal.WithLabelValues(string(from), string(to)).Inc()
+	switch to {
+	case models.OrderStatusCancelled:
+		ordersCancelledTotal.Inc()
+	case models.OrderStatusConfirmed:
+		ordersRevenueTotal.WithLabelValues(order.Total.Currency).Add(float64(order.Total.Amount))
+	}
+}
+
+func recordRefund(amount models.Money) {
+	ordersRefundedTotal.WithLabelValues(amount.Currency).Add(float64(amount.Amount))
+}
+
+func recordDuplicateEvent(consumer string) {
+	duplicateEventsTotal.WithLabelValues(consumer).Inc()
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 
 	"github.com/tm-acme-shop/acme-shop-orders-service/internal/clients"
@@ -15,7 +16,7 @@ import (
 
 // OrderService handles order business logic.
 type OrderService struct {
-	orderRepo           interfaces.OrderRepository
+	orderRepo           repository.OrderStore
 	orderCache          repository.OrderCache
 	legacyRepo          repository.OrderRepositoryV1
 	paymentClient       interfaces.PaymentClient
@@ -23,34 +24,73 @@ type OrderService struct {
 	userClient          *clients.HTTPUserClient
 	notificationClient  interfaces.NotificationSender
 	eventPublisher      interfaces.OrderEventPublisher
+	processedEvents     repository.ProcessedEventStore
+	taxLines            repository.TaxLineRepository
+	statusHistory       repository.StatusHistoryRepository
+	shipments           repository.ShipmentRepository
+	shipmentEvents      ShipmentEventPublisher
+	refunds             repository.RefundRepository
+	refundEvents        RefundEventPublisher
+	taxCalculator       TaxCalculator
+	lifecycle           *StateMachine
+	tx                  repository.Transactor
 	config              *config.Config
 	logger              *logging.LoggerV2
 }
 
+// EventPublisher publishes every event the order service emits.
+type EventPublisher interface {
+	interfaces.OrderEventPublisher
+	ShipmentEventPublisher
+	RefundEventPublisher
+}
+
+// OrderServiceDeps holds the dependencies of an OrderService.
+type OrderServiceDeps struct {
+	OrderRepo           repository.OrderStore
+	OrderCache          repository.OrderCache
+	LegacyRepo          repository.OrderRepositoryV1
+	PaymentClient       interfaces.PaymentClient
+	LegacyPaymentClient interfaces.LegacyPaymentClient
+	UserClient          *clients.HTTPUserClient
+	NotificationClient  interfaces.NotificationSender
+	Events              EventPublisher
+	ProcessedEvents     repository.ProcessedEventStore
+	TaxLines            repository.TaxLineRepository
+	StatusHistory       repository.StatusHistoryRepository
+	Shipments           repository.ShipmentRepository
+	Refunds             repository.RefundRepository
+	TaxCalculator       TaxCalculator
+	Lifecycle           *OrderLifecycle
+	Tx                  repository.Transactor
+	Config              *config.Config
+}
+
 // NewOrderService creates a new order service.
-func NewOrderService(
-	orderRepo interfaces.OrderRepository,
-	orderCache repository.OrderCache,
-	legacyRepo repository.OrderRepositoryV1,
-	paymentClient interfaces.PaymentClient,
-	legacyPaymentClient interfaces.LegacyPaymentClient,
-	userClient *clients.HTTPUserClient,
-	notificationClient interfaces.NotificationSender,
-	eventPublisher interfaces.OrderEventPublisher,
-	cfg *config.Config,
-) *OrderService {
-	return &OrderService{
-		orderRepo:           orderRepo,
-		orderCache:          orderCache,
-		legacyRepo:          legacyRepo,
-		paymentClient:       paymentClient,
-		legacyPaymentClient: legacyPaymentClient,
-		userClient:          userClient,
-		notificationClient:  notificationClient,
-		eventPublisher:      eventPublisher,
-		config:              cfg,
+func NewOrderService(deps OrderServiceDeps) *OrderService {
+	s := &OrderService{
+		orderRepo:           deps.OrderRepo,
+		orderCache:          deps.OrderCache,
+		legacyRepo:          deps.LegacyRepo,
+		paymentClient:       deps.PaymentClient,
+		legacyPaymentClient: deps.LegacyPaymentClient,
+		userClient:          deps.UserClient,
+		notificationClient:  deps.NotificationClient,
+		eventPublisher:      deps.Events,
+		processedEvents:     deps.ProcessedEvents,
+		taxLines:            deps.TaxLines,
+		statusHistory:       deps.StatusHistory,
+		shipments:           deps.Shipments,
+		shipmentEvents:      deps.Events,
+		refunds:             deps.Refunds,
+		refundEvents:        deps.Events,
+		taxCalculator:       deps.TaxCalculator,
+		tx:                  deps.Tx,
+		config:              deps.Config,
 		logger:              logging.NewLoggerV2("order-service"),
 	}
+	s.lifecycle = newStateMachine(deps.Lifecycle, s.transitionGuards(), s.transitionHooks())
+	return s
 }
 
 // CreateOrder creates a new order.
@@ -78,21 +118,66 @@ func (s *OrderService) CreateOrder(ctx context.Context, req *models.CreateOrderR
 		return nil, err
 	}
 
-	// Create order
-	order, err := s.orderRepo.Create(ctx, req)
+	// Price the order on the server; client totals are only cross-checked
+	pricing, err := PriceOrder(ctx, req, s.taxCalculator, s.config.Pricing)
 	if err != nil {
-		s.logger.Error("Failed to create order", logging.Fields{
+		return nil, err
+	}
+	if err := CheckClientTotals(req, pricing, s.config.Pricing.MismatchTolerance); err != nil {
+		s.logger.Info("Rejecting order with mismatched client totals", logging.Fields{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
 		return nil, err
 	}
 
-	// Use the frontend-calculated pricing values (subtotal, tax, total)
-	// to ensure consistency between what the customer saw at checkout and what is stored.
-	order.Subtotal = req.Subtotal
-	order.Tax = req.Tax
-	order.Total = req.Total
+	// Give every line a stable ID for shipments and refunds to reference
+	for i := range pricing.Items {
+		pricing.Items[i].ID = orderLineID(pricing.Items[i], i)
+	}
+
+	order := &models.Order{
+		UserID:          req.UserID,
+		Status:          models.OrderStatusPending,
+		Items:           pricing.Items,
+		ShippingAddress: req.ShippingAddress,
+		BillingAddress:  req.BillingAddress,
+		Notes:           req.Notes,
+		Subtotal:        pricing.Subtotal,
+		Tax:             pricing.Tax,
+		ShippingCost:    pricing.Shipping,
+		Total:           pricing.Total,
+	}
+
+	// Create the order, its history entry and its outbox event in one transaction
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.Insert(ctx, order); err != nil {
+			return err
+		}
+
+		if err := s.recordStatusChange(ctx, order.ID, "", order.Status, "Order created"); err != nil {
+			return err
+		}
+
+		if len(pricing.TaxLines) > 0 {
+			if err := s.taxLines.Insert(ctx, orderTaxLines(order.ID, pricing.TaxLines)); err != nil {
+				return err
+			}
+		}
+
+		if s.config.Features.EnableOrderEvents {
+			return s.eventPublisher.PublishOrderCreated(ctx, order)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to create order", logging.Fields{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	recordOrderCreated(order)
 
 	// Cache the order
 	if s.config.Features.EnableOrderCaching {
@@ -107,17 +192,6 @@ func (s *OrderService) CreateOrder(ctx context.Context, req *models.CreateOrderR
 		s.orderCache.InvalidateByUserID(ctx, order.UserID)
 	}
 
-	// Publish event
-	if s.config.Features.EnableOrderEvents {
-		if err := s.eventPublisher.PublishOrderCreated(ctx, order); err != nil {
-			// Log but don't fail
-			s.logger.Error("Failed to publish order created event", logging.Fields{
-				"order_id": order.ID,
-				"error":    err.Error(),
-			})
-		}
-	}
-
 	// Send notification
 	go s.sendOrderConfirmationNotification(context.Background(), order)
 
@@ -129,6 +203,22 @@ func (s *OrderService) CreateOrder(ctx context.Context, req *models.CreateOrderR
 	return order, nil
 }
 
+// GetOrderTaxLines returns the tax breakdown stored for an order.
+func (s *OrderService) GetOrderTaxLines(ctx context.Context, orderID string) ([]*repository.OrderTaxLine, error) {
+	if _, err := s.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.taxLines.ListByOrderID(ctx, orderID)
+}
+
+// GetOrderHistory returns an order's status history, oldest first.
+func (s *OrderService) GetOrderHistory(ctx context.Context, orderID string) ([]*repository.StatusHistoryEntry, error) {
+	if _, err := s.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.statusHistory.ListByOrderID(ctx, orderID)
+}
+
 // GetOrder retrieves an order by ID.
 func (s *OrderService) GetOrder(ctx context.Context, id string) (*models.Order, error) {
 	s.logger.Debug("Getting order", logging.Fields{"order_id": id})
@@ -158,6 +248,31 @@ func (s *OrderService) GetOrder(ctx context.Context, id string) (*models.Order,
 	return order, nil
 }
 
+// GetOrderVersioned retrieves an order and its version, reading through the
+// cache. Every versioned write invalidates the cached order once it
+// commits. A read racing a write can still cache the older version until
+// the TTL expires; writes check the version against the database, so a
+// client using it gets 412 with the current ETag rather than a lost update.
+func (s *OrderService) GetOrderVersioned(ctx context.Context, id string) (*repository.VersionedOrder, error) {
+	if s.config.Features.EnableOrderCaching {
+		if order, err := s.orderCache.GetVersioned(ctx, id); err == nil && order != nil {
+			s.logger.Debug("Order found in cache", logging.Fields{"order_id": id})
+			return order, nil
+		}
+	}
+
+	order, err := s.orderRepo.GetVersioned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.Features.EnableOrderCaching {
+		s.orderCache.SetVersioned(ctx, order)
+	}
+
+	return order, nil
+}
+
 // GetOrderV1 retrieves an order using the deprecated v1 format.
 // Deprecated: Use GetOrder instead.
 // TODO(TEAM-API): Remove after v1 API migration complete
@@ -167,149 +282,96 @@ func (s *OrderService) GetOrderV1(ctx context.Context, id int64) (*repository.Le
 	return s.legacyRepo.GetOrderByID(ctx, id)
 }
 
-// UpdateOrderStatus updates the status of an order.
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, id string, req *models.UpdateOrderStatusRequest) (*models.Order, error) {
+// UpdateOrderStatus updates the status of an order. When version is not
+// repository.AnyVersion the order must still be at that version.
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, id string, version int64, req *models.UpdateOrderStatusRequest) (*repository.VersionedOrder, error) {
 	s.logger.Info("Updating order status", logging.Fields{
 		"order_id":   id,
 		"new_status": req.Status,
 	})
 
 	// Get current order to check status transition
-	currentOrder, err := s.orderRepo.GetByID(ctx, id)
+	current, err := s.orderRepo.GetVersioned(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if currentOrder == nil {
-		return nil, errors.ErrNotFound
-	}
-
-	// Validate status transition
-	if !isValidStatusTransition(currentOrder.Status, req.Status) {
-		return nil, errors.NewValidationError("status", fmt.Sprintf(
-			"invalid status transition from %s to %s",
-			currentOrder.Status,
-			req.Status,
-		))
-	}
-
-	previousStatus := currentOrder.Status
-
-	// Update status
-	order, err := s.orderRepo.UpdateStatus(ctx, id, req)
-	if err != nil {
+	if err := checkVersion(current, version); err != nil {
 		return nil, err
 	}
 
-	// Invalidate cache
-	if s.config.Features.EnableOrderCaching {
-		s.orderCache.Delete(ctx, id)
-		s.orderCache.InvalidateByUserID(ctx, order.UserID)
-	}
-
-	// Publish event
-	if s.config.Features.EnableOrderEvents {
-		if err := s.eventPublisher.PublishOrderStatusChanged(ctx, order, previousStatus); err != nil {
-			s.logger.Error("Failed to publish status change event", logging.Fields{
-				"order_id": order.ID,
-				"error":    err.Error(),
-			})
-		}
-	}
-
-	// Send notification for important status changes
-	go s.sendStatusChangeNotification(context.Background(), order, previousStatus)
-
-	return order, nil
+	return s.transition(ctx, current, req.Status, req.Notes)
 }
 
-// CancelOrder cancels an order.
-func (s *OrderService) CancelOrder(ctx context.Context, id string, reason string) (*models.Order, error) {
+// CancelOrder cancels an order. When version is not repository.AnyVersion
+// the order must still be at that version.
+func (s *OrderService) CancelOrder(ctx context.Context, id string, version int64, reason string) (*repository.VersionedOrder, error) {
 	s.logger.Info("Cancelling order", logging.Fields{
 		"order_id": id,
 		"reason":   reason,
 	})
 
 	// Get current order
-	order, err := s.orderRepo.GetByID(ctx, id)
+	current, err := s.orderRepo.GetVersioned(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if order == nil {
-		return nil, errors.ErrNotFound
+	if err := checkVersion(current, version); err != nil {
+		return nil, err
 	}
+	order := current.Order
 
 	// Check if cancellation is allowed
 	if !order.CanCancel() {
 		return nil, errors.NewValidationError("status", "order cannot be cancelled in current state")
 	}
 
-	// Cancel any pending payment
-	if order.PaymentID != "" {
-		payment, err := s.paymentClient.GetPaymentStatus(ctx, order.PaymentID)
-		if err != nil {
-			s.logger.Error("Failed to get payment status", logging.Fields{
-				"payment_id": order.PaymentID,
-				"error":      err.Error(),
+	// Cancel any pending payment once the cancellation has committed, so the
+	// payment service is not called while the transaction is open.
+	cancelPayment := func(ctx context.Context, order *models.Order) error {
+		if order.PaymentID != "" {
+			repository.AfterCommit(ctx, func(ctx context.Context) {
+				s.cancelPendingPayment(ctx, order.PaymentID)
 			})
-		} else if payment != nil && payment.Status == models.PaymentStatusPending {
-			if err := s.paymentClient.CancelPayment(ctx, order.PaymentID); err != nil {
-				s.logger.Error("Failed to cancel payment", logging.Fields{
-					"payment_id": order.PaymentID,
-					"error":      err.Error(),
-				})
-			}
 		}
+		return nil
 	}
 
-	// Update order status
-	previousStatus := order.Status
-	req := &models.UpdateOrderStatusRequest{
-		Status: models.OrderStatusCancelled,
-		Notes:  reason,
-	}
+	return s.transitionWith(ctx, current, models.OrderStatusCancelled, reason, cancelPayment)
+}
 
-	order, err = s.orderRepo.UpdateStatus(ctx, id, req)
+// cancelPendingPayment cancels a payment that has not completed yet.
+// Failures are logged; the order is already cancelled.
+func (s *OrderService) cancelPendingPayment(ctx context.Context, paymentID string) {
+	payment, err := s.paymentClient.GetPaymentStatus(ctx, paymentID)
 	if err != nil {
-		return nil, err
+		s.logger.Error("Failed to get payment status", logging.Fields{
+			"payment_id": paymentID,
+			"error":      err.Error(),
+		})
+		return
 	}
-
-	// Invalidate cache
-	if s.config.Features.EnableOrderCaching {
-		s.orderCache.Delete(ctx, id)
-		s.orderCache.InvalidateByUserID(ctx, order.UserID)
+	if payment == nil || payment.Status != models.PaymentStatusPending {
+		return
 	}
-
-	// Publish event
-	if s.config.Features.EnableOrderEvents {
-		if err := s.eventPublisher.PublishOrderCancelled(ctx, order, reason); err != nil {
-			s.logger.Error("Failed to publish order cancelled event", logging.Fields{
-				"order_id": order.ID,
-				"error":    err.Error(),
-			})
-		}
+	if err := s.paymentClient.CancelPayment(ctx, paymentID); err != nil {
+		s.logger.Error("Failed to cancel payment", logging.Fields{
+			"payment_id": paymentID,
+			"error":      err.Error(),
+		})
 	}
-
-	go s.sendCancellationNotification(context.Background(), order, previousStatus)
-
-	return order, nil
 }
 
-// ListOrders retrieves orders based on filter criteria.
-func (s *OrderService) ListOrders(ctx context.Context, filter *models.OrderListFilter) ([]*models.Order, int, error) {
+// ListOrders retrieves a page of orders matching q. Callers validate q with
+// ValidateOrderQuery first.
+func (s *OrderService) ListOrders(ctx context.Context, q *repository.OrderQuery) (*repository.OrderPage, error) {
 	s.logger.Debug("Listing orders", logging.Fields{
-		"user_id": filter.UserID,
-		"status":  filter.Status,
+		"user_id":  q.UserID,
+		"statuses": q.Statuses,
+		"sort":     q.Sort,
+		"limit":    q.Limit,
 	})
 
-	// Set defaults
-	if filter.Limit <= 0 {
-		filter.Limit = 20
-	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
-	}
-
-	return s.orderRepo.List(ctx, filter)
+	return s.orderRepo.ListPage(ctx, q)
 }
 
 // GetUserOrders retrieves orders for a specific user.
@@ -350,21 +412,23 @@ func (s *OrderService) GetUserOrdersV1(ctx context.Context, userID int64) ([]*re
 	return s.legacyRepo.GetOrdersByUserID(ctx, userID)
 }
 
-// ProcessOrderPayment processes payment for an order.
-func (s *OrderService) ProcessOrderPayment(ctx context.Context, orderID string, paymentReq *models.ProcessPaymentRequest) (*models.ProcessPaymentResponse, error) {
+// ProcessOrderPayment processes payment for an order. When version is not
+// repository.AnyVersion the order must still be at that version.
+func (s *OrderService) ProcessOrderPayment(ctx context.Context, orderID string, version int64, paymentReq *models.ProcessPaymentRequest) (*models.ProcessPaymentResponse, error) {
 	s.logger.Info("Processing order payment", logging.Fields{
 		"order_id": orderID,
 		"method":   paymentReq.Method,
 	})
 
 	// Get the order
-	order, err := s.orderRepo.GetByID(ctx, orderID)
+	current, err := s.orderRepo.GetVersioned(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
-	if order == nil {
-		return nil, errors.ErrNotFound
+	if err := checkVersion(current, version); err != nil {
+		return nil, err
 	}
+	order := current.Order
 
 	// Ensure order is in pending state
 	if order.Status != models.OrderStatusPending {
@@ -383,7 +447,7 @@ func (s *OrderService) ProcessOrderPayment(ctx context.Context, orderID string,
 		// Use legacy payment client for bank transfers (temporary)
 		legacyReq := &models.LegacyPaymentRequest{
 			OrderID:  orderID,
-			Amount:   order.Total.ToFloat(),
+			Amount:   ToMajor(order.Total),
 			Currency: order.Total.Currency,
 		}
 		txnID, err := s.legacyPaymentClient.ProcessLegacyPayment(ctx, legacyReq)
@@ -406,7 +470,7 @@ func (s *OrderService) ProcessOrderPayment(ctx context.Context, orderID string,
 	}
 
 	// Update order with payment ID
-	if err := s.orderRepo.SetPaymentID(ctx, orderID, paymentResp.PaymentID); err != nil {
+	if err := s.attachPayment(ctx, current, paymentResp.PaymentID); err != nil {
 		s.logger.Error("Failed to set payment ID on order", logging.Fields{
 			"order_id":   orderID,
 			"payment_id": paymentResp.PaymentID,
@@ -414,12 +478,15 @@ func (s *OrderService) ProcessOrderPayment(ctx context.Context, orderID string,
 		})
 	}
 
-	// Update order status if payment completed
+	// Record the capture and confirm the order if payment completed
 	if paymentResp.Status == models.PaymentStatusCompleted {
-		s.UpdateOrderStatus(ctx, orderID, &models.UpdateOrderStatusRequest{
-			Status: models.OrderStatusConfirmed,
-			Notes:  "Payment completed",
-		})
+		if err := s.ApplyPaymentCompleted(ctx, orderID, paymentResp.PaymentID, "Payment completed"); err != nil {
+			s.logger.Error("Failed to confirm paid order", logging.Fields{
+				"order_id":   orderID,
+				"payment_id": paymentResp.PaymentID,
+				"error":      err.Error(),
+			})
+		}
 	}
 
 	// Invalidate cache
@@ -430,53 +497,26 @@ func (s *OrderService) ProcessOrderPayment(ctx context.Context, orderID string,
 	return paymentResp, nil
 }
 
-// RefundOrder processes a refund for an order.
-func (s *OrderService) RefundOrder(ctx context.Context, orderID string, reason string) (*models.RefundResponse, error) {
-	s.logger.Info("Processing order refund", logging.Fields{
-		"order_id": orderID,
-		"reason":   reason,
-	})
-
-	// Get the order
-	order, err := s.orderRepo.GetByID(ctx, orderID)
-	if err != nil {
-		return nil, err
-	}
-	if order == nil {
-		return nil, errors.ErrNotFound
-	}
+// attachPayment stores paymentID on the order read as current. The payment
+// has already been taken at this point, so if the order changed meanwhile it
+// is re-read once and the payment is still attached while the order awaits
+// payment.
+func (s *OrderService) attachPayment(ctx context.Context, current *repository.VersionedOrder, paymentID string) error {
+	defer s.invalidateOrder(ctx, current.ID)
 
-	// Check if refund is allowed
-	if !order.CanRefund() {
-		return nil, errors.NewValidationError("status", "order cannot be refunded")
-	}
-
-	// Process refund
-	refundReq := &models.RefundRequest{
-		PaymentID: order.PaymentID,
-		Amount:    order.Total,
-		Reason:    reason,
+	err := s.orderRepo.SetPaymentIDAt(ctx, current.ID, paymentID, current.Version)
+	if !stderrors.Is(err, repository.ErrVersionConflict) {
+		return err
 	}
 
-	refundResp, err := s.paymentClient.Refund(ctx, refundReq)
+	latest, err := s.orderRepo.GetVersioned(ctx, current.ID)
 	if err != nil {
-		s.logger.Error("Refund processing failed", logging.Fields{
-			"order_id":   orderID,
-			"payment_id": order.PaymentID,
-			"error":      err.Error(),
-		})
-		return nil, err
+		return err
 	}
-
-	// Update order status
-	if refundResp.Status == models.PaymentStatusRefunded {
-		s.UpdateOrderStatus(ctx, orderID, &models.UpdateOrderStatusRequest{
-			Status: models.OrderStatusRefunded,
-			Notes:  "Refund processed: " + reason,
-		})
+	if latest.Status != models.OrderStatusPending || latest.PaymentID != "" {
+		return repository.ErrVersionConflict
 	}
-
-	return refundResp, nil
+	return s.orderRepo.SetPaymentIDAt(ctx, current.ID, paymentID, latest.Version)
 }
 
 // HandlePaymentWebhook handles incoming payment webhooks.
@@ -494,8 +534,16 @@ func (s *OrderService) HandlePaymentWebhook(ctx context.Context, payload []byte,
 		return errors.NewValidationError("signature", "invalid webhook signature")
 	}
 
-	// TODO(TEAM-PAYMENTS): Parse and process webhook payload
-	s.logger.Info("Payment webhook processed")
+	event, err := ParsePaymentWebhook(payload)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ApplyPaymentWebhook(ctx, event); err != nil {
+		return err
+	}
+
+	s.logger.Info("Payment webhook processed", logging.Fields{"event_id": event.ID})
 	return nil
 }
 
@@ -508,15 +556,15 @@ func (s *OrderService) sendOrderConfirmationNotification(ctx context.Context, or
 		Subject:   "Order Confirmation",
 		Body:      fmt.Sprintf("Your order %s has been received.", order.ID),
 		TemplateData: map[string]interface{}{
-			"subtotal":      order.Subtotal.ToFloat(),
-			"tax":           order.Tax.ToFloat(),
-			"shipping_cost": order.ShippingCost.ToFloat(),
-			"total":         order.Total.ToFloat(),
+			"subtotal":      FormatMoney(order.Subtotal),
+			"tax":           FormatMoney(order.Tax),
+			"shipping_cost": FormatMoney(order.ShippingCost),
+			"total":         FormatMoney(order.Total),
 			"currency":      order.Total.Currency,
 		},
 		Metadata: map[string]string{
 			"order_id": order.ID,
-			"total":    fmt.Sprintf("%.2f %s", order.Total.ToFloat(), order.Total.Currency),
+			"total":    FormatMoney(order.Total) + " " + order.Total.Currency,
 		},
 	}
 
@@ -528,23 +576,7 @@ func (s *OrderService) sendOrderConfirmationNotification(ctx context.Context, or
 	}
 }
 
-func (s *OrderService) sendStatusChangeNotification(ctx context.Context, order *models.Order, previousStatus models.OrderStatus) {
-	var notificationType models.NotificationType
-	var subject, body string
-
-	switch order.Status {
-	case models.OrderStatusShipped:
-		notificationType = models.NotificationTypeOrderShipped
-		subject = "Order Shipped"
-		body = fmt.Sprintf("Your order %s has been shipped.", order.ID)
-	case models.OrderStatusDelivered:
-		notificationType = models.NotificationTypeOrderDelivered
-		subject = "Order Delivered"
-		body = fmt.Sprintf("Your order %s has been delivered.", order.ID)
-	default:
-		return // No notification for other status changes
-	}
-
+func (s *OrderService) sendStatusNotification(ctx context.Context, order *models.Order, notificationType models.NotificationType, subject, body string) {
 	req := &models.SendNotificationRequest{
 		Type:      notificationType,
 		Priority:  models.NotificationPriorityNormal,
@@ -561,7 +593,7 @@ func (s *OrderService) sendStatusChangeNotification(ctx context.Context, order *
 	}
 }
 
-func (s *OrderService) sendCancellationNotification(ctx context.Context, order *models.Order, previousStatus models.OrderStatus) {
+func (s *OrderService) sendCancellationNotification(ctx context.Context, order *models.Order) {
 	req := &models.SendNotificationRequest{
 		Type:      models.NotificationTypeOrderCancelled,
 		Priority:  models.NotificationPriorityNormal,
@@ -581,27 +613,3 @@ func (s *OrderService) sendCancellationNotification(ctx context.Context, order *
 		})
 	}
 }
-
-func isValidStatusTransition(from, to models.OrderStatus) bool {
-	validTransitions := map[models.OrderStatus][]models.OrderStatus{
-		models.OrderStatusPending:    {models.OrderStatusConfirmed, models.OrderStatusCancelled},
-		models.OrderStatusConfirmed:  {models.OrderStatusProcessing, models.OrderStatusCancelled},
-		models.OrderStatusProcessing: {models.OrderStatusShipped, models.OrderStatusCancelled},
-		models.OrderStatusShipped:    {models.OrderStatusDelivered},
-		models.OrderStatusDelivered:  {models.OrderStatusRefunded},
-		models.OrderStatusCancelled:  {},
-		models.OrderStatusRefunded:   {},
-	}
-
-	allowed, ok := validTransitions[from]
-	if !ok {
-		return false
-	}
-
-	for _, status := range allowed {
-		if status == to {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// memoryOrderCache keeps versioned orders in memory.
+type memoryOrderCache struct {
+	repository.OrderCache
+	orders map[string]*repository.VersionedOrder
+}
+
+func (m *memoryOrderCache) GetVersioned(ctx context.Context, id string) (*repository.VersionedOrder, error) {
+	return m.orders[id], nil
+}
+
+func (m *memoryOrderCache) SetVersioned(ctx context.Context, order *repository.VersionedOrder) error {
+	m.orders[order.ID] = order
+	return nil
+}
+
+func (m *memoryOrderCache) Delete(ctx context.Context, id string) error {
+	delete(m.orders, id)
+	return nil
+}
+
+func (m *memoryOrderCache) InvalidateByUserID(ctx context.Context, userID string) error {
+	return nil
+}
+
+func TestGetOrderVersioned_ReadsThroughCache(t *testing.T) {
+	orders := newMemoryOrderStore(&models.Order{ID: "ord_1", Status: models.OrderStatusPending})
+	cache := &memoryOrderCache{orders: make(map[string]*repository.VersionedOrder)}
+	s := newPaymentEventsTestService(orders, inlineTx{})
+	s.orderCache = cache
+	s.config.Features.EnableOrderCaching = true
+
+	first, err := s.GetOrderVersioned(context.Background(), "ord_1")
+	if err != nil {
+		t.Fatalf("GetOrderVersioned: %v", err)
+	}
+	if cached := cache.orders["ord_1"]; cached == nil || cached.Version != first.Version {
+		t.Fatalf("Expected the order to be cached at version %d, got %+v", first.Version, cached)
+	}
+
+	// A versioned write drops the cached order, so the next read sees the
+	// new version.
+	if err := s.ApplyPaymentCompleted(context.Background(), "ord_1", "pay_1", "Payment completed"); err != nil {
+		t.Fatalf("ApplyPaymentCompleted: %v", err)
+	}
+	if _, ok := cache.orders["ord_1"]; ok {
+		t.Fatal("Expected the write to invalidate the cached order")
+	}
+
+	second, err := s.GetOrderVersioned(context.Background(), "ord_1")
+	if err != nil {
+		t.Fatalf("GetOrderVersioned: %v", err)
+	}
+	if second.Version <= first.Version || second.Status != models.OrderStatusConfirmed {
+		t.Errorf("Expected a newer confirmed order, got version %d status %s", second.Version, second.Status)
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import "github.com/tm-acme-shop/acme-shop-shared-go/models"
+
+// Order statuses used by this service in addition to those defined in the
+// shared models package.
+const (
+	// OrderStatusPartiallyRefunded is an order with at least one refund that
+	// does not cover the full captured amount.
+	OrderStatusPartiallyRefunded models.OrderStatus = "partially_refunded"
+	// OrderStatusPartiallyShipped is an order with some, but not all, of its
+	// items in shipments that have left the warehouse.
+	OrderStatusPartiallyShipped models.OrderStatus = "partially_shipped"
+	// OrderStatusDisputed is an order whose payment is under chargeback.
+	OrderStatusDisputed models.OrderStatus = "disputed"
+)
+
+// knownOrderStatuses is every status an order can be in.
+var knownOrderStatuses = map[models.OrderStatus]bool{
+	models.OrderStatusPending:    true,
+	models.OrderStatusConfirmed:  true,
+	models.OrderStatusProcessing: true,
+	models.OrderStatusShipped:    true,
+	models.OrderStatusDelivered:  true,
+	models.OrderStatusCancelled:  true,
+	models.OrderStatusRefunded:   true,
+	OrderStatusPartiallyRefunded: true,
+	OrderStatusPartiallyShipped:  true,
+	OrderStatusDisputed:          true,
+}
+
+// IsKnownOrderStatus reports whether status is a status an order can be in.
+func IsKnownOrderStatus(status models.OrderStatus) bool {
+	return knownOrderStatuses[status]
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// Lifecycle returns the order state machine.
+func (s *OrderService) Lifecycle() *StateMachine {
+	return s.lifecycle
+}
+
+// transition moves order to status through the state machine: it checks the
+// rule and its guards, updates the status, records it in the status history
+// and runs in-transaction hooks atomically, then runs the after-commit hooks
+// once the outermost transaction commits.
+// The update only applies while the order is still at current.Version, so a
+// concurrent change makes it fail with repository.ErrVersionConflict instead
+// of being overwritten.
+func (s *OrderService) transition(ctx context.Context, current *repository.VersionedOrder, to models.OrderStatus, notes string) (*repository.VersionedOrder, error) {
+	return s.transitionWith(ctx, current, to, notes, nil)
+}
+
+// transitionWith is transition with an extra write, inTx, committed
+// atomically with the status change. inTx receives the updated order.
+func (s *OrderService) transitionWith(
+	ctx context.Context,
+	current *repository.VersionedOrder,
+	to models.OrderStatus,
+	notes string,
+	inTx func(ctx context.Context, order *models.Order) error,
+) (*repository.VersionedOrder, error) {
+	order := current.Order
+	from := order.Status
+
+	rule := s.lifecycle.Rule(from, to)
+	if rule == nil {
+		return nil, errors.NewValidationError("status", fmt.Sprintf(
+			"invalid status transition from %s to %s",
+			from,
+			to,
+		))
+	}
+
+	t := &Transition{Order: order, From: from, To: to, Notes: notes, PaymentStatus: current.PaymentStatus}
+	if err := s.lifecycle.CheckGuards(ctx, rule, t); err != nil {
+		return nil, err
+	}
+
+	var updated *repository.VersionedOrder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.orderRepo.UpdateStatusAt(ctx, order.ID, current.Version, &models.UpdateOrderStatusRequest{
+			Status: to,
+			Notes:  notes,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.recordStatusChange(ctx, order.ID, from, to, notes); err != nil {
+			return err
+		}
+		// The version changed, so the cached order is dropped whichever
+		// hooks the lifecycle configures.
+		s.invalidateOrder(ctx, order.ID)
+
+		if inTx != nil {
+			if err := inTx(ctx, updated.Order); err != nil {
+				return err
+			}
+		}
+
+		t.Order = updated.Order
+		if err := s.lifecycle.RunHooks(ctx, rule, HookInTx, t); err != nil {
+			return err
+		}
+
+		// When the transition joins a caller's transaction, the after-commit
+		// hooks must wait for that transaction rather than run on return.
+		repository.AfterCommit(ctx, func(ctx context.Context) {
+			recordTransition(t.Order, from, to)
+			if err := s.lifecycle.RunHooks(ctx, rule, HookAfterCommit, t); err != nil {
+				s.logger.Error("Order transition side effects failed", logging.Fields{
+					"order_id": order.ID,
+					"from":     from,
+					"to":       to,
+					"error":    err.Error(),
+				})
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// saveWithStatus runs save, a write to records that belong to the order,
+// and moves the order to target in the same transaction. When target is
+// empty or the current status the order version is still bumped, so
+// concurrent writes derived from the same order state cannot both succeed.
+func (s *OrderService) saveWithStatus(
+	ctx context.Context,
+	current *repository.VersionedOrder,
+	target models.OrderStatus,
+	notes string,
+	save func(ctx context.Context, order *models.Order) error,
+) error {
+	if target != "" && target != current.Status {
+		_, err := s.transitionWith(ctx, current, target, notes, save)
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.TouchAt(ctx, current.ID, current.Version); err != nil {
+			return err
+		}
+		if err := save(ctx, current.Order); err != nil {
+			return err
+		}
+
+		s.invalidateOrder(ctx, current.ID)
+		return nil
+	})
+}
+
+// invalidateOrder drops an order from the cache once the write made in ctx
+// commits, so cached reads do not keep serving its previous version.
+func (s *OrderService) invalidateOrder(ctx context.Context, id string) {
+	if !s.config.Features.EnableOrderCaching {
+		return
+	}
+	repository.AfterCommit(ctx, func(ctx context.Context) {
+		if err := s.orderCache.Delete(ctx, id); err != nil {
+			s.logger.Error("Failed to invalidate cached order", logging.Fields{
+				"order_id": id,
+				"error":    err.Error(),
+			})
+		}
+	})
+}
+
+// recordStatusChange appends a status history entry attributed to the audit
+// info carried by ctx.
+func (s *OrderService) recordStatusChange(ctx context.Context, orderID string, from, to models.OrderStatus, reason string) error {
+	audit := AuditFromContext(ctx)
+	return s.statusHistory.Record(ctx, &repository.StatusHistoryEntry{
+		OrderID:   orderID,
+		From:      from,
+		To:        to,
+		Actor:     audit.Actor,
+		Reason:    reason,
+		Source:    string(audit.Source),
+		RequestID: audit.RequestID,
+	})
+}
+
+func (s *OrderService) transitionGuards() map[string]TransitionGuard {
+	return map[string]TransitionGuard{
+		GuardPaymentCaptured: func(ctx context.Context, t *Transition) error {
+			// An attached payment may still be pending or have failed; only
+			// a completed payment recorded from the payment service counts.
+			if t.Order.PaymentID == "" || t.PaymentStatus != models.PaymentStatusCompleted {
+				return errors.NewValidationError("status", fmt.Sprintf(
+					"order cannot move to %s before a payment is captured", t.To))
+			}
+			return nil
+		},
+	}
+}
+
+func (s *OrderService) transitionHooks() map[string]boundHook {
+	notify := func(send func(ctx context.Context, order *models.Order)) TransitionHook {
+		return func(ctx context.Context, t *Transition) error {
+			go send(context.Background(), t.Order)
+			return nil
+		}
+	}
+
+	hooks := []boundHook{
+		{
+			name:  HookPublishStatusChanged,
+			phase: HookInTx,
+			fn: func(ctx context.Context, t *Transition) error {
+				if !s.config.Features.EnableOrderEvents {
+					return nil
+				}
+				return s.eventPublisher.PublishOrderStatusChanged(ctx, t.Order, t.From)
+			},
+		},
+		{
+			name:  HookPublishCancelled,
+			phase: HookInTx,
+			fn: func(ctx context.Context, t *Transition) error {
+				if !s.config.Features.EnableOrderEvents {
+					return nil
+				}
+				return s.eventPublisher.PublishOrderCancelled(ctx, t.Order, t.Notes)
+			},
+		},
+		{
+			name:  HookInvalidateCache,
+			phase: HookAfterCommit,
+			fn: func(ctx context.Context, t *Transition) error {
+				if !s.config.Features.EnableOrderCaching {
+					return nil
+				}
+				if err := s.orderCache.Delete(ctx, t.Order.ID); err != nil {
+					return err
+				}
+				return s.orderCache.InvalidateByUserID(ctx, t.Order.UserID)
+			},
+		},
+		{
+			name:  HookNotifyShipped,
+			phase: HookAfterCommit,
+			fn: notify(func(ctx context.Context, order *models.Order) {
+				s.sendStatusNotification(ctx, order, models.NotificationTypeOrderShipped,
+					"Order Shipped", fmt.Sprintf("Your order %s has been shipped.", order.ID))
+			}),
+		},
+		{
+			name:  HookNotifyDelivered,
+			phase: HookAfterCommit,
+			fn: notify(func(ctx context.Context, order *models.Order) {
+				s.sendStatusNotification(ctx, order, models.NotificationTypeOrderDelivered,
+					"Order Delivered", fmt.Sprintf("Your order %s has been delivered.", order.ID))
+			}),
+		},
+		{
+			name:  HookNotifyCancelled,
+			phase: HookAfterCommit,
+			fn:    notify(s.sendCancellationNotification),
+		},
+	}
+
+	bound := make(map[string]boundHook, len(hooks))
+	for _, hook := range hooks {
+		bound[hook.name] = hook
+	}
+	return bound
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// ApplyPaymentWebhook applies a payment webhook to its order. Each event ID
+// is applied at most once; redeliveries are acknowledged without effect.
+func (s *OrderService) ApplyPaymentWebhook(ctx context.Context, event *PaymentWebhookEvent) error {
+	s.logger.Info("Applying payment webhook", logging.Fields{
+		"event_id":   event.ID,
+		"event_type": event.Type,
+		"order_id":   event.Data.OrderID,
+		"payment_id": event.Data.PaymentID,
+	})
+
+	if _, ok := ctx.Value(auditKey{}).(AuditInfo); !ok {
+		ctx = WithAudit(ctx, AuditInfo{Source: ChangeSourceWebhook, RequestID: event.ID})
+	}
+
+	_, err := s.ApplyOnce(ctx, paymentWebhookConsumer, event.ID, func(ctx context.Context) error {
+		orderID := event.Data.OrderID
+
+		switch event.Type {
+		case PaymentWebhookCompleted:
+			return s.ApplyPaymentCompleted(ctx, orderID, event.Data.PaymentID, "Payment completed via webhook")
+		case PaymentWebhookFailed:
+			reason := "Payment failed"
+			if event.Data.FailureReason != "" {
+				reason = "Payment failed: " + event.Data.FailureReason
+			}
+			return s.ApplyPaymentFailed(ctx, orderID, reason)
+		case PaymentWebhookRefunded, PaymentWebhookPartiallyRefunded:
+			if event.Data.RefundID != "" {
+				reconciled, err := s.ReconcileRefund(ctx, orderID, event.Data.RefundID, event.Data.Status)
+				if err != nil || reconciled {
+					return err
+				}
+			}
+			if event.Type == PaymentWebhookRefunded {
+				return s.ApplyPaymentRefunded(ctx, orderID, "Payment refunded via webhook")
+			}
+			notes := "Payment partially refunded via webhook"
+			if event.Data.RefundedAmount != nil {
+				notes = fmt.Sprintf("Payment partially refunded via webhook: %d %s",
+					event.Data.RefundedAmount.Amount, event.Data.RefundedAmount.Currency)
+			}
+			return s.ApplyPaymentPartiallyRefunded(ctx, orderID, notes)
+		case PaymentWebhookDisputed:
+			notes := "Payment disputed"
+			if event.Data.DisputeReason != "" {
+				notes = "Payment disputed: " + event.Data.DisputeReason
+			}
+			return s.ApplyPaymentDisputed(ctx, orderID, notes)
+		default:
+			s.logger.Info("Ignoring unknown payment webhook type", logging.Fields{
+				"event_id":   event.ID,
+				"event_type": event.Type,
+			})
+			return nil
+		}
+	})
+	return err
+}
+
+// ApplyPaymentCompleted records paymentID as the order's captured payment
+// and confirms the order. The completion can arrive before
+// ProcessOrderPayment has attached the payment, or for a payment started
+// elsewhere, so an order without a payment gets paymentID attached in the
+// same transaction; a different payment already on the order is refused.
+func (s *OrderService) ApplyPaymentCompleted(ctx context.Context, orderID, paymentID, notes string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errors.ErrNotFound
+		}
+
+		switch {
+		case paymentID == "" || order.PaymentID == paymentID:
+		case order.PaymentID == "":
+			if err := s.orderRepo.SetPaymentIDAt(ctx, orderID, paymentID, repository.AnyVersion); err != nil {
+				return err
+			}
+		default:
+			return errors.NewValidationError("payment_id", fmt.Sprintf(
+				"order already has payment %s", order.PaymentID))
+		}
+
+		if err := s.orderRepo.SetPaymentStatus(ctx, orderID, models.PaymentStatusCompleted); err != nil {
+			return err
+		}
+		s.invalidateOrder(ctx, orderID)
+		return s.applyPaymentTransition(ctx, orderID, models.OrderStatusConfirmed, notes)
+	})
+}
+
+// ApplyPaymentFailed cancels an order whose payment failed.
+func (s *OrderService) ApplyPaymentFailed(ctx context.Context, orderID, reason string) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return errors.ErrNotFound
+	}
+
+	if order.Status == models.OrderStatusCancelled {
+		return nil
+	}
+
+	_, err = s.CancelOrder(ctx, orderID, repository.AnyVersion, reason)
+	return err
+}
+
+// ApplyPaymentRefunded marks an order as fully refunded.
+func (s *OrderService) ApplyPaymentRefunded(ctx context.Context, orderID, notes string) error {
+	return s.applyPaymentTransition(ctx, orderID, models.OrderStatusRefunded, notes)
+}
+
+// ApplyPaymentPartiallyRefunded marks an order as partially refunded.
+func (s *OrderService) ApplyPaymentPartiallyRefunded(ctx context.Context, orderID, notes string) error {
+	return s.applyPaymentTransition(ctx, orderID, OrderStatusPartiallyRefunded, notes)
+}
+
+// ApplyPaymentDisputed marks an order whose payment is under chargeback.
+func (s *OrderService) ApplyPaymentDisputed(ctx context.Context, orderID, notes string) error {
+	return s.applyPaymentTransition(ctx, orderID, OrderStatusDisputed, notes)
+}
+
+func (s *OrderService) applyPaymentTransition(ctx context.Context, orderID string, status models.OrderStatus, notes string) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return errors.ErrNotFound
+	}
+
+	// The outcome may already have been applied through another channel
+	// (Kafka event, webhook or synchronous payment response).
+	if order.Status == status {
+		return nil
+	}
+
+	_, err = s.UpdateOrderStatus(ctx, orderID, repository.AnyVersion, &models.UpdateOrderStatusRequest{
+		Status: status,
+		Notes:  notes,
+	})
+	return err
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// memoryOrderStore keeps orders in memory. Only the methods used by the
+// payment event paths are implemented.
+type memoryOrderStore struct {
+	repository.OrderStore
+	orders map[string]*repository.VersionedOrder
+}
+
+func newMemoryOrderStore(orders ...*models.Order) *memoryOrderStore {
+	m := &memoryOrderStore{orders: make(map[string]*repository.VersionedOrder)}
+	for _, order := range orders {
+		m.orders[order.ID] = &repository.VersionedOrder{Order: order, Version: repository.InitialVersion}
+	}
+	return m
+}
+
+func (m *memoryOrderStore) GetVersioned(ctx context.Context, id string) (*repository.VersionedOrder, error) {
+	current, ok := m.orders[id]
+	if !ok {
+		return nil, errors.ErrNotFound
+	}
+	order := *current.Order
+	return &repository.VersionedOrder{Order: &order, Version: current.Version, PaymentStatus: current.PaymentStatus}, nil
+}
+
+func (m *memoryOrderStore) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	current, err := m.GetVersioned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return current.Order, nil
+}
+
+func (m *memoryOrderStore) write(id string, version int64, change func(current *repository.VersionedOrder)) error {
+	current, ok := m.orders[id]
+	if !ok {
+		return errors.ErrNotFound
+	}
+	if version != repository.AnyVersion && version != current.Version {
+		return repository.ErrVersionConflict
+	}
+	change(current)
+	current.Version++
+	return nil
+}
+
+func (m *memoryOrderStore) UpdateStatusAt(ctx context.Context, id string, version int64, req *models.UpdateOrderStatusRequest) (*repository.VersionedOrder, error) {
+	err := m.write(id, version, func(current *repository.VersionedOrder) {
+		current.Status = req.Status
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.GetVersioned(ctx, id)
+}
+
+func (m *memoryOrderStore) SetPaymentIDAt(ctx context.Context, orderID, paymentID string, version int64) error {
+	return m.write(orderID, version, func(current *repository.VersionedOrder) {
+		current.PaymentID = paymentID
+	})
+}
+
+func (m *memoryOrderStore) TouchAt(ctx context.Context, id string, version int64) error {
+	return m.write(id, version, func(current *repository.VersionedOrder) {})
+}
+
+func (m *memoryOrderStore) SetPaymentStatus(ctx context.Context, orderID string, status models.PaymentStatus) error {
+	return m.write(orderID, repository.AnyVersion, func(current *repository.VersionedOrder) {
+		current.PaymentStatus = status
+	})
+}
+
+type memoryStatusHistory struct {
+	entries []*repository.StatusHistoryEntry
+}
+
+func (m *memoryStatusHistory) Record(ctx context.Context, entry *repository.StatusHistoryEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memoryStatusHistory) ListByOrderID(ctx context.Context, orderID string) ([]*repository.StatusHistoryEntry, error) {
+	return m.entries, nil
+}
+
+func newPaymentEventsTestService(orders *memoryOrderStore, tx repository.Transactor) *OrderService {
+	s := &OrderService{
+		orderRepo:       orders,
+		processedEvents: &memoryInbox{seen: make(map[string]bool)},
+		statusHistory:   &memoryStatusHistory{},
+		tx:              tx,
+		config:          &config.Config{},
+		logger:          logging.NewLoggerV2("test"),
+	}
+	s.lifecycle = newStateMachine(DefaultOrderLifecycle(), s.transitionGuards(), s.transitionHooks())
+	return s
+}
+
+func TestApplyPaymentCompleted_BeforePaymentAttached(t *testing.T) {
+	orders := newMemoryOrderStore(&models.Order{ID: "ord_1", Status: models.OrderStatusPending})
+	s := newPaymentEventsTestService(orders, inlineTx{})
+
+	if err := s.ApplyPaymentCompleted(context.Background(), "ord_1", "pay_1", "Payment completed via event"); err != nil {
+		t.Fatalf("ApplyPaymentCompleted: %v", err)
+	}
+
+	order := orders.orders["ord_1"]
+	if order.Status != models.OrderStatusConfirmed {
+		t.Errorf("Expected order to be confirmed, got %s", order.Status)
+	}
+	if order.PaymentID != "pay_1" {
+		t.Errorf("Expected payment pay_1 to be attached, got %q", order.PaymentID)
+	}
+	if order.PaymentStatus != models.PaymentStatusCompleted {
+		t.Errorf("Expected payment status completed, got %q", order.PaymentStatus)
+	}
+}
+
+func TestApplyPaymentCompleted_RejectsOtherPayment(t *testing.T) {
+	orders := newMemoryOrderStore(&models.Order{ID: "ord_1", Status: models.OrderStatusPending, PaymentID: "pay_1"})
+	s := newPaymentEventsTestService(orders, inlineTx{})
+
+	err := s.ApplyPaymentCompleted(context.Background(), "ord_1", "pay_2", "Payment completed via event")
+	if err == nil {
+		t.Fatal("Expected a completion for another payment to be refused")
+	}
+	if order := orders.orders["ord_1"]; order.Status != models.OrderStatusPending || order.PaymentStatus != "" {
+		t.Errorf("Expected order to be unchanged, got status %s payment status %q", order.Status, order.PaymentStatus)
+	}
+}
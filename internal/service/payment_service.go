@@ -15,6 +15,7 @@ type PaymentService struct {
 	paymentClient       interfaces.PaymentClient
 	legacyPaymentClient interfaces.LegacyPaymentClient
 	orderRepo           interfaces.OrderRepository
+	paymentEvents       PaymentEventApplier
 	config              *config.Config
 	logger              *logging.LoggerV2
 }
@@ -24,12 +25,14 @@ func NewPaymentService(
 	paymentClient interfaces.PaymentClient,
 	legacyPaymentClient interfaces.LegacyPaymentClient,
 	orderRepo interfaces.OrderRepository,
+	paymentEvents PaymentEventApplier,
 	cfg *config.Config,
 ) *PaymentService {
 	return &PaymentService{
 		paymentClient:       paymentClient,
 		legacyPaymentClient: legacyPaymentClient,
 		orderRepo:           orderRepo,
+		paymentEvents:       paymentEvents,
 		config:              cfg,
 		logger:              logging.NewLoggerV2("payment-service"),
 	}
@@ -196,8 +199,24 @@ func (s *PaymentService) ProcessWebhook(ctx context.Context, payload []byte, sig
 		return errors.NewValidationError("signature", "invalid webhook signature")
 	}
 
-	// TODO(TEAM-PAYMENTS): Parse webhook payload and update order status
-	s.logger.Info("Payment webhook processed")
+	event, err := ParsePaymentWebhook(payload)
+	if err != nil {
+		return err
+	}
+
+	if err := s.paymentEvents.ApplyPaymentWebhook(ctx, event); err != nil {
+		s.logger.Error("Failed to apply payment webhook", logging.Fields{
+			"event_id": event.ID,
+			"order_id": event.Data.OrderID,
+			"error":    err.Error(),
+		})
+		return err
+	}
+
+	s.logger.Info("Payment webhook processed", logging.Fields{
+		"event_id":   event.ID,
+		"event_type": event.Type,
+	})
 	return nil
 }
 
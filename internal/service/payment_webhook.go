@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// PaymentWebhookType is the type of a payment provider webhook.
+type PaymentWebhookType string
+
+const (
+	PaymentWebhookCompleted         PaymentWebhookType = "payment.completed"
+	PaymentWebhookFailed            PaymentWebhookType = "payment.failed"
+	PaymentWebhookRefunded          PaymentWebhookType = "payment.refunded"
+	PaymentWebhookPartiallyRefunded PaymentWebhookType = "payment.partially_refunded"
+	PaymentWebhookDisputed          PaymentWebhookType = "payment.disputed"
+)
+
+// paymentWebhookConsumer names the webhook path in the processed events inbox.
+const paymentWebhookConsumer = "payment-webhook"
+
+// PaymentWebhookEvent is the payload the payment service posts to our webhooks.
+type PaymentWebhookEvent struct {
+	ID        string             `json:"id"`
+	Type      PaymentWebhookType `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      PaymentWebhookData `json:"data"`
+}
+
+// PaymentWebhookData carries the payment the webhook refers to.
+type PaymentWebhookData struct {
+	PaymentID      string               `json:"payment_id"`
+	OrderID        string               `json:"order_id"`
+	Status         models.PaymentStatus `json:"status"`
+	Amount         models.Money         `json:"amount"`
+	RefundID       string               `json:"refund_id,omitempty"`
+	RefundedAmount *models.Money        `json:"refunded_amount,omitempty"`
+	FailureReason  string               `json:"failure_reason,omitempty"`
+	DisputeReason  string               `json:"dispute_reason,omitempty"`
+}
+
+// PaymentEventApplier applies payment webhooks to orders.
+type PaymentEventApplier interface {
+	ApplyPaymentWebhook(ctx context.Context, event *PaymentWebhookEvent) error
+}
+
+// ParsePaymentWebhook decodes and validates a webhook payload.
+func ParsePaymentWebhook(payload []byte) (*PaymentWebhookEvent, error) {
+	var event PaymentWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, errors.NewValidationError("payload", "invalid webhook payload")
+	}
+
+	if event.ID == "" {
+		return nil, errors.NewValidationError("id", "webhook event ID is required")
+	}
+
+	if event.Type == "" {
+		return nil, errors.NewValidationError("type", "webhook event type is required")
+	}
+
+	if event.Data.OrderID == "" {
+		return nil, errors.NewValidationError("data.order_id", "order ID is required")
+	}
+
+	if event.Data.PaymentID == "" {
+		return nil, errors.NewValidationError("data.payment_id", "payment ID is required")
+	}
+
+	return &event, nil
+}
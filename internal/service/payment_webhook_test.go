@@ -0,0 +1,42 @@
+package service
+
+import "testing"
+
+func TestParsePaymentWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		shouldError bool
+	}{
+		{
+			name:    "completed",
+			payload: `{"id":"evt_1","type":"payment.completed","data":{"payment_id":"pay_1","order_id":"ord_1","status":"completed"}}`,
+		},
+		{
+			name:    "partially refunded",
+			payload: `{"id":"evt_2","type":"payment.partially_refunded","data":{"payment_id":"pay_1","order_id":"ord_1","refunded_amount":{"amount":500,"currency":"USD"}}}`,
+		},
+		{"malformed JSON", `{"id":`, true},
+		{"missing event ID", `{"type":"payment.completed","data":{"payment_id":"pay_1","order_id":"ord_1"}}`, true},
+		{"missing order ID", `{"id":"evt_1","type":"payment.completed","data":{"payment_id":"pay_1"}}`, true},
+		{"missing payment ID", `{"id":"evt_1","type":"payment.completed","data":{"order_id":"ord_1"}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParsePaymentWebhook([]byte(tt.payload))
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error, got event %+v", event)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if event.Data.OrderID != "ord_1" {
+				t.Errorf("Expected order ID ord_1, got %s", event.Data.OrderID)
+			}
+		})
+	}
+}
@@ -1,21 +1,378 @@
 package service
 
-import "math"
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// RoundingMode selects how fractional minor units are rounded.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero (0.5 -> 1, -0.5 -> -1).
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the nearest even unit (banker's rounding).
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown truncates toward zero.
+	RoundDown RoundingMode = "down"
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = "up"
+)
+
+// ParseRoundingMode parses a configured rounding mode. Empty means half-up.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(s)); mode {
+	case "":
+		return RoundHalfUp, nil
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q", s)
+	}
+}
+
+// currencyExponents lists ISO 4217 currencies whose minor unit is not 1/100.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent returns the number of decimal digits in a currency's minor
+// unit: 0 for JPY, 3 for KWD and 2 for everything else.
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// FormatMoney renders m in major units with the currency's exponent, e.g.
+// "12.34" for USD, "1234" for JPY and "1.234" for KWD.
+func FormatMoney(m models.Money) string {
+	exp := CurrencyExponent(m.Currency)
+	if exp == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	scale := pow10(exp)
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, exp, amount%scale)
+}
+
+// ToMajor converts m to a float in major units. Only use it at the edge of
+// APIs that require floats; all arithmetic should stay in minor units.
+func ToMajor(m models.Money) float64 {
+	f, _ := strconv.ParseFloat(FormatMoney(m), 64)
+	return f
+}
+
+// MoneyFromMajor converts a major-unit amount, such as a legacy API float, to
+// minor units using the currency's exponent and half-even rounding.
+func MoneyFromMajor(amount float64, currency string) models.Money {
+	exp := CurrencyExponent(currency)
+	digits := strconv.FormatFloat(amount, 'f', -1, 64)
+
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+
+	// Pad or split the fraction at the exponent and round what is left over.
+	var extra string
+	if len(frac) > exp {
+		frac, extra = frac[:exp], frac[exp:]
+	} else {
+		frac += strings.Repeat("0", exp-len(frac))
+	}
+	minor, _ := strconv.ParseInt(whole+frac, 10, 64)
+	if extra != "" && roundHalfEvenUp(minor, extra) {
+		minor++
+	}
+
+	if negative {
+		minor = -minor
+	}
+	return models.Money{Amount: minor, Currency: currency}
+}
+
+// RateScale is the denominator of Rate.
+const RateScale = 1000000
+
+// Rate is a ratio expressed in parts per million, e.g. 8.8% is 88000.
+type Rate int64
+
+// RateFromFloat converts a fractional rate such as 0.088 to a Rate.
+func RateFromFloat(f float64) Rate {
+	return Rate(math.Round(f * RateScale))
+}
+
+// Apply returns amount × rate in the same minor units, rounded with mode.
+func (r Rate) Apply(amount int64, mode RoundingMode) int64 {
+	return divRound(amount*int64(r), RateScale, mode)
+}
 
 // OrderTotal represents the pricing breakdown for an order.
 type OrderTotal struct {
-	Subtotal float64 `json:"subtotal"`
-	Tax      float64 `json:"tax"`
-	Total    float64 `json:"total"`
+	Subtotal models.Money `json:"subtotal"`
+	Tax      models.Money `json:"tax"`
+	Total    models.Money `json:"total"`
 }
 
-// CalculateTax computes tax based on subtotal and configured tax rate.
-func CalculateTax(subtotal float64, taxRate float64) float64 {
-	return math.Round(subtotal*taxRate*100) / 100
+// CalculateTax computes tax on subtotal at rate.
+func CalculateTax(subtotal models.Money, rate Rate, mode RoundingMode) models.Money {
+	return models.Money{Amount: rate.Apply(subtotal.Amount, mode), Currency: subtotal.Currency}
 }
 
 // CalculateOrderTotal computes the full order breakdown.
-func CalculateOrderTotal(subtotal float64, taxRate float64) OrderTotal {
-	tax := CalculateTax(subtotal, taxRate)
-	return OrderTotal{Subtotal: subtotal, Tax: tax, Total: subtotal + tax}
+func CalculateOrderTotal(subtotal models.Money, rate Rate, mode RoundingMode) OrderTotal {
+	tax := CalculateTax(subtotal, rate, mode)
+	return OrderTotal{
+		Subtotal: subtotal,
+		Tax:      tax,
+		Total:    models.Money{Amount: subtotal.Amount + tax.Amount, Currency: subtotal.Currency},
+	}
+}
+
+// Allocate splits total across weights proportionally using the largest
+// remainder method, so the parts always sum to exactly total.
+func Allocate(total int64, weights []int64) []int64 {
+	parts := make([]int64, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	var sum int64
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 {
+		parts[0] = total
+		return parts
+	}
+
+	// Multiply in big integers: total × weight can exceed int64.
+	bigTotal := big.NewInt(total)
+	bigSum := big.NewInt(sum)
+	remainders := make([]*big.Int, len(weights))
+	var allocated int64
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(bigTotal, big.NewInt(w)), bigSum, new(big.Int))
+		parts[i] = q.Int64()
+		remainders[i] = r.Abs(r)
+		allocated += parts[i]
+	}
+
+	// Hand out the leftover units to the largest remainders, earliest first.
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+
+	step := int64(1)
+	if total < 0 {
+		step = -1
+	}
+	for i := 0; allocated != total; i = (i + 1) % len(order) {
+		parts[order[i]] += step
+		allocated += step
+	}
+
+	return parts
+}
+
+// divRound divides num by a positive den, rounding the quotient with mode.
+func divRound(num, den int64, mode RoundingMode) int64 {
+	q, rem := num/den, num%den
+	if rem == 0 {
+		return q
+	}
+
+	sign := int64(1)
+	if num < 0 {
+		sign = -1
+		rem = -rem
+	}
+
+	switch mode {
+	case RoundDown:
+		return q
+	case RoundUp:
+		return q + sign
+	case RoundHalfEven:
+		if 2*rem > den || (2*rem == den && q%2 != 0) {
+			return q + sign
+		}
+		return q
+	default:
+		if 2*rem >= den {
+			return q + sign
+		}
+		return q
+	}
+}
+
+// roundHalfEvenUp reports whether minor should be incremented given the
+// decimal digits that follow it.
+func roundHalfEvenUp(minor int64, extra string) bool {
+	switch {
+	case extra[0] > '5':
+		return true
+	case extra[0] < '5':
+		return false
+	case strings.Trim(extra[1:], "0") != "":
+		return true
+	default:
+		return minor%2 != 0
+	}
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// OrderPricing is the authoritative, server-computed price of an order.
+type OrderPricing struct {
+	Items []models.OrderItem
+	// LineTaxes holds each item's share of Tax, summing exactly to Tax.
+	LineTaxes []models.Money
+	Subtotal  models.Money
+	Tax       models.Money
+	Shipping  models.Money
+	Total     models.Money
+	// TaxInclusive is true when Subtotal already contains Tax.
+	TaxInclusive bool
+	TaxLines     []TaxLine
+}
+
+// PriceOrder prices items from UnitPrice × Quantity, ignoring any line totals
+// supplied by the client, and adds tax from taxes and shipping. All
+// arithmetic is in integer minor units.
+func PriceOrder(ctx context.Context, req *models.CreateOrderRequest, taxes TaxCalculator, cfg config.PricingConfig) (*OrderPricing, error) {
+	items := req.Items
+	if len(items) == 0 {
+		return nil, errors.NewValidationError("items", "at least one item is required")
+	}
+
+	currency := items[0].UnitPrice.Currency
+	priced := make([]models.OrderItem, len(items))
+	var subtotal int64
+
+	for i, item := range items {
+		if item.UnitPrice.Currency != currency {
+			return nil, errors.NewValidationError("items", "all items must use the same currency")
+		}
+		item.Total = models.Money{
+			Amount:   item.UnitPrice.Amount * int64(item.Quantity),
+			Currency: currency,
+		}
+		subtotal += item.Total.Amount
+		priced[i] = item
+	}
+
+	taxResult, err := taxes.Calculate(ctx, &TaxRequest{
+		UserID:   req.UserID,
+		Address:  req.ShippingAddress,
+		Currency: currency,
+		Items:    priced,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Shipping is configured per currency: one amount in minor units means
+	// very different prices in, say, USD and JPY.
+	shipping := cfg.ShippingFlatRate[strings.ToUpper(currency)]
+	if threshold := cfg.FreeShippingThreshold[strings.ToUpper(currency)]; threshold > 0 && subtotal >= threshold {
+		shipping = 0
+	}
+
+	total := subtotal + shipping
+	if !taxResult.Inclusive {
+		total += taxResult.Tax.Amount
+	}
+
+	return &OrderPricing{
+		Items:        priced,
+		LineTaxes:    taxResult.LineTaxes,
+		Subtotal:     models.Money{Amount: subtotal, Currency: currency},
+		Tax:          taxResult.Tax,
+		Shipping:     models.Money{Amount: shipping, Currency: currency},
+		Total:        models.Money{Amount: total, Currency: currency},
+		TaxInclusive: taxResult.Inclusive,
+		TaxLines:     taxResult.Lines,
+	}, nil
+}
+
+// PriceMismatch describes one client-submitted amount that disagrees with
+// the server-computed price.
+type PriceMismatch struct {
+	Field     string       `json:"field"`
+	Expected  models.Money `json:"expected"`
+	Submitted models.Money `json:"submitted"`
+}
+
+// PriceMismatchError is returned when client totals differ from the
+// server-computed totals by more than the configured tolerance.
+type PriceMismatchError struct {
+	Mismatches []PriceMismatch `json:"mismatches"`
+	Tolerance  int64           `json:"tolerance"`
+}
+
+func (e *PriceMismatchError) Error() string {
+	m := e.Mismatches[0]
+	return fmt.Sprintf("price mismatch on %s: expected %d %s, got %d %s",
+		m.Field, m.Expected.Amount, m.Expected.Currency, m.Submitted.Amount, m.Submitted.Currency)
+}
+
+// CheckClientTotals compares the totals a client submitted against pricing.
+// Amounts the client left empty are not checked.
+func CheckClientTotals(req *models.CreateOrderRequest, pricing *OrderPricing, tolerance int64) error {
+	var mismatches []PriceMismatch
+
+	check := func(field string, submitted, expected models.Money) {
+		if submitted == (models.Money{}) {
+			return
+		}
+		diff := submitted.Amount - expected.Amount
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance || (submitted.Currency != "" && submitted.Currency != expected.Currency) {
+			mismatches = append(mismatches, PriceMismatch{
+				Field:     field,
+				Expected:  expected,
+				Submitted: submitted,
+			})
+		}
+	}
+
+	check("subtotal", req.Subtotal, pricing.Subtotal)
+	check("tax", req.Tax, pricing.Tax)
+	check("total", req.Total, pricing.Total)
+
+	if len(mismatches) > 0 {
+		return &PriceMismatchError{Mismatches: mismatches, Tolerance: tolerance}
+	}
+	return nil
 }
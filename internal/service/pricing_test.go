@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func testItems() []models.OrderItem {
+	return []models.OrderItem{
+		{ProductID: "prod_a", Quantity: 2, UnitPrice: models.Money{Amount: 1000, Currency: "USD"}},
+		{ProductID: "prod_b", Quantity: 1, UnitPrice: models.Money{Amount: 550, Currency: "USD"}, Total: models.Money{Amount: 1, Currency: "USD"}},
+	}
+}
+
+// flatTax charges a single exclusive rate, spread across items.
+type flatTax struct {
+	rate Rate
+}
+
+func (f flatTax) Calculate(ctx context.Context, req *TaxRequest) (*TaxResult, error) {
+	amounts := make([]int64, len(req.Items))
+	var subtotal int64
+	for i, item := range req.Items {
+		amounts[i] = item.Total.Amount
+		subtotal += item.Total.Amount
+	}
+
+	tax := f.rate.Apply(subtotal, RoundHalfUp)
+	result := &TaxResult{Tax: models.Money{Amount: tax, Currency: req.Currency}}
+	for _, share := range Allocate(tax, amounts) {
+		result.LineTaxes = append(result.LineTaxes, models.Money{Amount: share, Currency: req.Currency})
+	}
+	return result, nil
+}
+
+func testOrderRequest(items []models.OrderItem) *models.CreateOrderRequest {
+	return &models.CreateOrderRequest{UserID: "user_123", Items: items}
+}
+
+func TestPriceOrder(t *testing.T) {
+	pricing, err := PriceOrder(context.Background(), testOrderRequest(testItems()), flatTax{RateFromFloat(0.1)}, config.PricingConfig{ShippingFlatRate: map[string]int64{"USD": 500}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if pricing.Items[1].Total.Amount != 550 {
+		t.Errorf("Expected client line total to be recomputed as 550, got %d", pricing.Items[1].Total.Amount)
+	}
+	if pricing.Subtotal.Amount != 2550 {
+		t.Errorf("Expected subtotal 2550, got %d", pricing.Subtotal.Amount)
+	}
+	if pricing.Tax.Amount != 255 {
+		t.Errorf("Expected tax 255, got %d", pricing.Tax.Amount)
+	}
+	if pricing.Total.Amount != 3305 {
+		t.Errorf("Expected total 3305, got %d", pricing.Total.Amount)
+	}
+}
+
+func TestPriceOrder_FreeShipping(t *testing.T) {
+	pricing, err := PriceOrder(context.Background(), testOrderRequest(testItems()), flatTax{}, config.PricingConfig{
+		ShippingFlatRate:      map[string]int64{"USD": 500},
+		FreeShippingThreshold: map[string]int64{"USD": 2000},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pricing.Shipping.Amount != 0 {
+		t.Errorf("Expected free shipping above threshold, got %d", pricing.Shipping.Amount)
+	}
+}
+
+func TestPriceOrder_ShippingByCurrency(t *testing.T) {
+	cfg := config.PricingConfig{
+		ShippingFlatRate:      map[string]int64{"USD": 500, "JPY": 800},
+		FreeShippingThreshold: map[string]int64{"USD": 100000, "JPY": 3000},
+	}
+
+	items := []models.OrderItem{{ProductID: "prod_a", Quantity: 1, UnitPrice: models.Money{Amount: 2500, Currency: "JPY"}}}
+	pricing, err := PriceOrder(context.Background(), testOrderRequest(items), flatTax{}, cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pricing.Shipping != (models.Money{Amount: 800, Currency: "JPY"}) {
+		t.Errorf("Expected the JPY flat rate, got %v", pricing.Shipping)
+	}
+
+	items[0].UnitPrice.Currency = "EUR"
+	pricing, err = PriceOrder(context.Background(), testOrderRequest(items), flatTax{}, cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pricing.Shipping.Amount != 0 {
+		t.Errorf("Expected no shipping for a currency without a rate, got %v", pricing.Shipping)
+	}
+}
+
+func TestPriceOrder_MixedCurrencies(t *testing.T) {
+	items := testItems()
+	items[1].UnitPrice.Currency = "EUR"
+
+	if _, err := PriceOrder(context.Background(), testOrderRequest(items), flatTax{}, config.PricingConfig{}); err == nil {
+		t.Error("Expected error for mixed currencies")
+	}
+}
+
+func TestCheckClientTotals(t *testing.T) {
+	pricing, err := PriceOrder(context.Background(), testOrderRequest(testItems()), flatTax{RateFromFloat(0.1)}, config.PricingConfig{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		req      *models.CreateOrderRequest
+		mismatch bool
+	}{
+		{"no client totals", &models.CreateOrderRequest{}, false},
+		{"matching totals", &models.CreateOrderRequest{Total: models.Money{Amount: 2805, Currency: "USD"}}, false},
+		{"within tolerance", &models.CreateOrderRequest{Tax: models.Money{Amount: 256, Currency: "USD"}}, false},
+		{"tampered total", &models.CreateOrderRequest{Total: models.Money{Amount: 1, Currency: "USD"}}, true},
+		{"wrong currency", &models.CreateOrderRequest{Subtotal: models.Money{Amount: 2550, Currency: "EUR"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckClientTotals(tt.req, pricing, 1)
+			var mismatchErr *PriceMismatchError
+			if got := errors.As(err, &mismatchErr); got != tt.mismatch {
+				t.Errorf("Expected mismatch %v, got error %v", tt.mismatch, err)
+			}
+		})
+	}
+}
+
+func TestRateApply_RoundingModes(t *testing.T) {
+	rate := RateFromFloat(0.1)
+
+	tests := []struct {
+		amount int64
+		mode   RoundingMode
+		want   int64
+	}{
+		{25, RoundHalfUp, 3},
+		{25, RoundHalfEven, 2},
+		{35, RoundHalfEven, 4},
+		{-25, RoundHalfUp, -3},
+		{-25, RoundHalfEven, -2},
+		{29, RoundDown, 2},
+		{21, RoundUp, 3},
+	}
+
+	for _, tt := range tests {
+		if got := rate.Apply(tt.amount, tt.mode); got != tt.want {
+			t.Errorf("Apply(%d, %s) = %d, want %d", tt.amount, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMoney_CurrencyExponents(t *testing.T) {
+	tests := []struct {
+		money models.Money
+		want  string
+	}{
+		{models.Money{Amount: 1234, Currency: "USD"}, "12.34"},
+		{models.Money{Amount: 5, Currency: "EUR"}, "0.05"},
+		{models.Money{Amount: 1234, Currency: "JPY"}, "1234"},
+		{models.Money{Amount: 1234, Currency: "KWD"}, "1.234"},
+		{models.Money{Amount: -150, Currency: "USD"}, "-1.50"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatMoney(tt.money); got != tt.want {
+			t.Errorf("FormatMoney(%+v) = %s, want %s", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyFromMajor(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     int64
+	}{
+		{19.99, "USD", 1999},
+		{0.1 + 0.2, "USD", 30},
+		{1.005, "USD", 100},
+		{1.015, "USD", 102},
+		{1500, "JPY", 1500},
+		{2.5, "JPY", 2},
+		{1.2345, "KWD", 1234},
+	}
+
+	for _, tt := range tests {
+		if got := MoneyFromMajor(tt.amount, tt.currency); got.Amount != tt.want {
+			t.Errorf("MoneyFromMajor(%v, %s) = %d, want %d", tt.amount, tt.currency, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestAllocate_PreservesTotal(t *testing.T) {
+	tests := []struct {
+		total   int64
+		weights []int64
+	}{
+		{100, []int64{1, 1, 1}},
+		{1, []int64{333, 333, 334}},
+		{-7, []int64{2, 3}},
+		{255, []int64{2000, 550}},
+		{10, []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		var sum int64
+		for _, part := range Allocate(tt.total, tt.weights) {
+			sum += part
+		}
+		if sum != tt.total {
+			t.Errorf("Allocate(%d, %v) sums to %d", tt.total, tt.weights, sum)
+		}
+	}
+}
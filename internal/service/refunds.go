@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/clients"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// RefundEventPublisher publishes refund events. It complements
+// interfaces.OrderEventPublisher, which has no refund events.
+type RefundEventPublisher interface {
+	// PublishOrderRefunded announces a completed refund. refunded is the
+	// order's cumulative refunded amount including refund.
+	PublishOrderRefunded(ctx context.Context, order *models.Order, refund *repository.OrderRefund, refunded models.Money) error
+}
+
+// RefundOrderRequest is the body of POST /api/v2/orders/:id/refund.
+type RefundOrderRequest struct {
+	Reason string `json:"reason"`
+	// Items selects the order lines and quantities to refund. When empty,
+	// everything not yet refunded is refunded.
+	Items []RefundItemRequest `json:"items"`
+}
+
+// RefundItemRequest is a quantity of one order line to refund.
+type RefundItemRequest struct {
+	ItemID   string `json:"item_id"`
+	Quantity int    `json:"quantity"`
+}
+
+// ListRefunds returns an order's refunds, oldest first.
+func (s *OrderService) ListRefunds(ctx context.Context, orderID string) ([]*repository.OrderRefund, error) {
+	if _, err := s.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.refunds.ListByOrderID(ctx, orderID)
+}
+
+// RefundOrder refunds some or all of an order's items, with their share of
+// tax and shipping, against the captured payment. When version is not
+// repository.AnyVersion the order must still be at that version.
+//
+// The refund is recorded as pending before the payment service is called,
+// so concurrent refunds cannot together exceed the captured amount. Once
+// the refund completes the order moves to partially refunded or refunded
+// and an order.refunded event is published.
+func (s *OrderService) RefundOrder(ctx context.Context, orderID string, version int64, req *RefundOrderRequest) (*repository.OrderRefund, error) {
+	return s.refund(ctx, orderID, version, req, s.paymentRefund)
+}
+
+// refundFunc asks the payment service to refund amount of a payment.
+type refundFunc func(ctx context.Context, paymentID string, amount models.Money, reason string) (*models.RefundResponse, error)
+
+func (s *OrderService) paymentRefund(ctx context.Context, paymentID string, amount models.Money, reason string) (*models.RefundResponse, error) {
+	return s.paymentClient.Refund(ctx, &models.RefundRequest{
+		PaymentID: paymentID,
+		Amount:    amount,
+		Reason:    reason,
+	})
+}
+
+// refund is RefundOrder with the payment call supplied by the caller.
+func (s *OrderService) refund(ctx context.Context, orderID string, version int64, req *RefundOrderRequest, pay refundFunc) (*repository.OrderRefund, error) {
+	s.logger.Info("Processing order refund", logging.Fields{
+		"order_id":   orderID,
+		"reason":     req.Reason,
+		"item_count": len(req.Items),
+	})
+
+	current, err := s.orderRepo.GetVersioned(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(current, version); err != nil {
+		return nil, err
+	}
+	order := current.Order
+
+	if !canRefund(order) {
+		return nil, errors.NewValidationError("status", "order cannot be refunded")
+	}
+
+	previous, err := s.refunds.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	taxLines, err := s.taxLines.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := planRefund(order, exclusiveTax(order, taxLines), previous, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	refund.PaymentID = order.PaymentID
+	refund.Reason = req.Reason
+	refund.Status = models.PaymentStatusPending
+
+	// Reserve the amount; bumping the order version makes a concurrent
+	// refund planned from the same state fail.
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.TouchAt(ctx, current.ID, current.Version); err != nil {
+			return err
+		}
+		s.invalidateOrder(ctx, current.ID)
+		return s.refunds.Create(ctx, refund)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refundResp, err := pay(ctx, order.PaymentID, refund.Amount, req.Reason)
+	if err != nil {
+		s.logger.Error("Refund processing failed", logging.Fields{
+			"order_id":   orderID,
+			"payment_id": order.PaymentID,
+			"refund_id":  refund.ID,
+			"error":      err.Error(),
+		})
+		if !refundNotProcessed(err) {
+			// The payment service may have refunded the money before the
+			// call failed, so the amount stays reserved until the refund's
+			// webhook or event settles it.
+			return refund, nil
+		}
+		refund.Status = models.PaymentStatusFailed
+		if updateErr := s.refunds.UpdateStatus(ctx, refund); updateErr != nil {
+			s.logger.Error("Failed to release refund", logging.Fields{
+				"refund_id": refund.ID,
+				"error":     updateErr.Error(),
+			})
+		}
+		return nil, err
+	}
+
+	// Record the payment service's reference before anything else, so the
+	// refund can still be settled from the payment's webhook or event if
+	// settling it here fails.
+	refund.RefundID = refundResp.RefundID
+	if err := s.refunds.UpdateStatus(ctx, refund); err != nil {
+		s.logger.Error("Failed to record refund reference", logging.Fields{
+			"order_id":       orderID,
+			"refund_id":      refund.ID,
+			"payment_refund": refund.RefundID,
+			"error":          err.Error(),
+		})
+		return nil, err
+	}
+
+	if refundResp.Status == models.PaymentStatusPending {
+		return refund, nil
+	}
+
+	settled := *refund
+	settled.Status = refundResp.Status
+	if err := s.settleRefund(ctx, orderID, &settled); err != nil {
+		// The payment service has accepted the refund, so it is reported as
+		// pending rather than failed; the payment's refund webhook or event
+		// settles it.
+		s.logger.Error("Failed to record refund outcome", logging.Fields{
+			"order_id":        orderID,
+			"refund_id":       refund.ID,
+			"payment_refund":  refund.RefundID,
+			"payment_status":  refundResp.Status,
+			"refund_amount":   refund.Amount.Amount,
+			"refund_currency": refund.Amount.Currency,
+			"error":           err.Error(),
+		})
+		return refund, nil
+	}
+
+	return &settled, nil
+}
+
+// refundNotProcessed reports whether err from the payment service means it
+// definitely did not refund anything: it rejected the request, or the call
+// was never made because its circuit breaker or bulkhead refused it.
+// Timeouts, transport errors and server errors leave the outcome unknown.
+func refundNotProcessed(err error) bool {
+	var validation *errors.ValidationError
+	return clients.IsRejected(err) || clients.IsUnavailable(err) || stderrors.As(err, &validation)
+}
+
+// ReconcileRefund settles a pending refund of orderID from the status the
+// payment service reports for it in a webhook or event. A refund whose call
+// failed without an answer has no refund ID yet; when it is the order's only
+// such refund it is matched to refundID. It reports whether refundID belongs
+// to one of our refunds; refunds already settled are left alone.
+func (s *OrderService) ReconcileRefund(ctx context.Context, orderID, refundID string, reported models.PaymentStatus) (bool, error) {
+	status := refundOutcome(reported)
+	refund, err := s.refunds.GetByRefundID(ctx, refundID)
+	if err != nil {
+		return false, err
+	}
+	if refund == nil {
+		refund, err = s.unreferencedRefund(ctx, orderID)
+		if err != nil || refund == nil {
+			return false, err
+		}
+		refund.RefundID = refundID
+		if status == models.PaymentStatusPending {
+			// Still pending; keep the reference for the final outcome.
+			return true, s.refunds.UpdateStatus(ctx, refund)
+		}
+	}
+	if refund.Status != models.PaymentStatusPending || status == models.PaymentStatusPending {
+		return true, nil
+	}
+
+	s.logger.Info("Reconciling refund", logging.Fields{
+		"order_id":       refund.OrderID,
+		"refund_id":      refund.ID,
+		"payment_refund": refundID,
+		"status":         status,
+	})
+	refund.Status = status
+	return true, s.settleRefund(ctx, refund.OrderID, refund)
+}
+
+// unreferencedRefund returns the order's only pending refund without a
+// refund ID, or nil if there is none or more than one.
+func (s *OrderService) unreferencedRefund(ctx context.Context, orderID string) (*repository.OrderRefund, error) {
+	if orderID == "" {
+		return nil, nil
+	}
+	refunds, err := s.refunds.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *repository.OrderRefund
+	for _, refund := range refunds {
+		if refund.Status != models.PaymentStatusPending || refund.RefundID != "" {
+			continue
+		}
+		if found != nil {
+			return nil, nil
+		}
+		found = refund
+	}
+	return found, nil
+}
+
+// refundOutcome maps the payment status reported for a refund to the status
+// of the refund: failed and cancelled refunds failed, pending ones are still
+// pending and anything else has been refunded.
+func refundOutcome(status models.PaymentStatus) models.PaymentStatus {
+	switch status {
+	case models.PaymentStatusFailed, models.PaymentStatusCancelled:
+		return models.PaymentStatusFailed
+	case models.PaymentStatusPending:
+		return models.PaymentStatusPending
+	default:
+		return models.PaymentStatusRefunded
+	}
+}
+
+// settleRefundAttempts bounds how often settling a completed refund is
+// retried after the order changed concurrently.
+const settleRefundAttempts = 3
+
+// settleRefund stores the payment service's outcome of a pending refund. A
+// completed refund moves the order to partially refunded, or refunded once
+// the whole captured amount has been returned. The outcome comes from the
+// payment service and cannot be rejected, so a version conflict is retried
+// against the latest order.
+func (s *OrderService) settleRefund(ctx context.Context, orderID string, refund *repository.OrderRefund) error {
+	if refund.Status != models.PaymentStatusRefunded {
+		// Failed, and no longer counted against the order.
+		err := s.refunds.UpdateStatus(ctx, refund)
+		if stderrors.Is(err, repository.ErrVersionConflict) {
+			// Already settled through another channel.
+			return nil
+		}
+		return err
+	}
+
+	var err error
+	for attempt := 0; attempt < settleRefundAttempts; attempt++ {
+		err = s.settleCompletedRefund(ctx, orderID, refund)
+		if !stderrors.Is(err, repository.ErrVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *OrderService) settleCompletedRefund(ctx context.Context, orderID string, refund *repository.OrderRefund) error {
+	current, err := s.orderRepo.GetVersioned(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	refunds, err := s.refunds.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, stored := range refunds {
+		if stored.ID == refund.ID && stored.Status != models.PaymentStatusPending {
+			// Already settled through another channel.
+			return nil
+		}
+	}
+
+	refunded := refundedAmount(refunds, refund)
+	target := OrderStatusPartiallyRefunded
+	if refunded.Amount >= current.Total.Amount {
+		target = models.OrderStatusRefunded
+	}
+
+	save := func(ctx context.Context, order *models.Order) error {
+		if err := s.refunds.UpdateStatus(ctx, refund); err != nil {
+			return err
+		}
+		return s.publishOrderRefunded(ctx, order, refund, refunded)
+	}
+
+	if err := s.saveWithStatus(ctx, current, target, "Refund processed: "+refund.Reason, save); err != nil {
+		return err
+	}
+	recordRefund(refund.Amount)
+	return nil
+}
+
+// canRefund reports whether order has a captured payment with a balance
+// that may still be refunded.
+func canRefund(order *models.Order) bool {
+	if order.Status == OrderStatusPartiallyRefunded {
+		return order.PaymentID != ""
+	}
+	return order.CanRefund()
+}
+
+// planRefund works out what refunding items returns to the customer, given
+// the order's previous refunds. Line totals and tax are prorated by quantity
+// and shipping by the share of the subtotal refunded. Each share is computed
+// as a difference of cumulative amounts, so refunding everything piece by
+// piece returns exactly the order total. Empty items refunds every quantity
+// not yet refunded. exclusive is the part of the order's tax charged on top
+// of its prices; only that share of the refunded tax is added to the amount.
+func planRefund(order *models.Order, exclusive int64, previous []*repository.OrderRefund, items []RefundItemRequest) (*repository.OrderRefund, error) {
+	currency := order.Total.Currency
+
+	lineTotals := make([]int64, len(order.Items))
+	lines := make(map[string]int, len(order.Items))
+	for i, item := range order.Items {
+		lineTotals[i] = item.Total.Amount
+		lines[orderLineID(item, i)] = i
+	}
+	lineTaxes := Allocate(order.Tax.Amount, lineTotals)
+
+	refundedQty := refundedQuantities(previous)
+	var refundedSubtotal, refundedTax, refundedTotal int64
+	for _, refund := range previous {
+		if refund.Status == models.PaymentStatusFailed {
+			continue
+		}
+		refundedSubtotal += refund.Subtotal.Amount
+		refundedTax += refund.Tax.Amount
+		refundedTotal += refund.Amount.Amount
+	}
+
+	if len(items) == 0 {
+		for i, item := range order.Items {
+			id := orderLineID(item, i)
+			if left := item.Quantity - refundedQty[id]; left > 0 {
+				items = append(items, RefundItemRequest{ItemID: id, Quantity: left})
+			}
+		}
+		if len(items) == 0 {
+			return nil, errors.NewValidationError("items", "order has already been fully refunded")
+		}
+	}
+
+	refund := &repository.OrderRefund{
+		OrderID:  order.ID,
+		Items:    make([]repository.RefundItem, 0, len(items)),
+		Subtotal: models.Money{Currency: currency},
+		Tax:      models.Money{Currency: currency},
+		Shipping: models.Money{Currency: currency},
+		Amount:   models.Money{Currency: currency},
+	}
+
+	for n, req := range items {
+		field := fmt.Sprintf("items[%d]", n)
+		i, ok := lines[req.ItemID]
+		if !ok {
+			return nil, errors.NewValidationError(field, "unknown order item "+req.ItemID)
+		}
+		if req.Quantity <= 0 {
+			return nil, errors.NewValidationError(field, "quantity must be positive")
+		}
+
+		line := order.Items[i]
+		before := refundedQty[req.ItemID]
+		after := before + req.Quantity
+		if after > line.Quantity {
+			return nil, errors.NewValidationError(field, fmt.Sprintf(
+				"only %d of item %s remain to be refunded", line.Quantity-before, req.ItemID))
+		}
+		refundedQty[req.ItemID] = after
+
+		qty := int64(line.Quantity)
+		amount := prorate(line.Total.Amount, int64(after), qty) - prorate(line.Total.Amount, int64(before), qty)
+		tax := prorate(lineTaxes[i], int64(after), qty) - prorate(lineTaxes[i], int64(before), qty)
+
+		refund.Items = append(refund.Items, repository.RefundItem{
+			ItemID:    req.ItemID,
+			ProductID: line.ProductID,
+			Quantity:  req.Quantity,
+			Amount:    models.Money{Amount: amount, Currency: currency},
+			Tax:       models.Money{Amount: tax, Currency: currency},
+		})
+		refund.Subtotal.Amount += amount
+		refund.Tax.Amount += tax
+	}
+
+	shipping := order.ShippingCost.Amount
+	refund.Shipping.Amount = prorate(shipping, refundedSubtotal+refund.Subtotal.Amount, order.Subtotal.Amount) -
+		prorate(shipping, refundedSubtotal, order.Subtotal.Amount)
+
+	refund.Amount.Amount = refund.Subtotal.Amount + refund.Shipping.Amount +
+		prorate(exclusive, refundedTax+refund.Tax.Amount, order.Tax.Amount) -
+		prorate(exclusive, refundedTax, order.Tax.Amount)
+
+	if refund.Amount.Amount <= 0 {
+		return nil, errors.NewValidationError("items", "refund amount must be positive")
+	}
+	if refundedTotal+refund.Amount.Amount > order.Total.Amount {
+		return nil, errors.NewValidationError("items", fmt.Sprintf(
+			"refund of %s exceeds the %s left to refund",
+			FormatMoney(refund.Amount),
+			FormatMoney(models.Money{Amount: order.Total.Amount - refundedTotal, Currency: currency}),
+		))
+	}
+
+	return refund, nil
+}
+
+// refundedQuantities returns the quantity of each order line covered by
+// refunds that have not failed.
+func refundedQuantities(refunds []*repository.OrderRefund) map[string]int {
+	quantities := make(map[string]int)
+	for _, refund := range refunds {
+		if refund.Status == models.PaymentStatusFailed {
+			continue
+		}
+		for _, item := range refund.Items {
+			quantities[item.ItemID] += item.Quantity
+		}
+	}
+	return quantities
+}
+
+// prorate returns part/whole of total, rounded half up.
+func prorate(total, part, whole int64) int64 {
+	if whole == 0 {
+		return 0
+	}
+	return divRound(total*part, whole, RoundHalfUp)
+}
+
+// exclusiveTax returns the part of an order's tax charged on top of its
+// prices, from the inclusive flag stored with each tax line. Orders stored
+// without tax lines were priced tax exclusive.
+func exclusiveTax(order *models.Order, lines []*repository.OrderTaxLine) int64 {
+	if len(lines) == 0 {
+		return order.Tax.Amount
+	}
+	var tax int64
+	for _, line := range lines {
+		if !line.Inclusive {
+			tax += line.Tax.Amount
+		}
+	}
+	return tax
+}
+
+// refundedAmount sums the completed refunds, counting settled as completed.
+func refundedAmount(refunds []*repository.OrderRefund, settled *repository.OrderRefund) models.Money {
+	total := models.Money{Amount: settled.Amount.Amount, Currency: settled.Amount.Currency}
+	for _, refund := range refunds {
+		if refund.ID != settled.ID && refund.Status == models.PaymentStatusRefunded {
+			total.Amount += refund.Amount.Amount
+		}
+	}
+	return total
+}
+
+func (s *OrderService) publishOrderRefunded(ctx context.Context, order *models.Order, refund *repository.OrderRefund, refunded models.Money) error {
+	if !s.config.Features.EnableOrderEvents {
+		return nil
+	}
+	return s.refundEvents.PublishOrderRefunded(ctx, order, refund, refunded)
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/clients"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func usd(amount int64) models.Money {
+	return models.Money{Amount: amount, Currency: "USD"}
+}
+
+// refundTestOrder is 3 × 3.33 + 1 × 10.00 with 8.25% tax and 5.00 shipping.
+func refundTestOrder() *models.Order {
+	return &models.Order{
+		ID:        "ord_1",
+		Status:    models.OrderStatusDelivered,
+		PaymentID: "pay_1",
+		Items: []models.OrderItem{
+			{ID: "line_1", ProductID: "prod_a", Quantity: 3, UnitPrice: usd(333), Total: usd(999)},
+			{ID: "line_2", ProductID: "prod_b", Quantity: 1, UnitPrice: usd(1000), Total: usd(1000)},
+		},
+		Subtotal:     usd(1999),
+		Tax:          usd(165),
+		ShippingCost: usd(500),
+		Total:        usd(2664),
+	}
+}
+
+func TestPlanRefundFullByDefault(t *testing.T) {
+	order := refundTestOrder()
+
+	refund, err := planRefund(order, order.Tax.Amount, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if refund.Amount != order.Total {
+		t.Errorf("expected full refund of %v, got %v", order.Total, refund.Amount)
+	}
+	if refund.Tax != order.Tax || refund.Shipping != order.ShippingCost {
+		t.Errorf("expected all tax and shipping, got tax %v shipping %v", refund.Tax, refund.Shipping)
+	}
+	if len(refund.Items) != 2 || refund.Items[0].Quantity != 3 || refund.Items[1].Quantity != 1 {
+		t.Errorf("expected every line refunded, got %+v", refund.Items)
+	}
+}
+
+func TestPlanRefundProratesAndAddsUp(t *testing.T) {
+	order := refundTestOrder()
+	var previous []*repository.OrderRefund
+	var total int64
+
+	// Refund one unit at a time; the pieces must add up to the order total.
+	steps := []RefundItemRequest{
+		{ItemID: "line_1", Quantity: 1},
+		{ItemID: "line_2", Quantity: 1},
+		{ItemID: "line_1", Quantity: 1},
+		{ItemID: "line_1", Quantity: 1},
+	}
+	for i, step := range steps {
+		refund, err := planRefund(order, order.Tax.Amount, previous, []RefundItemRequest{step})
+		if err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if got := refund.Subtotal.Amount + refund.Tax.Amount + refund.Shipping.Amount; got != refund.Amount.Amount {
+			t.Errorf("step %d: parts %d do not add up to amount %d", i, got, refund.Amount.Amount)
+		}
+		refund.Status = models.PaymentStatusRefunded
+		previous = append(previous, refund)
+		total += refund.Amount.Amount
+	}
+
+	if first := previous[0]; first.Subtotal.Amount != 333 || first.Shipping.Amount != 83 {
+		t.Errorf("expected first refund of 333 + 83 shipping, got %+v", first)
+	}
+	if total != order.Total.Amount {
+		t.Errorf("expected refunds to total %d, got %d", order.Total.Amount, total)
+	}
+
+	if _, err := planRefund(order, order.Tax.Amount, previous, nil); err == nil {
+		t.Error("expected an error refunding a fully refunded order")
+	}
+}
+
+func TestPlanRefundIgnoresFailedRefunds(t *testing.T) {
+	order := refundTestOrder()
+	failed, err := planRefund(order, order.Tax.Amount, nil, []RefundItemRequest{{ItemID: "line_2", Quantity: 1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failed.Status = models.PaymentStatusFailed
+
+	if _, err := planRefund(order, order.Tax.Amount, []*repository.OrderRefund{failed}, []RefundItemRequest{{ItemID: "line_2", Quantity: 1}}); err != nil {
+		t.Errorf("expected failed refund to be ignored, got %v", err)
+	}
+}
+
+func TestPlanRefundTaxInclusive(t *testing.T) {
+	order := refundTestOrder()
+	order.Total = usd(order.Subtotal.Amount + order.ShippingCost.Amount)
+	lines := []*repository.OrderTaxLine{{Inclusive: true, Tax: order.Tax}}
+
+	refund, err := planRefund(order, exclusiveTax(order, lines), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refund.Amount != order.Total {
+		t.Errorf("expected tax not to be refunded on top, got %v", refund.Amount)
+	}
+}
+
+func TestPlanRefundRejectsInvalidItems(t *testing.T) {
+	order := refundTestOrder()
+
+	tests := []struct {
+		name  string
+		items []RefundItemRequest
+	}{
+		{"unknown item", []RefundItemRequest{{ItemID: "line_9", Quantity: 1}}},
+		{"zero quantity", []RefundItemRequest{{ItemID: "line_1", Quantity: 0}}},
+		{"too many", []RefundItemRequest{{ItemID: "line_1", Quantity: 4}}},
+		{"too many across entries", []RefundItemRequest{
+			{ItemID: "line_2", Quantity: 1},
+			{ItemID: "line_2", Quantity: 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		_, err := planRefund(order, order.Tax.Amount, nil, tt.items)
+		if _, ok := err.(*errors.ValidationError); !ok {
+			t.Errorf("%s: expected validation error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestExclusiveTax(t *testing.T) {
+	order := refundTestOrder()
+
+	if got := exclusiveTax(order, nil); got != order.Tax.Amount {
+		t.Errorf("expected orders without tax lines to be tax exclusive, got %d", got)
+	}
+
+	lines := []*repository.OrderTaxLine{
+		{Inclusive: true, Tax: usd(100)},
+		{Inclusive: false, Tax: usd(65)},
+	}
+	if got := exclusiveTax(order, lines); got != 65 {
+		t.Errorf("expected only the exclusive lines, got %d", got)
+	}
+
+	// Only the exclusive share of the refunded tax is added on top.
+	refund, err := planRefund(order, 65, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := order.Subtotal.Amount + order.ShippingCost.Amount + 65; refund.Amount.Amount != want {
+		t.Errorf("expected %d, got %d", want, refund.Amount.Amount)
+	}
+}
+
+func TestRefundOutcome(t *testing.T) {
+	tests := map[models.PaymentStatus]models.PaymentStatus{
+		models.PaymentStatusRefunded:  models.PaymentStatusRefunded,
+		models.PaymentStatusCompleted: models.PaymentStatusRefunded,
+		"":                            models.PaymentStatusRefunded,
+		models.PaymentStatusPending:   models.PaymentStatusPending,
+		models.PaymentStatusFailed:    models.PaymentStatusFailed,
+		models.PaymentStatusCancelled: models.PaymentStatusFailed,
+	}
+	for reported, want := range tests {
+		if got := refundOutcome(reported); got != want {
+			t.Errorf("refundOutcome(%q) = %s, want %s", reported, got, want)
+		}
+	}
+}
+
+type memoryRefunds struct {
+	refunds []*repository.OrderRefund
+}
+
+func (m *memoryRefunds) Create(ctx context.Context, refund *repository.OrderRefund) error {
+	refund.ID = fmt.Sprintf("refund_%d", len(m.refunds)+1)
+	stored := *refund
+	m.refunds = append(m.refunds, &stored)
+	return nil
+}
+
+func (m *memoryRefunds) ListByOrderID(ctx context.Context, orderID string) ([]*repository.OrderRefund, error) {
+	var refunds []*repository.OrderRefund
+	for _, refund := range m.refunds {
+		if refund.OrderID == orderID {
+			stored := *refund
+			refunds = append(refunds, &stored)
+		}
+	}
+	return refunds, nil
+}
+
+func (m *memoryRefunds) GetByRefundID(ctx context.Context, refundID string) (*repository.OrderRefund, error) {
+	for _, refund := range m.refunds {
+		if refund.RefundID == refundID {
+			stored := *refund
+			return &stored, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryRefunds) UpdateStatus(ctx context.Context, refund *repository.OrderRefund) error {
+	for _, stored := range m.refunds {
+		if stored.ID == refund.ID {
+			if stored.Status != models.PaymentStatusPending {
+				return repository.ErrVersionConflict
+			}
+			stored.Status = refund.Status
+			stored.RefundID = refund.RefundID
+			return nil
+		}
+	}
+	return errors.ErrNotFound
+}
+
+type noTaxLines struct{}
+
+func (noTaxLines) Insert(ctx context.Context, lines []*repository.OrderTaxLine) error {
+	return nil
+}
+
+func (noTaxLines) ListByOrderID(ctx context.Context, orderID string) ([]*repository.OrderTaxLine, error) {
+	return nil, nil
+}
+
+func newRefundTestService() (*OrderService, *memoryOrderStore, *memoryRefunds) {
+	order := refundTestOrder()
+	orders := newMemoryOrderStore(order)
+	orders.orders[order.ID].PaymentStatus = models.PaymentStatusCompleted
+
+	refunds := &memoryRefunds{}
+	s := newPaymentEventsTestService(orders, inlineTx{})
+	s.refunds = refunds
+	s.taxLines = noTaxLines{}
+	return s, orders, refunds
+}
+
+func TestRefundFailureKeepsUnknownOutcomesPending(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		pending bool
+	}{
+		{"timeout", context.DeadlineExceeded, true},
+		{"server error", &clients.StatusError{Service: "refund", StatusCode: 503}, true},
+		{"rejected", &clients.StatusError{Service: "refund", StatusCode: 422}, false},
+		{"circuit open", fmt.Errorf("payment service: %w", clients.ErrCircuitOpen), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, refunds := newRefundTestService()
+			pay := func(ctx context.Context, paymentID string, amount models.Money, reason string) (*models.RefundResponse, error) {
+				return nil, tt.err
+			}
+
+			refund, err := s.refund(context.Background(), "ord_1", repository.AnyVersion, &RefundOrderRequest{Reason: "damaged"}, pay)
+			stored := refunds.refunds[0].Status
+
+			if tt.pending {
+				if err != nil || refund == nil || refund.Status != models.PaymentStatusPending {
+					t.Fatalf("Expected a pending refund, got %+v, %v", refund, err)
+				}
+				if stored != models.PaymentStatusPending {
+					t.Errorf("Expected the refund to stay reserved, stored as %s", stored)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected the rejection to be returned")
+			}
+			if stored != models.PaymentStatusFailed {
+				t.Errorf("Expected the refund to be released, stored as %s", stored)
+			}
+		})
+	}
+}
+
+func TestReconcileRefundMatchesUnreferencedRefund(t *testing.T) {
+	s, orders, refunds := newRefundTestService()
+	pay := func(ctx context.Context, paymentID string, amount models.Money, reason string) (*models.RefundResponse, error) {
+		return nil, context.DeadlineExceeded
+	}
+	if _, err := s.refund(context.Background(), "ord_1", repository.AnyVersion, &RefundOrderRequest{Reason: "damaged"}, pay); err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+
+	reconciled, err := s.ReconcileRefund(context.Background(), "ord_1", "ref_1", models.PaymentStatusRefunded)
+	if err != nil || !reconciled {
+		t.Fatalf("Expected the refund to be reconciled, got %v, %v", reconciled, err)
+	}
+	if stored := refunds.refunds[0]; stored.Status != models.PaymentStatusRefunded || stored.RefundID != "ref_1" {
+		t.Errorf("Expected refund ref_1 to be settled, got %s %q", stored.Status, stored.RefundID)
+	}
+	if status := orders.orders["ord_1"].Status; status != models.OrderStatusRefunded {
+		t.Errorf("Expected the order to be refunded, got %s", status)
+	}
+}
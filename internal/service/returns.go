@@ -0,0 +1,506 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/interfaces"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// Notification types sent through the returns workflow. The shared models
+// only define order notifications.
+const (
+	NotificationTypeReturnRequested models.NotificationType = "return_requested"
+	NotificationTypeReturnApproved  models.NotificationType = "return_approved"
+	NotificationTypeReturnReceived  models.NotificationType = "return_received"
+	NotificationTypeReturnInspected models.NotificationType = "return_inspected"
+	NotificationTypeReturnRefunded  models.NotificationType = "return_refunded"
+	NotificationTypeReturnRejected  models.NotificationType = "return_rejected"
+)
+
+// CreateReturnRequest is the body of POST /api/v2/orders/:id/returns.
+type CreateReturnRequest struct {
+	Reason string              `json:"reason"`
+	Items  []ReturnItemRequest `json:"items"`
+}
+
+// ReturnItemRequest is a quantity of one order line.
+type ReturnItemRequest struct {
+	ItemID   string `json:"item_id"`
+	Quantity int    `json:"quantity"`
+}
+
+// ReturnItemsRequest is the body of the receive and inspect steps. Items
+// holds the received, or accepted, quantity of each line; lines left out
+// count as zero. When Items is empty every unit from the previous step is
+// carried over.
+type ReturnItemsRequest struct {
+	Items []ReturnItemRequest `json:"items"`
+	Notes string              `json:"notes"`
+}
+
+// RejectReturnRequest is the body of POST .../returns/:return_id/reject.
+type RejectReturnRequest struct {
+	Reason string `json:"reason"`
+}
+
+// returnTransitions lists the allowed return status changes.
+var returnTransitions = map[repository.ReturnStatus][]repository.ReturnStatus{
+	repository.ReturnStatusRequested: {repository.ReturnStatusApproved, repository.ReturnStatusRejected},
+	repository.ReturnStatusApproved:  {repository.ReturnStatusReceived, repository.ReturnStatusRejected},
+	repository.ReturnStatusReceived:  {repository.ReturnStatusInspected, repository.ReturnStatusRejected},
+	repository.ReturnStatusInspected: {repository.ReturnStatusRefunded, repository.ReturnStatusRejected},
+}
+
+// ReturnService runs the returns (RMA) workflow: a customer asks to send
+// back delivered items, and the return is approved, received, inspected
+// and then refunded or rejected.
+type ReturnService struct {
+	orders             *OrderService
+	payments           *PaymentService
+	returns            repository.ReturnRepository
+	notificationClient interfaces.NotificationSender
+	config             *config.Config
+	logger             *logging.LoggerV2
+	now                func() time.Time
+}
+
+// NewReturnService creates a new return service. Refunds for accepted items
+// are recorded against the order by orders and paid through payments.
+func NewReturnService(
+	orders *OrderService,
+	payments *PaymentService,
+	returns repository.ReturnRepository,
+	notificationClient interfaces.NotificationSender,
+	cfg *config.Config,
+) *ReturnService {
+	return &ReturnService{
+		orders:             orders,
+		payments:           payments,
+		returns:            returns,
+		notificationClient: notificationClient,
+		config:             cfg,
+		logger:             logging.NewLoggerV2("return-service"),
+		now:                time.Now,
+	}
+}
+
+// ListReturns returns an order's returns, oldest first.
+func (s *ReturnService) ListReturns(ctx context.Context, orderID string) ([]*repository.Return, error) {
+	if _, err := s.orders.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.returns.ListByOrderID(ctx, orderID)
+}
+
+// GetReturn returns one return of an order.
+func (s *ReturnService) GetReturn(ctx context.Context, orderID, returnID string) (*repository.Return, error) {
+	return s.returns.GetByID(ctx, orderID, returnID)
+}
+
+// RequestReturn opens a return for some of a delivered order's items. The
+// order must be within the return window, and no more units of a line can
+// be returned than have not already been refunded or put in an open return.
+func (s *ReturnService) RequestReturn(ctx context.Context, orderID string, req *CreateReturnRequest) (*repository.Return, error) {
+	s.logger.Info("Requesting return", logging.Fields{
+		"order_id":   orderID,
+		"item_count": len(req.Items),
+	})
+
+	current, err := s.orders.orderRepo.GetVersioned(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order := current.Order
+
+	if err := checkReturnWindow(order, s.config.Returns.Window, s.now()); err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.orders.refunds.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.returns.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := checkReturnItems(order, refunds, existing, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &repository.Return{
+		OrderID: orderID,
+		UserID:  order.UserID,
+		Status:  repository.ReturnStatusRequested,
+		Reason:  req.Reason,
+		Items:   items,
+	}
+
+	// Bump the order version with the insert so two concurrent requests
+	// cannot both claim the same units.
+	save := func(ctx context.Context, order *models.Order) error {
+		return s.returns.Create(ctx, ret)
+	}
+	if err := s.orders.saveWithStatus(ctx, current, "", "", save); err != nil {
+		return nil, err
+	}
+
+	go s.sendReturnNotification(context.Background(), ret)
+	return ret, nil
+}
+
+// ApproveReturn authorises the customer to send the items back.
+func (s *ReturnService) ApproveReturn(ctx context.Context, orderID, returnID string) (*repository.Return, error) {
+	return s.advance(ctx, orderID, returnID, repository.ReturnStatusApproved, func(ret *repository.Return, now time.Time) error {
+		ret.ApprovedAt = &now
+		return nil
+	})
+}
+
+// ReceiveReturn records the quantities that arrived at the warehouse.
+func (s *ReturnService) ReceiveReturn(ctx context.Context, orderID, returnID string, req *ReturnItemsRequest) (*repository.Return, error) {
+	return s.advance(ctx, orderID, returnID, repository.ReturnStatusReceived, func(ret *repository.Return, now time.Time) error {
+		err := setReturnQuantities(ret.Items, req.Items,
+			func(item repository.ReturnItem) int { return item.Quantity },
+			func(item *repository.ReturnItem, n int) { item.ReceivedQuantity = n })
+		if err != nil {
+			return err
+		}
+		if countReturnUnits(ret.Items, func(item repository.ReturnItem) int { return item.ReceivedQuantity }) == 0 {
+			return errors.NewValidationError("items", "nothing was received; reject the return instead")
+		}
+		ret.ReceivedAt = &now
+		if req.Notes != "" {
+			ret.Notes = req.Notes
+		}
+		return nil
+	})
+}
+
+// InspectReturn records the quantities that passed inspection and will be
+// refunded.
+func (s *ReturnService) InspectReturn(ctx context.Context, orderID, returnID string, req *ReturnItemsRequest) (*repository.Return, error) {
+	return s.advance(ctx, orderID, returnID, repository.ReturnStatusInspected, func(ret *repository.Return, now time.Time) error {
+		err := setReturnQuantities(ret.Items, req.Items,
+			func(item repository.ReturnItem) int { return item.ReceivedQuantity },
+			func(item *repository.ReturnItem, n int) { item.AcceptedQuantity = n })
+		if err != nil {
+			return err
+		}
+		ret.InspectedAt = &now
+		if req.Notes != "" {
+			ret.Notes = req.Notes
+		}
+		return nil
+	})
+}
+
+// RejectReturn closes a return without a refund.
+func (s *ReturnService) RejectReturn(ctx context.Context, orderID, returnID string, req *RejectReturnRequest) (*repository.Return, error) {
+	return s.advance(ctx, orderID, returnID, repository.ReturnStatusRejected, func(ret *repository.Return, now time.Time) error {
+		ret.Notes = req.Reason
+		ret.ClosedAt = &now
+		return nil
+	})
+}
+
+// RefundReturn refunds the accepted units of an inspected return, with their
+// share of tax and shipping, through PaymentService.ProcessRefund. The
+// refund is recorded against the order like any other, so the order moves
+// to partially refunded or refunded.
+func (s *ReturnService) RefundReturn(ctx context.Context, orderID, returnID string) (*repository.Return, error) {
+	s.logger.Info("Refunding return", logging.Fields{
+		"order_id":  orderID,
+		"return_id": returnID,
+	})
+
+	ret, err := s.returns.GetByID(ctx, orderID, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := ret.Status
+	if !returnTransitionAllowed(from, repository.ReturnStatusRefunded) {
+		return nil, errors.NewValidationError("status", fmt.Sprintf(
+			"invalid return status transition from %s to %s", from, repository.ReturnStatusRefunded))
+	}
+
+	var items []RefundItemRequest
+	for _, item := range ret.Items {
+		if item.AcceptedQuantity > 0 {
+			items = append(items, RefundItemRequest{ItemID: item.ItemID, Quantity: item.AcceptedQuantity})
+		}
+	}
+	if len(items) == 0 {
+		return nil, errors.NewValidationError("items", "no items were accepted; reject the return instead")
+	}
+
+	// Close the return before paying out so a concurrent call cannot refund
+	// it twice; reopen it if the refund fails.
+	now := s.now()
+	ret.Status = repository.ReturnStatusRefunded
+	ret.ClosedAt = &now
+	if err := s.returns.Update(ctx, ret, from); err != nil {
+		return nil, err
+	}
+
+	refund, err := s.orders.refund(ctx, orderID, repository.AnyVersion, &RefundOrderRequest{
+		Reason: "Return " + ret.ID + ": " + ret.Reason,
+		Items:  items,
+	}, s.payments.ProcessRefund)
+	if err != nil {
+		ret.Status = from
+		ret.ClosedAt = nil
+		if reopenErr := s.returns.Update(ctx, ret, repository.ReturnStatusRefunded); reopenErr != nil {
+			s.logger.Error("Failed to reopen return after refund failure", logging.Fields{
+				"return_id": ret.ID,
+				"error":     reopenErr.Error(),
+			})
+		}
+		return nil, err
+	}
+
+	ret.RefundID = refund.ID
+	if err := s.returns.Update(ctx, ret, repository.ReturnStatusRefunded); err != nil {
+		s.logger.Error("Failed to link refund to return", logging.Fields{
+			"return_id": ret.ID,
+			"refund_id": refund.ID,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+
+	go s.sendReturnNotification(context.Background(), ret)
+	return ret, nil
+}
+
+// advance moves a return to status to after apply has filled in the fields
+// of that step, then notifies the customer.
+func (s *ReturnService) advance(
+	ctx context.Context,
+	orderID, returnID string,
+	to repository.ReturnStatus,
+	apply func(ret *repository.Return, now time.Time) error,
+) (*repository.Return, error) {
+	s.logger.Info("Updating return status", logging.Fields{
+		"order_id":  orderID,
+		"return_id": returnID,
+		"status":    to,
+	})
+
+	ret, err := s.returns.GetByID(ctx, orderID, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := ret.Status
+	if !returnTransitionAllowed(from, to) {
+		return nil, errors.NewValidationError("status", fmt.Sprintf(
+			"invalid return status transition from %s to %s", from, to))
+	}
+
+	if err := apply(ret, s.now()); err != nil {
+		return nil, err
+	}
+	ret.Status = to
+
+	if err := s.returns.Update(ctx, ret, from); err != nil {
+		return nil, err
+	}
+
+	go s.sendReturnNotification(context.Background(), ret)
+	return ret, nil
+}
+
+// checkReturnWindow verifies that order was delivered, has a captured
+// payment left to refund and was delivered no longer than window ago.
+func checkReturnWindow(order *models.Order, window time.Duration, now time.Time) error {
+	if !canRefund(order) {
+		return errors.NewValidationError("status", fmt.Sprintf(
+			"order in status %s cannot be returned", order.Status))
+	}
+	if order.DeliveredAt == nil {
+		return errors.NewValidationError("delivered_at", "order has no delivery date")
+	}
+
+	deadline := order.DeliveredAt.Add(window)
+	if now.After(deadline) {
+		return errors.NewValidationError("delivered_at", fmt.Sprintf(
+			"the return window closed on %s", deadline.Format("2006-01-02")))
+	}
+	return nil
+}
+
+// checkReturnItems verifies that items reference lines of order and do not
+// return more than remains after refunds and other open returns, and
+// converts them to return items.
+func checkReturnItems(order *models.Order, refunds []*repository.OrderRefund, existing []*repository.Return, items []ReturnItemRequest) ([]repository.ReturnItem, error) {
+	if len(items) == 0 {
+		return nil, errors.NewValidationError("items", "at least one item is required")
+	}
+
+	refunded := refundedQuantities(refunds)
+	remaining := make(map[string]int, len(order.Items))
+	for i, item := range order.Items {
+		id := orderLineID(item, i)
+		remaining[id] += item.Quantity - refunded[id]
+	}
+	for _, ret := range existing {
+		// Refunded returns are already counted by their refund.
+		if ret.Status.Closed() {
+			continue
+		}
+		for _, item := range ret.Items {
+			remaining[item.ItemID] -= item.Quantity
+		}
+	}
+
+	returned := make([]repository.ReturnItem, 0, len(items))
+	for i, item := range items {
+		field := fmt.Sprintf("items[%d]", i)
+		left, ok := remaining[item.ItemID]
+		if !ok {
+			return nil, errors.NewValidationError(field, "unknown order item "+item.ItemID)
+		}
+		if item.Quantity <= 0 {
+			return nil, errors.NewValidationError(field, "quantity must be positive")
+		}
+		if item.Quantity > left {
+			return nil, errors.NewValidationError(field, fmt.Sprintf(
+				"only %d of item %s can be returned", left, item.ItemID))
+		}
+		remaining[item.ItemID] = left - item.Quantity
+
+		returned = append(returned, repository.ReturnItem{
+			ItemID:    item.ItemID,
+			ProductID: productForLine(order, item.ItemID),
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return returned, nil
+}
+
+// setReturnQuantities sets the quantity of each item from req using set,
+// bounded by limit. Items missing from req are set to zero; an empty req
+// sets every item to its limit.
+func setReturnQuantities(
+	items []repository.ReturnItem,
+	req []ReturnItemRequest,
+	limit func(item repository.ReturnItem) int,
+	set func(item *repository.ReturnItem, n int),
+) error {
+	if len(req) == 0 {
+		for i := range items {
+			set(&items[i], limit(items[i]))
+		}
+		return nil
+	}
+
+	index := make(map[string]int, len(items))
+	for i, item := range items {
+		index[item.ItemID] = i
+	}
+
+	quantities := make(map[string]int, len(req))
+	for i, item := range req {
+		field := fmt.Sprintf("items[%d]", i)
+		n, ok := index[item.ItemID]
+		if !ok {
+			return errors.NewValidationError(field, "item "+item.ItemID+" is not part of this return")
+		}
+		if _, dup := quantities[item.ItemID]; dup {
+			return errors.NewValidationError(field, "item "+item.ItemID+" is listed twice")
+		}
+		if item.Quantity < 0 {
+			return errors.NewValidationError(field, "quantity cannot be negative")
+		}
+		if max := limit(items[n]); item.Quantity > max {
+			return errors.NewValidationError(field, fmt.Sprintf(
+				"at most %d of item %s can be recorded", max, item.ItemID))
+		}
+		quantities[item.ItemID] = item.Quantity
+	}
+
+	for i := range items {
+		set(&items[i], quantities[items[i].ItemID])
+	}
+	return nil
+}
+
+func countReturnUnits(items []repository.ReturnItem, quantity func(item repository.ReturnItem) int) int {
+	total := 0
+	for _, item := range items {
+		total += quantity(item)
+	}
+	return total
+}
+
+func returnTransitionAllowed(from, to repository.ReturnStatus) bool {
+	for _, allowed := range returnTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// sendReturnNotification tells the customer about the step ret has reached.
+// Failures are logged only.
+func (s *ReturnService) sendReturnNotification(ctx context.Context, ret *repository.Return) {
+	var notificationType models.NotificationType
+	var subject, body string
+
+	switch ret.Status {
+	case repository.ReturnStatusRequested:
+		notificationType, subject = NotificationTypeReturnRequested, "Return Requested"
+		body = fmt.Sprintf("We have received your return request %s for order %s.", ret.ID, ret.OrderID)
+	case repository.ReturnStatusApproved:
+		notificationType, subject = NotificationTypeReturnApproved, "Return Approved"
+		body = fmt.Sprintf("Your return %s has been approved. Please send the items back.", ret.ID)
+	case repository.ReturnStatusReceived:
+		notificationType, subject = NotificationTypeReturnReceived, "Return Received"
+		body = fmt.Sprintf("The items of your return %s have arrived at our warehouse.", ret.ID)
+	case repository.ReturnStatusInspected:
+		notificationType, subject = NotificationTypeReturnInspected, "Return Inspected"
+		body = fmt.Sprintf("The items of your return %s have been inspected.", ret.ID)
+	case repository.ReturnStatusRefunded:
+		notificationType, subject = NotificationTypeReturnRefunded, "Return Refunded"
+		body = fmt.Sprintf("Your return %s has been refunded.", ret.ID)
+	case repository.ReturnStatusRejected:
+		notificationType, subject = NotificationTypeReturnRejected, "Return Rejected"
+		body = fmt.Sprintf("Your return %s has been rejected: %s", ret.ID, ret.Notes)
+	default:
+		return
+	}
+
+	req := &models.SendNotificationRequest{
+		Type:      notificationType,
+		Priority:  models.NotificationPriorityNormal,
+		Recipient: ret.UserID,
+		Subject:   subject,
+		Body:      body,
+		Metadata: map[string]string{
+			"order_id":  ret.OrderID,
+			"return_id": ret.ID,
+			"status":    string(ret.Status),
+		},
+	}
+
+	if _, err := s.notificationClient.Send(ctx, req); err != nil {
+		s.logger.Error("Failed to send return notification", logging.Fields{
+			"order_id":  ret.OrderID,
+			"return_id": ret.ID,
+			"error":     err.Error(),
+		})
+	}
+}
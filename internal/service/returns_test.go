@@ -0,0 +1,105 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func TestCheckReturnWindow(t *testing.T) {
+	delivered := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+
+	order := refundTestOrder()
+	order.DeliveredAt = &delivered
+
+	if err := checkReturnWindow(order, window, delivered.Add(window)); err != nil {
+		t.Errorf("expected the last day of the window to be accepted, got %v", err)
+	}
+	if err := checkReturnWindow(order, window, delivered.Add(window+time.Minute)); err == nil {
+		t.Error("expected an error after the window closed")
+	}
+
+	order.DeliveredAt = nil
+	if err := checkReturnWindow(order, window, delivered); err == nil {
+		t.Error("expected an error without a delivery date")
+	}
+
+	order.DeliveredAt = &delivered
+	order.Status = models.OrderStatusShipped
+	if err := checkReturnWindow(order, window, delivered); err == nil {
+		t.Error("expected an error for an order that is not delivered")
+	}
+}
+
+func TestCheckReturnItems(t *testing.T) {
+	order := refundTestOrder()
+	refunds := []*repository.OrderRefund{
+		{Status: models.PaymentStatusRefunded, Items: []repository.RefundItem{{ItemID: "line_1", Quantity: 1}}},
+		{Status: models.PaymentStatusFailed, Items: []repository.RefundItem{{ItemID: "line_2", Quantity: 1}}},
+	}
+	existing := []*repository.Return{
+		{Status: repository.ReturnStatusApproved, Items: []repository.ReturnItem{{ItemID: "line_1", Quantity: 1}}},
+		{Status: repository.ReturnStatusRejected, Items: []repository.ReturnItem{{ItemID: "line_2", Quantity: 1}}},
+	}
+
+	items, err := checkReturnItems(order, refunds, existing, []ReturnItemRequest{
+		{ItemID: "line_1", Quantity: 1},
+		{ItemID: "line_2", Quantity: 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].ProductID != "prod_a" || items[1].ProductID != "prod_b" {
+		t.Errorf("expected product IDs to be filled in, got %+v", items)
+	}
+
+	// One unit of line_1 is refunded and one is in an open return.
+	if _, err := checkReturnItems(order, refunds, existing, []ReturnItemRequest{{ItemID: "line_1", Quantity: 2}}); err == nil {
+		t.Error("expected an error returning more than remains")
+	}
+	if _, err := checkReturnItems(order, nil, nil, []ReturnItemRequest{{ItemID: "line_9", Quantity: 1}}); err == nil {
+		t.Error("expected an error for an unknown item")
+	}
+}
+
+func TestSetReturnQuantities(t *testing.T) {
+	newItems := func() []repository.ReturnItem {
+		return []repository.ReturnItem{
+			{ItemID: "line_1", Quantity: 2},
+			{ItemID: "line_2", Quantity: 1},
+		}
+	}
+	limit := func(item repository.ReturnItem) int { return item.Quantity }
+	set := func(item *repository.ReturnItem, n int) { item.ReceivedQuantity = n }
+
+	items := newItems()
+	if err := setReturnQuantities(items, nil, limit, set); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].ReceivedQuantity != 2 || items[1].ReceivedQuantity != 1 {
+		t.Errorf("expected everything carried over, got %+v", items)
+	}
+
+	items = newItems()
+	if err := setReturnQuantities(items, []ReturnItemRequest{{ItemID: "line_1", Quantity: 1}}, limit, set); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].ReceivedQuantity != 1 || items[1].ReceivedQuantity != 0 {
+		t.Errorf("expected listed quantity and zero for the rest, got %+v", items)
+	}
+
+	invalid := [][]ReturnItemRequest{
+		{{ItemID: "line_1", Quantity: 3}},
+		{{ItemID: "line_1", Quantity: -1}},
+		{{ItemID: "line_3", Quantity: 1}},
+		{{ItemID: "line_2", Quantity: 1}, {ItemID: "line_2", Quantity: 1}},
+	}
+	for _, req := range invalid {
+		if err := setReturnQuantities(newItems(), req, limit, set); err == nil {
+			t.Errorf("expected an error for %+v", req)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// ShipmentEventPublisher publishes shipment events. It complements
+// interfaces.OrderEventPublisher, which has no shipment events.
+type ShipmentEventPublisher interface {
+	PublishShipmentCreated(ctx context.Context, order *models.Order, shipment *repository.Shipment) error
+	PublishShipmentStatusChanged(ctx context.Context, order *models.Order, shipment *repository.Shipment, previousStatus repository.ShipmentStatus) error
+}
+
+// CreateShipmentRequest is the body of POST /api/v2/orders/:id/shipments.
+type CreateShipmentRequest struct {
+	Warehouse      string `json:"warehouse"`
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"tracking_number"`
+	// Status is pending (label created) or shipped; it defaults to shipped.
+	Status repository.ShipmentStatus `json:"status"`
+	Items  []repository.ShipmentItem `json:"items"`
+}
+
+// UpdateShipmentStatusRequest is the body of
+// PATCH /api/v2/orders/:id/shipments/:shipment_id.
+type UpdateShipmentStatusRequest struct {
+	Status         repository.ShipmentStatus `json:"status"`
+	TrackingNumber string                    `json:"tracking_number"`
+}
+
+// shipmentTransitions lists the allowed shipment status changes.
+var shipmentTransitions = map[repository.ShipmentStatus][]repository.ShipmentStatus{
+	repository.ShipmentStatusPending: {repository.ShipmentStatusShipped, repository.ShipmentStatusCancelled},
+	repository.ShipmentStatusShipped: {repository.ShipmentStatusDelivered},
+}
+
+// orderLineID returns the ID shipments and refunds use to reference the
+// order line at index i. Lines created before IDs were assigned fall back to
+// their position.
+func orderLineID(item models.OrderItem, i int) string {
+	if item.ID != "" {
+		return item.ID
+	}
+	return "line_" + strconv.Itoa(i+1)
+}
+
+// ListShipments returns an order's shipments, oldest first.
+func (s *OrderService) ListShipments(ctx context.Context, orderID string) ([]*repository.Shipment, error) {
+	if _, err := s.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.shipments.ListByOrderID(ctx, orderID)
+}
+
+// CreateShipment records a shipment of some of an order's items and moves
+// the order to partially shipped or shipped once the items have left the
+// warehouse.
+func (s *OrderService) CreateShipment(ctx context.Context, orderID string, req *CreateShipmentRequest) (*repository.Shipment, error) {
+	s.logger.Info("Creating shipment", logging.Fields{
+		"order_id":  orderID,
+		"warehouse": req.Warehouse,
+		"carrier":   req.Carrier,
+	})
+
+	current, err := s.orderRepo.GetVersioned(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch current.Status {
+	case models.OrderStatusProcessing, OrderStatusPartiallyShipped:
+	default:
+		return nil, errors.NewValidationError("status", fmt.Sprintf(
+			"order in status %s cannot be shipped", current.Status))
+	}
+
+	existing, err := s.shipments.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment := &repository.Shipment{
+		OrderID:        orderID,
+		Warehouse:      req.Warehouse,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		Status:         req.Status,
+		Items:          req.Items,
+	}
+	if shipment.Status == "" {
+		shipment.Status = repository.ShipmentStatusShipped
+	}
+
+	if err := checkShipmentItems(current.Order, existing, shipment.Items); err != nil {
+		return nil, err
+	}
+	// Fill in product IDs so consumers of shipment events need not look
+	// them up.
+	for i := range shipment.Items {
+		shipment.Items[i].ProductID = productForLine(current.Order, shipment.Items[i].ItemID)
+	}
+
+	save := func(ctx context.Context, order *models.Order) error {
+		if err := s.shipments.Create(ctx, shipment); err != nil {
+			return err
+		}
+		return s.publishShipmentCreated(ctx, order, shipment)
+	}
+
+	if err := s.applyShipmentChange(ctx, current, append(existing, shipment), save); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// UpdateShipmentStatus moves a shipment along pending → shipped → delivered
+// (or pending → cancelled) and re-derives the order status.
+func (s *OrderService) UpdateShipmentStatus(ctx context.Context, orderID, shipmentID string, req *UpdateShipmentStatusRequest) (*repository.Shipment, error) {
+	s.logger.Info("Updating shipment status", logging.Fields{
+		"order_id":    orderID,
+		"shipment_id": shipmentID,
+		"status":      req.Status,
+	})
+
+	current, err := s.orderRepo.GetVersioned(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments, err := s.shipments.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var shipment *repository.Shipment
+	for _, candidate := range shipments {
+		if candidate.ID == shipmentID {
+			shipment = candidate
+		}
+	}
+	if shipment == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	from := shipment.Status
+	if !shipmentTransitionAllowed(from, req.Status) {
+		return nil, errors.NewValidationError("status", fmt.Sprintf(
+			"invalid shipment status transition from %s to %s", from, req.Status))
+	}
+
+	shipment.Status = req.Status
+	if req.TrackingNumber != "" {
+		shipment.TrackingNumber = req.TrackingNumber
+	}
+
+	save := func(ctx context.Context, order *models.Order) error {
+		if err := s.shipments.UpdateStatus(ctx, shipment, from); err != nil {
+			return err
+		}
+		return s.publishShipmentStatusChanged(ctx, order, shipment, from)
+	}
+
+	if err := s.applyShipmentChange(ctx, current, shipments, save); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// applyShipmentChange runs save together with the order status change that
+// shipments imply.
+func (s *OrderService) applyShipmentChange(
+	ctx context.Context,
+	current *repository.VersionedOrder,
+	shipments []*repository.Shipment,
+	save func(ctx context.Context, order *models.Order) error,
+) error {
+	target := deriveFulfillmentStatus(current.Order, shipments)
+	return s.saveWithStatus(ctx, current, target, "Derived from shipments", save)
+}
+
+// checkShipmentItems verifies that items reference lines of order and do not
+// ship more than remains after the existing, non-cancelled shipments.
+func checkShipmentItems(order *models.Order, existing []*repository.Shipment, items []repository.ShipmentItem) error {
+	if len(items) == 0 {
+		return errors.NewValidationError("items", "at least one item is required")
+	}
+
+	remaining := make(map[string]int, len(order.Items))
+	for i, item := range order.Items {
+		remaining[orderLineID(item, i)] += item.Quantity
+	}
+	for _, shipment := range existing {
+		if shipment.Status == repository.ShipmentStatusCancelled {
+			continue
+		}
+		for _, item := range shipment.Items {
+			remaining[item.ItemID] -= item.Quantity
+		}
+	}
+
+	for i, item := range items {
+		field := fmt.Sprintf("items[%d]", i)
+		left, ok := remaining[item.ItemID]
+		if !ok {
+			return errors.NewValidationError(field, "unknown order item "+item.ItemID)
+		}
+		if item.Quantity <= 0 {
+			return errors.NewValidationError(field, "quantity must be positive")
+		}
+		if item.Quantity > left {
+			return errors.NewValidationError(field, fmt.Sprintf(
+				"only %d of item %s remain to be shipped", left, item.ItemID))
+		}
+		remaining[item.ItemID] = left - item.Quantity
+	}
+
+	return nil
+}
+
+// deriveFulfillmentStatus returns the order status implied by shipments, or
+// "" while nothing has left the warehouse.
+func deriveFulfillmentStatus(order *models.Order, shipments []*repository.Shipment) models.OrderStatus {
+	shipped := make(map[string]int)
+	anyShipped := false
+	allDelivered := true
+
+	for _, shipment := range shipments {
+		switch shipment.Status {
+		case repository.ShipmentStatusShipped:
+			allDelivered = false
+		case repository.ShipmentStatusDelivered:
+		default:
+			continue
+		}
+		anyShipped = true
+		for _, item := range shipment.Items {
+			shipped[item.ItemID] += item.Quantity
+		}
+	}
+
+	if !anyShipped {
+		return ""
+	}
+
+	for i, item := range order.Items {
+		if shipped[orderLineID(item, i)] < item.Quantity {
+			return OrderStatusPartiallyShipped
+		}
+	}
+
+	if allDelivered {
+		return models.OrderStatusDelivered
+	}
+	return models.OrderStatusShipped
+}
+
+func shipmentTransitionAllowed(from, to repository.ShipmentStatus) bool {
+	for _, allowed := range shipmentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func productForLine(order *models.Order, lineID string) string {
+	for i, item := range order.Items {
+		if orderLineID(item, i) == lineID {
+			return item.ProductID
+		}
+	}
+	return ""
+}
+
+func (s *OrderService) publishShipmentCreated(ctx context.Context, order *models.Order, shipment *repository.Shipment) error {
+	if !s.config.Features.EnableOrderEvents {
+		return nil
+	}
+	return s.shipmentEvents.PublishShipmentCreated(ctx, order, shipment)
+}
+
+func (s *OrderService) publishShipmentStatusChanged(ctx context.Context, order *models.Order, shipment *repository.Shipment, previous repository.ShipmentStatus) error {
+	if !s.config.Features.EnableOrderEvents {
+		return nil
+	}
+	return s.shipmentEvents.PublishShipmentStatusChanged(ctx, order, shipment, previous)
+}
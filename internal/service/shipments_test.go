@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func shipmentTestOrder() *models.Order {
+	return &models.Order{
+		ID:     "ord_1",
+		Status: models.OrderStatusProcessing,
+		Items: []models.OrderItem{
+			{ID: "line_1", ProductID: "prod_a", Quantity: 2},
+			{ProductID: "prod_b", Quantity: 1},
+		},
+	}
+}
+
+func shipment(status repository.ShipmentStatus, items ...repository.ShipmentItem) *repository.Shipment {
+	return &repository.Shipment{Status: status, Items: items}
+}
+
+func TestDeriveFulfillmentStatus(t *testing.T) {
+	order := shipmentTestOrder()
+	a1 := repository.ShipmentItem{ItemID: "line_1", Quantity: 1}
+	a2 := repository.ShipmentItem{ItemID: "line_1", Quantity: 2}
+	b1 := repository.ShipmentItem{ItemID: "line_2", Quantity: 1}
+
+	tests := []struct {
+		name      string
+		shipments []*repository.Shipment
+		want      models.OrderStatus
+	}{
+		{"nothing shipped", nil, ""},
+		{"only pending", []*repository.Shipment{shipment(repository.ShipmentStatusPending, a2, b1)}, ""},
+		{"partial", []*repository.Shipment{shipment(repository.ShipmentStatusShipped, a1)}, OrderStatusPartiallyShipped},
+		{"cancelled ignored", []*repository.Shipment{
+			shipment(repository.ShipmentStatusShipped, a2),
+			shipment(repository.ShipmentStatusCancelled, b1),
+		}, OrderStatusPartiallyShipped},
+		{"fully shipped", []*repository.Shipment{
+			shipment(repository.ShipmentStatusDelivered, a2),
+			shipment(repository.ShipmentStatusShipped, b1),
+		}, models.OrderStatusShipped},
+		{"fully delivered", []*repository.Shipment{
+			shipment(repository.ShipmentStatusDelivered, a1),
+			shipment(repository.ShipmentStatusDelivered, a1, b1),
+		}, models.OrderStatusDelivered},
+	}
+
+	for _, tt := range tests {
+		if got := deriveFulfillmentStatus(order, tt.shipments); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCheckShipmentItems(t *testing.T) {
+	order := shipmentTestOrder()
+	existing := []*repository.Shipment{
+		shipment(repository.ShipmentStatusShipped, repository.ShipmentItem{ItemID: "line_1", Quantity: 1}),
+		shipment(repository.ShipmentStatusCancelled, repository.ShipmentItem{ItemID: "line_2", Quantity: 1}),
+	}
+
+	ok := []repository.ShipmentItem{{ItemID: "line_1", Quantity: 1}, {ItemID: "line_2", Quantity: 1}}
+	if err := checkShipmentItems(order, existing, ok); err != nil {
+		t.Errorf("Expected remaining items to be shippable, got %v", err)
+	}
+
+	invalid := map[string][]repository.ShipmentItem{
+		"over shipped":   {{ItemID: "line_1", Quantity: 2}},
+		"split overship": {{ItemID: "line_1", Quantity: 1}, {ItemID: "line_1", Quantity: 1}},
+		"unknown line":   {{ItemID: "line_9", Quantity: 1}},
+		"zero quantity":  {{ItemID: "line_2", Quantity: 0}},
+		"no items":       nil,
+	}
+	for name, items := range invalid {
+		if err := checkShipmentItems(order, existing, items); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// Guards that can be attached to lifecycle transitions.
+const (
+	// GuardPaymentCaptured requires the order's payment to have been
+	// captured, as recorded from the payment service.
+	GuardPaymentCaptured = "payment_captured"
+)
+
+// Hooks that can be attached to lifecycle transitions.
+const (
+	HookPublishStatusChanged = "publish_status_changed"
+	HookPublishCancelled     = "publish_cancelled"
+	HookInvalidateCache      = "invalidate_cache"
+	HookNotifyShipped        = "notify_shipped"
+	HookNotifyDelivered      = "notify_delivered"
+	HookNotifyCancelled      = "notify_cancelled"
+)
+
+var knownGuards = map[string]bool{
+	GuardPaymentCaptured: true,
+}
+
+var knownHooks = map[string]bool{
+	HookPublishStatusChanged: true,
+	HookPublishCancelled:     true,
+	HookInvalidateCache:      true,
+	HookNotifyShipped:        true,
+	HookNotifyDelivered:      true,
+	HookNotifyCancelled:      true,
+}
+
+// OrderLifecycle declares the order state machine. It can be built in code
+// (DefaultOrderLifecycle) or loaded from a JSON file with the same shape.
+type OrderLifecycle struct {
+	Initial     models.OrderStatus `json:"initial"`
+	Transitions []TransitionRule   `json:"transitions"`
+}
+
+// TransitionRule allows an order to move From -> To once all Guards pass,
+// and runs Hooks when it does.
+type TransitionRule struct {
+	From   models.OrderStatus `json:"from"`
+	To     models.OrderStatus `json:"to"`
+	Guards []string           `json:"guards,omitempty"`
+	Hooks  []string           `json:"hooks,omitempty"`
+}
+
+// DefaultOrderLifecycle is the built-in order lifecycle.
+func DefaultOrderLifecycle() *OrderLifecycle {
+	statusChanged := []string{HookPublishStatusChanged, HookInvalidateCache}
+	cancelled := []string{HookPublishCancelled, HookInvalidateCache, HookNotifyCancelled}
+	captured := []string{GuardPaymentCaptured}
+
+	return &OrderLifecycle{
+		Initial: models.OrderStatusPending,
+		Transitions: []TransitionRule{
+			{From: models.OrderStatusPending, To: models.OrderStatusConfirmed, Guards: captured, Hooks: statusChanged},
+			{From: models.OrderStatusPending, To: models.OrderStatusCancelled, Hooks: cancelled},
+			{From: models.OrderStatusConfirmed, To: models.OrderStatusProcessing, Guards: captured, Hooks: statusChanged},
+			{From: models.OrderStatusConfirmed, To: models.OrderStatusCancelled, Hooks: cancelled},
+			{From: models.OrderStatusConfirmed, To: OrderStatusDisputed, Hooks: statusChanged},
+			{From: models.OrderStatusProcessing, To: models.OrderStatusShipped, Guards: captured, Hooks: append(statusChanged, HookNotifyShipped)},
+			{From: models.OrderStatusProcessing, To: models.OrderStatusCancelled, Hooks: cancelled},
+			{From: models.OrderStatusProcessing, To: OrderStatusDisputed, Hooks: statusChanged},
+			{From: models.OrderStatusProcessing, To: OrderStatusPartiallyShipped, Guards: captured, Hooks: statusChanged},
+			{From: OrderStatusPartiallyShipped, To: models.OrderStatusShipped, Guards: captured, Hooks: append(statusChanged, HookNotifyShipped)},
+			{From: OrderStatusPartiallyShipped, To: OrderStatusDisputed, Hooks: statusChanged},
+			{From: models.OrderStatusShipped, To: models.OrderStatusDelivered, Hooks: append(statusChanged, HookNotifyDelivered)},
+			{From: models.OrderStatusShipped, To: OrderStatusDisputed, Hooks: statusChanged},
+			{From: models.OrderStatusDelivered, To: models.OrderStatusRefunded, Guards: captured, Hooks: statusChanged},
+			{From: models.OrderStatusDelivered, To: OrderStatusPartiallyRefunded, Guards: captured, Hooks: statusChanged},
+			{From: models.OrderStatusDelivered, To: OrderStatusDisputed, Hooks: statusChanged},
+			{From: OrderStatusPartiallyRefunded, To: models.OrderStatusRefunded, Hooks: statusChanged},
+			{From: OrderStatusPartiallyRefunded, To: OrderStatusDisputed, Hooks: statusChanged},
+			{From: OrderStatusDisputed, To: models.OrderStatusDelivered, Hooks: statusChanged},
+			{From: OrderStatusDisputed, To: models.OrderStatusRefunded, Hooks: statusChanged},
+		},
+	}
+}
+
+// LoadOrderLifecycle reads a lifecycle definition from a JSON file.
+func LoadOrderLifecycle(path string) (*OrderLifecycle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read order lifecycle: %w", err)
+	}
+
+	var lifecycle OrderLifecycle
+	if err := json.Unmarshal(data, &lifecycle); err != nil {
+		return nil, fmt.Errorf("parse order lifecycle: %w", err)
+	}
+	if err := lifecycle.Validate(); err != nil {
+		return nil, err
+	}
+	return &lifecycle, nil
+}
+
+// Validate checks that every transition is complete and only references
+// known guards and hooks.
+func (l *OrderLifecycle) Validate() error {
+	if l.Initial == "" {
+		return fmt.Errorf("order lifecycle: initial status is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, rule := range l.Transitions {
+		if rule.From == "" || rule.To == "" {
+			return fmt.Errorf("order lifecycle: transition needs from and to")
+		}
+		key := string(rule.From) + "->" + string(rule.To)
+		if seen[key] {
+			return fmt.Errorf("order lifecycle: duplicate transition %s", key)
+		}
+		seen[key] = true
+
+		for _, guard := range rule.Guards {
+			if !knownGuards[guard] {
+				return fmt.Errorf("order lifecycle: unknown guard %q on %s", guard, key)
+			}
+		}
+		for _, hook := range rule.Hooks {
+			if !knownHooks[hook] {
+				return fmt.Errorf("order lifecycle: unknown hook %q on %s", hook, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Transition is a status change being applied to an order.
+type Transition struct {
+	// Order is the order before the change while guards run, and after the
+	// change while hooks run.
+	Order *models.Order
+	From  models.OrderStatus
+	To    models.OrderStatus
+	Notes string
+	// PaymentStatus is the payment status recorded for the order.
+	PaymentStatus models.PaymentStatus
+}
+
+// TransitionGuard blocks a transition by returning an error.
+type TransitionGuard func(ctx context.Context, t *Transition) error
+
+// TransitionHook performs a side effect of a transition.
+type TransitionHook func(ctx context.Context, t *Transition) error
+
+// HookPhase says when a hook runs relative to the status update.
+type HookPhase int
+
+const (
+	// HookInTx runs inside the status update transaction; an error rolls
+	// the transition back.
+	HookInTx HookPhase = iota
+	// HookAfterCommit runs once the transition is committed; errors are
+	// logged only.
+	HookAfterCommit
+)
+
+type boundHook struct {
+	name  string
+	phase HookPhase
+	fn    TransitionHook
+}
+
+// StateMachine evaluates an OrderLifecycle.
+type StateMachine struct {
+	lifecycle *OrderLifecycle
+	rules     map[models.OrderStatus]map[models.OrderStatus]*TransitionRule
+	guards    map[string]TransitionGuard
+	hooks     map[string]boundHook
+}
+
+// newStateMachine binds a validated lifecycle to guard and hook
+// implementations.
+func newStateMachine(lifecycle *OrderLifecycle, guards map[string]TransitionGuard, hooks map[string]boundHook) *StateMachine {
+	rules := make(map[models.OrderStatus]map[models.OrderStatus]*TransitionRule)
+	for i := range lifecycle.Transitions {
+		rule := &lifecycle.Transitions[i]
+		if rules[rule.From] == nil {
+			rules[rule.From] = make(map[models.OrderStatus]*TransitionRule)
+		}
+		rules[rule.From][rule.To] = rule
+	}
+
+	return &StateMachine{
+		lifecycle: lifecycle,
+		rules:     rules,
+		guards:    guards,
+		hooks:     hooks,
+	}
+}
+
+// Rule returns the rule for from -> to, or nil if the transition is not allowed.
+func (m *StateMachine) Rule(from, to models.OrderStatus) *TransitionRule {
+	return m.rules[from][to]
+}
+
+// CanTransition reports whether from -> to is declared.
+func (m *StateMachine) CanTransition(from, to models.OrderStatus) bool {
+	return m.Rule(from, to) != nil
+}
+
+// CheckGuards runs the guards of rule and returns the first failure.
+func (m *StateMachine) CheckGuards(ctx context.Context, rule *TransitionRule, t *Transition) error {
+	for _, name := range rule.Guards {
+		if err := m.guards[name](ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunHooks runs the hooks of rule registered for phase, in declaration order.
+// In-transaction hooks stop at the first error; after-commit hooks run
+// regardless and the errors are returned together.
+func (m *StateMachine) RunHooks(ctx context.Context, rule *TransitionRule, phase HookPhase, t *Transition) error {
+	var failures []string
+	for _, name := range rule.Hooks {
+		hook := m.hooks[name]
+		if hook.phase != phase {
+			continue
+		}
+		if err := hook.fn(ctx, t); err != nil {
+			if phase == HookInTx {
+				return err
+			}
+			failures = append(failures, name+": "+err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("transition hooks failed: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// DOT renders the lifecycle as a Graphviz digraph.
+func (m *StateMachine) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph order_lifecycle {\n")
+	b.WriteString("  rankdir=LR;\n")
+	fmt.Fprintf(&b, "  %q [shape=doublecircle];\n", m.lifecycle.Initial)
+	for _, rule := range m.lifecycle.Transitions {
+		if len(rule.Guards) > 0 {
+			fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", rule.From, rule.To, "["+strings.Join(rule.Guards, ", ")+"]")
+		} else {
+			fmt.Fprintf(&b, "  %q -> %q;\n", rule.From, rule.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the lifecycle as a Mermaid state diagram.
+func (m *StateMachine) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    [*] --> %s\n", m.lifecycle.Initial)
+	for _, rule := range m.lifecycle.Transitions {
+		if len(rule.Guards) > 0 {
+			fmt.Fprintf(&b, "    %s --> %s : [%s]\n", rule.From, rule.To, strings.Join(rule.Guards, ", "))
+		} else {
+			fmt.Fprintf(&b, "    %s --> %s\n", rule.From, rule.To)
+		}
+	}
+	for _, status := range m.terminalStatuses() {
+		fmt.Fprintf(&b, "    %s --> [*]\n", status)
+	}
+	return b.String()
+}
+
+// terminalStatuses returns statuses with no outgoing transitions, in the
+// order they first appear.
+func (m *StateMachine) terminalStatuses() []models.OrderStatus {
+	var terminal []models.OrderStatus
+	seen := make(map[models.OrderStatus]bool)
+	for _, rule := range m.lifecycle.Transitions {
+		if !seen[rule.To] && len(m.rules[rule.To]) == 0 {
+			terminal = append(terminal, rule.To)
+		}
+		seen[rule.To] = true
+	}
+	return terminal
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func newTestStateMachine(calls *[]string) *StateMachine {
+	guards := map[string]TransitionGuard{
+		GuardPaymentCaptured: func(ctx context.Context, t *Transition) error {
+			if t.PaymentStatus != models.PaymentStatusCompleted {
+				return errors.New("payment not captured")
+			}
+			return nil
+		},
+	}
+
+	hooks := make(map[string]boundHook)
+	for name := range knownHooks {
+		name := name
+		phase := HookAfterCommit
+		if strings.HasPrefix(name, "publish_") {
+			phase = HookInTx
+		}
+		hooks[name] = boundHook{name: name, phase: phase, fn: func(ctx context.Context, t *Transition) error {
+			*calls = append(*calls, name)
+			return nil
+		}}
+	}
+
+	return newStateMachine(DefaultOrderLifecycle(), guards, hooks)
+}
+
+func TestDefaultOrderLifecycle_Validates(t *testing.T) {
+	if err := DefaultOrderLifecycle().Validate(); err != nil {
+		t.Fatalf("Built-in lifecycle is invalid: %v", err)
+	}
+}
+
+func TestStateMachine_Transitions(t *testing.T) {
+	m := newTestStateMachine(new([]string))
+
+	tests := []struct {
+		from, to models.OrderStatus
+		allowed  bool
+	}{
+		{models.OrderStatusPending, models.OrderStatusConfirmed, true},
+		{models.OrderStatusShipped, models.OrderStatusDelivered, true},
+		{models.OrderStatusPending, models.OrderStatusShipped, false},
+		{models.OrderStatusCancelled, models.OrderStatusPending, false},
+		{models.OrderStatusRefunded, models.OrderStatusDelivered, false},
+	}
+
+	for _, tt := range tests {
+		if got := m.CanTransition(tt.from, tt.to); got != tt.allowed {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.allowed)
+		}
+	}
+}
+
+func TestStateMachine_Guards(t *testing.T) {
+	m := newTestStateMachine(new([]string))
+	rule := m.Rule(models.OrderStatusPending, models.OrderStatusConfirmed)
+
+	unpaid := &Transition{Order: &models.Order{ID: "ord_1"}, From: rule.From, To: rule.To}
+	if err := m.CheckGuards(context.Background(), rule, unpaid); err == nil {
+		t.Error("Expected guard to block confirmation without a payment")
+	}
+
+	paid := &Transition{Order: &models.Order{ID: "ord_1", PaymentID: "pay_1"}, From: rule.From, To: rule.To, PaymentStatus: models.PaymentStatusCompleted}
+	if err := m.CheckGuards(context.Background(), rule, paid); err != nil {
+		t.Errorf("Expected guard to pass, got %v", err)
+	}
+
+	for _, to := range []models.OrderStatus{models.OrderStatusProcessing, models.OrderStatusShipped} {
+		from := models.OrderStatusConfirmed
+		if to == models.OrderStatusShipped {
+			from = models.OrderStatusProcessing
+		}
+		rule := m.Rule(from, to)
+		unpaid := &Transition{Order: &models.Order{ID: "ord_1", PaymentID: "pay_1"}, From: from, To: to}
+		if err := m.CheckGuards(context.Background(), rule, unpaid); err == nil {
+			t.Errorf("Expected guard to block %s -> %s without a captured payment", from, to)
+		}
+	}
+}
+
+func TestGuardPaymentCaptured(t *testing.T) {
+	guard := (&OrderService{}).transitionGuards()[GuardPaymentCaptured]
+
+	tests := []struct {
+		name      string
+		paymentID string
+		status    models.PaymentStatus
+		allowed   bool
+	}{
+		{"no payment", "", "", false},
+		{"attached but not reported", "pay_1", "", false},
+		{"pending", "pay_1", models.PaymentStatusPending, false},
+		{"failed", "pay_1", models.PaymentStatusFailed, false},
+		{"completed", "pay_1", models.PaymentStatusCompleted, true},
+	}
+
+	for _, tt := range tests {
+		tr := &Transition{
+			Order:         &models.Order{ID: "ord_1", PaymentID: tt.paymentID},
+			From:          models.OrderStatusConfirmed,
+			To:            models.OrderStatusProcessing,
+			PaymentStatus: tt.status,
+		}
+		if err := guard(context.Background(), tr); (err == nil) != tt.allowed {
+			t.Errorf("%s: guard returned %v, want allowed=%v", tt.name, err, tt.allowed)
+		}
+	}
+}
+
+func TestStateMachine_HooksRunByPhase(t *testing.T) {
+	var calls []string
+	m := newTestStateMachine(&calls)
+	rule := m.Rule(models.OrderStatusProcessing, models.OrderStatusShipped)
+	tr := &Transition{Order: &models.Order{ID: "ord_1"}, From: rule.From, To: rule.To}
+
+	if err := m.RunHooks(context.Background(), rule, HookInTx, tr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != HookPublishStatusChanged {
+		t.Fatalf("Expected only the event hook in the transaction, got %v", calls)
+	}
+
+	if err := m.RunHooks(context.Background(), rule, HookAfterCommit, tr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(calls[1:], ",") != HookInvalidateCache+","+HookNotifyShipped {
+		t.Errorf("Unexpected after-commit hooks %v", calls[1:])
+	}
+}
+
+func TestStateMachine_Diagrams(t *testing.T) {
+	m := newTestStateMachine(new([]string))
+
+	dot := m.DOT()
+	if !strings.Contains(dot, `"pending" -> "confirmed" [label="[payment_captured]"];`) {
+		t.Errorf("DOT output missing guarded edge:\n%s", dot)
+	}
+
+	mermaid := m.Mermaid()
+	for _, want := range []string{"[*] --> pending", "shipped --> delivered", "cancelled --> [*]"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestOrderLifecycle_RejectsUnknownHook(t *testing.T) {
+	lifecycle := &OrderLifecycle{
+		Initial: models.OrderStatusPending,
+		Transitions: []TransitionRule{
+			{From: models.OrderStatusPending, To: models.OrderStatusConfirmed, Hooks: []string{"send_fax"}},
+		},
+	}
+	if err := lifecycle.Validate(); err == nil {
+		t.Error("Expected unknown hook to be rejected")
+	}
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+// TaxCalculator computes the tax owed on an order.
+type TaxCalculator interface {
+	Calculate(ctx context.Context, req *TaxRequest) (*TaxResult, error)
+}
+
+// TaxRequest describes a priced order for tax purposes.
+type TaxRequest struct {
+	UserID   string
+	Address  models.Address
+	Currency string
+	// Items must already carry their server-computed line totals.
+	Items []models.OrderItem
+}
+
+// TaxResult is the outcome of a tax calculation.
+type TaxResult struct {
+	Tax models.Money
+	// LineTaxes holds each item's share of Tax, in request order.
+	LineTaxes []models.Money
+	// Inclusive is true when item prices already contain the tax (VAT), in
+	// which case Tax must not be added to the order total.
+	Inclusive bool
+	Exempt    bool
+	Lines     []TaxLine
+}
+
+// TaxLine is one row of an order's tax breakdown: a single rate applied to
+// the items of one tax category.
+type TaxLine struct {
+	Jurisdiction string       `json:"jurisdiction"`
+	Name         string       `json:"name"`
+	Category     string       `json:"category"`
+	Rate         Rate         `json:"rate_ppm"`
+	Inclusive    bool         `json:"inclusive"`
+	Taxable      models.Money `json:"taxable_amount"`
+	Tax          models.Money `json:"tax_amount"`
+	RulesVersion string       `json:"rules_version"`
+}
+
+// TaxRules is the versioned rules file read by RulesTaxCalculator.
+type TaxRules struct {
+	Version         string `json:"version"`
+	DefaultCategory string `json:"default_category"`
+	// ProductCategories maps product IDs to tax categories. Products not
+	// listed use DefaultCategory.
+	ProductCategories map[string]string `json:"product_categories"`
+	ExemptUsers       []string          `json:"exempt_users"`
+	Jurisdictions     []TaxJurisdiction `json:"jurisdictions"`
+}
+
+// TaxJurisdiction holds the rates for a country, optionally narrowed to a
+// region and postal code prefixes. The most specific match wins.
+type TaxJurisdiction struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Country        string            `json:"country"`
+	Region         string            `json:"region,omitempty"`
+	PostalPrefixes []string          `json:"postal_prefixes,omitempty"`
+	Inclusive      bool              `json:"inclusive"`
+	Rates          map[string]string `json:"rates"`
+
+	rates map[string]Rate
+}
+
+// LoadTaxRules reads and validates a tax rules file.
+func LoadTaxRules(path string) (*TaxRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tax rules: %w", err)
+	}
+	return ParseTaxRules(data)
+}
+
+// ParseTaxRules parses and validates tax rules.
+func ParseTaxRules(data []byte) (*TaxRules, error) {
+	var rules TaxRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse tax rules: %w", err)
+	}
+
+	if rules.Version == "" {
+		return nil, fmt.Errorf("tax rules: version is required")
+	}
+	if rules.DefaultCategory == "" {
+		return nil, fmt.Errorf("tax rules: default_category is required")
+	}
+
+	for i := range rules.Jurisdictions {
+		j := &rules.Jurisdictions[i]
+		if j.ID == "" || j.Country == "" {
+			return nil, fmt.Errorf("tax rules: jurisdiction %d needs an id and country", i)
+		}
+		if _, ok := j.Rates[rules.DefaultCategory]; !ok {
+			return nil, fmt.Errorf("tax rules: jurisdiction %s has no %q rate", j.ID, rules.DefaultCategory)
+		}
+
+		j.rates = make(map[string]Rate, len(j.Rates))
+		for category, value := range j.Rates {
+			rate, err := ParseRate(value)
+			if err != nil {
+				return nil, fmt.Errorf("tax rules: jurisdiction %s category %s: %w", j.ID, category, err)
+			}
+			j.rates[category] = rate
+		}
+	}
+
+	return &rules, nil
+}
+
+// ParseRate parses a decimal fraction such as "0.0725" into a Rate without
+// going through floating point.
+func ParseRate(s string) (Rate, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if len(frac) > 6 {
+		return 0, fmt.Errorf("rate %q has more than 6 decimal places", s)
+	}
+
+	ppm, err := strconv.ParseInt(whole+frac+strings.Repeat("0", 6-len(frac)), 10, 64)
+	if err != nil || ppm < 0 {
+		return 0, fmt.Errorf("invalid rate %q", s)
+	}
+	return Rate(ppm), nil
+}
+
+// RulesTaxCalculator implements TaxCalculator from a TaxRules file.
+type RulesTaxCalculator struct {
+	rules    *TaxRules
+	rounding RoundingMode
+	exempt   map[string]bool
+}
+
+// NewRulesTaxCalculator creates a calculator that rounds each category's
+// tax with rounding.
+func NewRulesTaxCalculator(rules *TaxRules, rounding RoundingMode) *RulesTaxCalculator {
+	exempt := make(map[string]bool, len(rules.ExemptUsers))
+	for _, userID := range rules.ExemptUsers {
+		exempt[userID] = true
+	}
+
+	return &RulesTaxCalculator{
+		rules:    rules,
+		rounding: rounding,
+		exempt:   exempt,
+	}
+}
+
+// Calculate computes tax per category for the jurisdiction of req.Address.
+// Tax is rounded once per category and then spread across that category's
+// items, so line shares always add up to the category tax.
+func (c *RulesTaxCalculator) Calculate(ctx context.Context, req *TaxRequest) (*TaxResult, error) {
+	result := &TaxResult{
+		Tax:       models.Money{Currency: req.Currency},
+		LineTaxes: make([]models.Money, len(req.Items)),
+	}
+	for i := range result.LineTaxes {
+		result.LineTaxes[i] = models.Money{Currency: req.Currency}
+	}
+
+	if c.exempt[req.UserID] {
+		result.Exempt = true
+		return result, nil
+	}
+
+	jurisdiction := c.match(req.Address)
+	if jurisdiction == nil {
+		return nil, errors.NewValidationError("shipping_address",
+			fmt.Sprintf("no tax jurisdiction configured for %s %s", req.Address.Country, req.Address.State))
+	}
+	result.Inclusive = jurisdiction.Inclusive
+
+	// Group items by tax category.
+	groups := make(map[string][]int)
+	for i, item := range req.Items {
+		category := c.category(item.ProductID)
+		if _, ok := jurisdiction.rates[category]; !ok {
+			category = c.rules.DefaultCategory
+		}
+		groups[category] = append(groups[category], i)
+	}
+
+	categories := make([]string, 0, len(groups))
+	for category := range groups {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		indexes := groups[category]
+		rate := jurisdiction.rates[category]
+
+		amounts := make([]int64, len(indexes))
+		var taxable int64
+		for n, i := range indexes {
+			amounts[n] = req.Items[i].Total.Amount
+			taxable += amounts[n]
+		}
+
+		var tax int64
+		if jurisdiction.Inclusive {
+			// Extract the tax already contained in the gross price.
+			tax = divRound(taxable*int64(rate), RateScale+int64(rate), c.rounding)
+			taxable -= tax
+		} else {
+			tax = rate.Apply(taxable, c.rounding)
+		}
+
+		for n, share := range Allocate(tax, amounts) {
+			result.LineTaxes[indexes[n]].Amount = share
+		}
+		result.Tax.Amount += tax
+
+		result.Lines = append(result.Lines, TaxLine{
+			Jurisdiction: jurisdiction.ID,
+			Name:         jurisdiction.Name,
+			Category:     category,
+			Rate:         rate,
+			Inclusive:    jurisdiction.Inclusive,
+			Taxable:      models.Money{Amount: taxable, Currency: req.Currency},
+			Tax:          models.Money{Amount: tax, Currency: req.Currency},
+			RulesVersion: c.rules.Version,
+		})
+	}
+
+	return result, nil
+}
+
+func (c *RulesTaxCalculator) category(productID string) string {
+	if category, ok := c.rules.ProductCategories[productID]; ok {
+		return category
+	}
+	return c.rules.DefaultCategory
+}
+
+// match returns the most specific jurisdiction for addr: postal prefix beats
+// region, which beats country. Ties go to the earlier entry in the file.
+func (c *RulesTaxCalculator) match(addr models.Address) *TaxJurisdiction {
+	var best *TaxJurisdiction
+	bestScore := 0
+
+	for i := range c.rules.Jurisdictions {
+		j := &c.rules.Jurisdictions[i]
+		if !strings.EqualFold(j.Country, addr.Country) {
+			continue
+		}
+
+		score := 1
+		if j.Region != "" {
+			if !strings.EqualFold(j.Region, addr.State) {
+				continue
+			}
+			score += 2
+		}
+		if len(j.PostalPrefixes) > 0 {
+			if !hasAnyPrefix(addr.PostalCode, j.PostalPrefixes) {
+				continue
+			}
+			score += 4
+		}
+
+		if score > bestScore {
+			best, bestScore = j, score
+		}
+	}
+
+	return best
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, strings.ToUpper(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func orderTaxLines(orderID string, lines []TaxLine) []*repository.OrderTaxLine {
+	stored := make([]*repository.OrderTaxLine, len(lines))
+	for i, line := range lines {
+		stored[i] = &repository.OrderTaxLine{
+			OrderID:      orderID,
+			Jurisdiction: line.Jurisdiction,
+			Name:         line.Name,
+			Category:     line.Category,
+			RatePPM:      int64(line.Rate),
+			Inclusive:    line.Inclusive,
+			Taxable:      line.Taxable,
+			Tax:          line.Tax,
+			RulesVersion: line.RulesVersion,
+		}
+	}
+	return stored
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+const testTaxRules = `{
+  "version": "test-1",
+  "default_category": "standard",
+  "product_categories": {"prod_bread": "groceries"},
+  "exempt_users": ["user_charity"],
+  "jurisdictions": [
+    {"id": "US", "country": "US", "rates": {"standard": "0.05"}},
+    {"id": "US-NY", "country": "US", "region": "NY", "rates": {"standard": "0.04", "groceries": "0"}},
+    {"id": "US-NY-NYC", "country": "US", "region": "NY", "postal_prefixes": ["100"], "rates": {"standard": "0.08875", "groceries": "0"}},
+    {"id": "GB", "country": "GB", "inclusive": true, "rates": {"standard": "0.20", "groceries": "0"}}
+  ]
+}`
+
+func newTestTaxCalculator(t *testing.T) *RulesTaxCalculator {
+	t.Helper()
+	rules, err := ParseTaxRules([]byte(testTaxRules))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+	return NewRulesTaxCalculator(rules, RoundHalfUp)
+}
+
+func taxRequest(userID string, addr models.Address, items ...models.OrderItem) *TaxRequest {
+	return &TaxRequest{UserID: userID, Address: addr, Currency: "USD", Items: items}
+}
+
+func taxItem(productID string, amount int64) models.OrderItem {
+	return models.OrderItem{ProductID: productID, Quantity: 1, Total: models.Money{Amount: amount, Currency: "USD"}}
+}
+
+func TestRulesTaxCalculator_MostSpecificJurisdiction(t *testing.T) {
+	calc := newTestTaxCalculator(t)
+
+	tests := []struct {
+		name         string
+		addr         models.Address
+		jurisdiction string
+		tax          int64
+	}{
+		{"country", models.Address{Country: "US", State: "TX", PostalCode: "73301"}, "US", 500},
+		{"region", models.Address{Country: "US", State: "NY", PostalCode: "14201"}, "US-NY", 400},
+		{"postal prefix", models.Address{Country: "US", State: "NY", PostalCode: "10001"}, "US-NY-NYC", 888},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(context.Background(), taxRequest("user_1", tt.addr, taxItem("prod_a", 10000)))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Lines[0].Jurisdiction != tt.jurisdiction {
+				t.Errorf("Expected jurisdiction %s, got %s", tt.jurisdiction, result.Lines[0].Jurisdiction)
+			}
+			if result.Tax.Amount != tt.tax {
+				t.Errorf("Expected tax %d, got %d", tt.tax, result.Tax.Amount)
+			}
+		})
+	}
+}
+
+func TestRulesTaxCalculator_Categories(t *testing.T) {
+	calc := newTestTaxCalculator(t)
+	addr := models.Address{Country: "US", State: "NY", PostalCode: "10001"}
+
+	result, err := calc.Calculate(context.Background(), taxRequest("user_1", addr,
+		taxItem("prod_a", 10000),
+		taxItem("prod_bread", 500),
+	))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Lines) != 2 {
+		t.Fatalf("Expected a breakdown line per category, got %d", len(result.Lines))
+	}
+	if result.LineTaxes[1].Amount != 0 {
+		t.Errorf("Expected groceries to be untaxed, got %d", result.LineTaxes[1].Amount)
+	}
+	if result.Tax.Amount != 888 {
+		t.Errorf("Expected tax 888, got %d", result.Tax.Amount)
+	}
+}
+
+func TestRulesTaxCalculator_InclusiveVAT(t *testing.T) {
+	calc := newTestTaxCalculator(t)
+
+	result, err := calc.Calculate(context.Background(), taxRequest("user_1", models.Address{Country: "GB"}, taxItem("prod_a", 1200)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.Inclusive {
+		t.Error("Expected VAT to be tax-inclusive")
+	}
+	if result.Tax.Amount != 200 {
+		t.Errorf("Expected embedded VAT 200, got %d", result.Tax.Amount)
+	}
+	if result.Lines[0].Taxable.Amount != 1000 {
+		t.Errorf("Expected net taxable amount 1000, got %d", result.Lines[0].Taxable.Amount)
+	}
+}
+
+func TestRulesTaxCalculator_ExemptUser(t *testing.T) {
+	calc := newTestTaxCalculator(t)
+
+	result, err := calc.Calculate(context.Background(), taxRequest("user_charity", models.Address{Country: "US"}, taxItem("prod_a", 10000)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Exempt || result.Tax.Amount != 0 {
+		t.Errorf("Expected exempt user to pay no tax, got %+v", result)
+	}
+}
+
+func TestRulesTaxCalculator_UnknownJurisdiction(t *testing.T) {
+	calc := newTestTaxCalculator(t)
+
+	if _, err := calc.Calculate(context.Background(), taxRequest("user_1", models.Address{Country: "ZZ"}, taxItem("prod_a", 100))); err == nil {
+		t.Error("Expected error for an address without a jurisdiction")
+	}
+}
+
+func TestLoadTaxRules_ShippedFile(t *testing.T) {
+	if _, err := LoadTaxRules("../../configs/tax_rules.json"); err != nil {
+		t.Fatalf("Shipped tax rules do not load: %v", err)
+	}
+}
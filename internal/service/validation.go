@@ -1,8 +1,10 @@
 package service
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
 	"github.com/tm-acme-shop/acme-shop-shared-go/errors"
 	"github.com/tm-acme-shop/acme-shop-shared-go/logging"
 	"github.com/tm-acme-shop/acme-shop-shared-go/models"
@@ -130,6 +132,139 @@ func ValidateOrderListFilter(filter *models.OrderListFilter) error {
 	return nil
 }
 
+// ValidateOrderQuery validates an order listing query and applies the
+// default and maximum page size.
+func ValidateOrderQuery(q *repository.OrderQuery) error {
+	if q.Limit < 0 {
+		return errors.NewValidationError("limit", "limit cannot be negative")
+	}
+	if q.Limit == 0 {
+		q.Limit = 20
+	}
+	if q.Limit > 100 {
+		// TODO(TEAM-API): Make max limit configurable
+		q.Limit = 100
+	}
+
+	if q.Offset < 0 {
+		return errors.NewValidationError("offset", "offset cannot be negative")
+	}
+	if q.Offset > 0 && q.Cursor != "" {
+		return errors.NewValidationError("offset", "offset cannot be combined with cursor")
+	}
+
+	for _, status := range q.Statuses {
+		if !IsKnownOrderStatus(status) {
+			return errors.NewValidationError("status", "invalid order status: "+string(status))
+		}
+	}
+
+	if q.CreatedFrom != nil && q.CreatedTo != nil && q.CreatedFrom.After(*q.CreatedTo) {
+		return errors.NewValidationError("created_from", "created_from cannot be after created_to")
+	}
+
+	if q.MinTotal != nil && q.MaxTotal != nil && *q.MinTotal > *q.MaxTotal {
+		return errors.NewValidationError("min_total", "min_total cannot be greater than max_total")
+	}
+
+	if q.Sort != "" && !repository.ValidOrderSort(q.Sort) {
+		return errors.NewValidationError("sort", "unsupported sort order: "+string(q.Sort))
+	}
+
+	return nil
+}
+
+// ValidateCreateShipmentRequest validates a shipment creation request.
+// Quantities are checked against the order when the shipment is created.
+func ValidateCreateShipmentRequest(req *CreateShipmentRequest) error {
+	if strings.TrimSpace(req.Warehouse) == "" {
+		return errors.NewValidationError("warehouse", "warehouse is required")
+	}
+
+	if strings.TrimSpace(req.Carrier) == "" {
+		return errors.NewValidationError("carrier", "carrier is required")
+	}
+
+	switch req.Status {
+	case "", repository.ShipmentStatusPending, repository.ShipmentStatusShipped:
+	default:
+		return errors.NewValidationError("status", "a new shipment must be pending or shipped")
+	}
+
+	if len(req.Items) == 0 {
+		return errors.NewValidationError("items", "at least one item is required")
+	}
+
+	return nil
+}
+
+// ValidateUpdateShipmentStatusRequest validates a shipment status update.
+func ValidateUpdateShipmentStatusRequest(req *UpdateShipmentStatusRequest) error {
+	switch req.Status {
+	case repository.ShipmentStatusShipped,
+		repository.ShipmentStatusDelivered,
+		repository.ShipmentStatusCancelled:
+		return nil
+	case "":
+		return errors.NewValidationError("status", "status is required")
+	default:
+		return errors.NewValidationError("status", "invalid shipment status")
+	}
+}
+
+// ValidateRefundOrderRequest validates an order refund request. Quantities
+// are checked against the order and its earlier refunds when the refund is
+// planned.
+func ValidateRefundOrderRequest(req *RefundOrderRequest) error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return errors.NewValidationError("reason", "reason is required")
+	}
+
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if item.ItemID == "" {
+			return errors.NewValidationError(field, "item_id is required")
+		}
+		if item.Quantity <= 0 {
+			return errors.NewValidationError(field, "quantity must be positive")
+		}
+	}
+
+	return nil
+}
+
+// ValidateCreateReturnRequest validates a return request. Quantities are
+// checked against the order when the return is created.
+func ValidateCreateReturnRequest(req *CreateReturnRequest) error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return errors.NewValidationError("reason", "reason is required")
+	}
+
+	if len(req.Items) == 0 {
+		return errors.NewValidationError("items", "at least one item is required")
+	}
+
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if item.ItemID == "" {
+			return errors.NewValidationError(field, "item_id is required")
+		}
+		if item.Quantity <= 0 {
+			return errors.NewValidationError(field, "quantity must be positive")
+		}
+	}
+
+	return nil
+}
+
+// ValidateRejectReturnRequest validates a return rejection.
+func ValidateRejectReturnRequest(req *RejectReturnRequest) error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return errors.NewValidationError("reason", "reason is required")
+	}
+	return nil
+}
+
 // ValidatePaymentRequest validates a payment request.
 func ValidatePaymentRequest(req *models.ProcessPaymentRequest) error {
 	if req.OrderID == "" {
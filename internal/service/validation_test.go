@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+	"github.com/tm-acme-shop/acme-shop-shared-go/models"
+)
+
+func TestValidateOrderQueryStatuses(t *testing.T) {
+	for status := range knownOrderStatuses {
+		q := &repository.OrderQuery{Statuses: []models.OrderStatus{status}}
+		if err := ValidateOrderQuery(q); err != nil {
+			t.Errorf("expected %s to be accepted, got %v", status, err)
+		}
+	}
+
+	q := &repository.OrderQuery{Statuses: []models.OrderStatus{"lost"}}
+	if err := ValidateOrderQuery(q); err == nil {
+		t.Error("expected an unknown status to be rejected")
+	}
+}
+
+func TestKnownOrderStatusesCoverLifecycle(t *testing.T) {
+	for _, rule := range DefaultOrderLifecycle().Transitions {
+		for _, status := range []models.OrderStatus{rule.From, rule.To} {
+			if !IsKnownOrderStatus(status) {
+				t.Errorf("state machine status %s in the default lifecycle is missing from knownOrderStatuses", status)
+			}
+		}
+	}
+}
+
+func TestValidateOrderQueryOffset(t *testing.T) {
+	tests := []struct {
+		name  string
+		q     repository.OrderQuery
+		valid bool
+	}{
+		{"offset", repository.OrderQuery{Offset: 40}, true},
+		{"cursor", repository.OrderQuery{Cursor: "abc"}, true},
+		{"negative offset", repository.OrderQuery{Offset: -1}, false},
+		{"offset with cursor", repository.OrderQuery{Offset: 20, Cursor: "abc"}, false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateOrderQuery(&tt.q); (err == nil) != tt.valid {
+			t.Errorf("%s: got %v, want valid=%v", tt.name, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/repository"
+)
+
+// PreconditionFailedError is returned when a caller asked to modify a
+// specific order version (If-Match) and the order has moved on since.
+type PreconditionFailedError struct {
+	Expected int64
+	Current  int64
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return fmt.Sprintf("order is at version %d, expected %d", e.Current, e.Expected)
+}
+
+// checkVersion verifies that order is at expected, unless expected is
+// repository.AnyVersion.
+func checkVersion(order *repository.VersionedOrder, expected int64) error {
+	if expected != repository.AnyVersion && order.Version != expected {
+		return &PreconditionFailedError{Expected: expected, Current: order.Version}
+	}
+	return nil
+}
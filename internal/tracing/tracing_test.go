@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tm-acme-shop/acme-shop-orders-service/internal/config"
+)
+
+func TestInit(t *testing.T) {
+	for _, exporter := range []string{"none", "stdout"} {
+		shutdown, err := Init(context.Background(), config.TracingConfig{Exporter: exporter, SampleRatio: 1})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", exporter, err)
+		}
+		if err := shutdown(context.Background()); err != nil {
+			t.Errorf("%s: unexpected shutdown error: %v", exporter, err)
+		}
+	}
+
+	if _, err := Init(context.Background(), config.TracingConfig{Exporter: "zipkin"}); err == nil {
+		t.Error("expected an error for an unknown exporter")
+	}
+}
@@ -0,0 +1,16 @@
+-- Idempotency keys for retried POST requests (Idempotency-Key header).
+-- A row is inserted when a request first acquires a key and completed with the
+-- response once the handler has finished; retries replay the stored response.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    scope           VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64)     NOT NULL,
+    status_code     INTEGER,
+    response_body   BYTEA,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    completed_at    TIMESTAMPTZ,
+    expires_at      TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (idempotency_key, scope)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
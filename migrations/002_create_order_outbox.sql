@@ -0,0 +1,20 @@
+-- Transactional outbox for order events. Rows are written in the same
+-- transaction as the order change and relayed to Kafka by the outbox relay.
+CREATE TABLE IF NOT EXISTS order_outbox (
+    id              BIGSERIAL    PRIMARY KEY,
+    event_id        VARCHAR(64)  NOT NULL UNIQUE,
+    event_type      VARCHAR(64)  NOT NULL,
+    aggregate_id    VARCHAR(64)  NOT NULL,
+    payload         JSONB        NOT NULL,
+    status          VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    next_attempt_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    sent_at         TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_outbox_pending
+    ON order_outbox (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_order_outbox_aggregate
+    ON order_outbox (aggregate_id, id) WHERE status = 'pending';
@@ -0,0 +1,11 @@
+-- Inbox of external events that have already been applied, keyed by the
+-- producer's event ID and the consumer that applied it. Used to make
+-- redelivered payment webhooks harmless.
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id     VARCHAR(255) NOT NULL,
+    consumer     VARCHAR(64)  NOT NULL,
+    processed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, consumer)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events (processed_at);
@@ -0,0 +1,18 @@
+-- Tax breakdown applied to each order: one row per jurisdiction and tax
+-- category, recorded with the version of the rules file that produced it.
+CREATE TABLE IF NOT EXISTS order_tax_lines (
+    id              BIGSERIAL    PRIMARY KEY,
+    order_id        VARCHAR(64)  NOT NULL,
+    jurisdiction    VARCHAR(64)  NOT NULL,
+    name            VARCHAR(255) NOT NULL DEFAULT '',
+    category        VARCHAR(64)  NOT NULL,
+    rate_ppm        BIGINT       NOT NULL,
+    inclusive       BOOLEAN      NOT NULL DEFAULT FALSE,
+    taxable_amount  BIGINT       NOT NULL,
+    tax_amount      BIGINT       NOT NULL,
+    currency        VARCHAR(3)   NOT NULL,
+    rules_version   VARCHAR(64)  NOT NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_tax_lines_order_id ON order_tax_lines (order_id);
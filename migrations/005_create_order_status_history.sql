@@ -0,0 +1,15 @@
+-- Audit trail of order status changes. One row per transition, including
+-- order creation (from_status is NULL).
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id           BIGSERIAL    PRIMARY KEY,
+    order_id     VARCHAR(64)  NOT NULL,
+    from_status  VARCHAR(32),
+    to_status    VARCHAR(32)  NOT NULL,
+    actor        VARCHAR(255) NOT NULL,
+    reason       TEXT         NOT NULL DEFAULT '',
+    source       VARCHAR(32)  NOT NULL,
+    request_id   VARCHAR(255) NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history (order_id, id);
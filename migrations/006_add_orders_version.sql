@@ -0,0 +1,4 @@
+-- Optimistic concurrency for orders. Every mutation increments version and
+-- only applies while the row is still at the version the caller read; the
+-- version is exposed to API clients as the order's ETag.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
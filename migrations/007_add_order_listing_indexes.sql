@@ -0,0 +1,10 @@
+-- Indexes backing keyset pagination and filtering of GET /api/v2/orders.
+-- Each sort order pages on (column, id) so the index serves both the ORDER BY
+-- and the cursor comparison.
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_id ON orders (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at_id ON orders (updated_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_total_amount_id ON orders (total_amount, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_user_id_created_at ON orders (user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_payment_id ON orders (payment_id) WHERE payment_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_shipping_country ON orders ((upper(shipping_address->>'country')));
+CREATE INDEX IF NOT EXISTS idx_orders_items ON orders USING GIN (items jsonb_path_ops);
@@ -0,0 +1,18 @@
+-- Shipments fulfil an order in parts, possibly from several warehouses.
+-- items holds [{"item_id", "product_id", "quantity"}] referencing order lines.
+CREATE TABLE IF NOT EXISTS shipments (
+    id               VARCHAR(64)  PRIMARY KEY,
+    order_id         VARCHAR(64)  NOT NULL,
+    warehouse        VARCHAR(64)  NOT NULL,
+    carrier          VARCHAR(64)  NOT NULL,
+    tracking_number  VARCHAR(128) NOT NULL DEFAULT '',
+    status           VARCHAR(32)  NOT NULL,
+    items            JSONB        NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    shipped_at       TIMESTAMPTZ,
+    delivered_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments (order_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_shipments_tracking_number ON shipments (carrier, tracking_number) WHERE tracking_number <> '';
@@ -0,0 +1,22 @@
+-- Refunds of an order's captured payment, possibly several per order.
+-- items holds [{"item_id", "product_id", "quantity", "amount", "tax"}]
+-- referencing order lines; amount is the total refunded to the customer.
+-- Pending refunds count against the refundable balance until they settle.
+CREATE TABLE IF NOT EXISTS order_refunds (
+    id                VARCHAR(64)  PRIMARY KEY,
+    order_id          VARCHAR(64)  NOT NULL,
+    payment_id        VARCHAR(64)  NOT NULL,
+    refund_id         VARCHAR(64)  NOT NULL DEFAULT '',
+    status            VARCHAR(32)  NOT NULL,
+    reason            TEXT         NOT NULL,
+    items             JSONB        NOT NULL,
+    subtotal_amount   BIGINT       NOT NULL,
+    tax_amount        BIGINT       NOT NULL,
+    shipping_amount   BIGINT       NOT NULL,
+    amount            BIGINT       NOT NULL,
+    currency          VARCHAR(3)   NOT NULL,
+    created_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_refunds_order_id ON order_refunds (order_id, created_at);
@@ -0,0 +1,25 @@
+-- Return merchandise authorisations. A return moves
+-- requested → approved → received → inspected → refunded, and can be rejected
+-- before it is refunded. items holds
+-- [{"item_id", "product_id", "quantity", "received_quantity", "accepted_quantity"}]
+-- referencing order lines; refund_id points at the order_refunds row it paid out.
+CREATE TABLE IF NOT EXISTS returns (
+    id            VARCHAR(64)  PRIMARY KEY,
+    order_id      VARCHAR(64)  NOT NULL,
+    user_id       VARCHAR(64)  NOT NULL,
+    status        VARCHAR(32)  NOT NULL,
+    reason        TEXT         NOT NULL,
+    notes         TEXT         NOT NULL DEFAULT '',
+    items         JSONB        NOT NULL,
+    refund_id     VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    approved_at   TIMESTAMPTZ,
+    received_at   TIMESTAMPTZ,
+    inspected_at  TIMESTAMPTZ,
+    closed_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_returns_order_id ON returns (order_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_returns_open ON returns (status, created_at)
+    WHERE status NOT IN ('refunded', 'rejected');
@@ -0,0 +1,3 @@
+-- Payment webhooks and events report refund outcomes by the payment
+-- service's refund ID, so pending refunds are looked up by it to settle.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_refunds_refund_id ON order_refunds (refund_id) WHERE refund_id <> '';
@@ -0,0 +1,11 @@
+-- Payment status recorded on each order, so the lifecycle can require a
+-- captured payment rather than just an attached one. Orders that already
+-- moved past pending with a payment attached were confirmed by a captured
+-- payment.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_status VARCHAR(32) NOT NULL DEFAULT '';
+
+UPDATE orders
+SET payment_status = 'completed'
+WHERE payment_status = ''
+  AND payment_id IS NOT NULL AND payment_id <> ''
+  AND status NOT IN ('pending', 'cancelled');
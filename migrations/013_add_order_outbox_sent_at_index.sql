@@ -0,0 +1,3 @@
+-- Delivered outbox messages are pruned by delivery time once they are past
+-- their retention.
+CREATE INDEX IF NOT EXISTS idx_order_outbox_sent_at ON order_outbox (sent_at) WHERE status = 'sent';